@@ -0,0 +1,9 @@
+-- Rango objetivo de series efectivas por semana para cada grupo muscular.
+-- Se usa para marcar los grupos subentrenados o sobreentrenados.
+ALTER TABLE public.muscle_groups
+ADD COLUMN IF NOT EXISTS target_min_sets INTEGER NOT NULL DEFAULT 10,
+ADD COLUMN IF NOT EXISTS target_max_sets INTEGER NOT NULL DEFAULT 20;
+
+ALTER TABLE public.muscle_groups DROP CONSTRAINT IF EXISTS muscle_groups_target_sets_check;
+ALTER TABLE public.muscle_groups
+ADD CONSTRAINT muscle_groups_target_sets_check CHECK (target_min_sets >= 0 AND target_max_sets >= target_min_sets AND target_max_sets <= 100);
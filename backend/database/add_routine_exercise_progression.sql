@@ -0,0 +1,32 @@
+-- Parámetros de progresión de cada ejercicio de rutina, usados para sugerir el peso y las
+-- repeticiones de la próxima sesión. Los valores NULL usan los valores por defecto.
+ALTER TABLE public.routine_exercises
+ADD COLUMN IF NOT EXISTS progression_rule TEXT,
+ADD COLUMN IF NOT EXISTS rep_range_min INTEGER,
+ADD COLUMN IF NOT EXISTS rep_range_max INTEGER,
+ADD COLUMN IF NOT EXISTS weight_increment NUMERIC(6,2),
+ADD COLUMN IF NOT EXISTS deload_after_misses INTEGER,
+ADD COLUMN IF NOT EXISTS deload_percent NUMERIC(5,2);
+
+ALTER TABLE public.routine_exercises DROP CONSTRAINT IF EXISTS routine_exercises_progression_rule_check;
+ALTER TABLE public.routine_exercises
+ADD CONSTRAINT routine_exercises_progression_rule_check CHECK (progression_rule IS NULL OR progression_rule IN ('double_progression', 'linear'));
+
+ALTER TABLE public.routine_exercises DROP CONSTRAINT IF EXISTS routine_exercises_rep_range_check;
+ALTER TABLE public.routine_exercises
+ADD CONSTRAINT routine_exercises_rep_range_check CHECK (
+    (rep_range_min IS NULL OR rep_range_min BETWEEN 1 AND 100)
+    AND (rep_range_max IS NULL OR rep_range_max BETWEEN 1 AND 100)
+    AND (rep_range_min IS NULL OR rep_range_max IS NULL OR rep_range_min <= rep_range_max)
+);
+
+ALTER TABLE public.routine_exercises DROP CONSTRAINT IF EXISTS routine_exercises_weight_increment_check;
+ALTER TABLE public.routine_exercises
+ADD CONSTRAINT routine_exercises_weight_increment_check CHECK (weight_increment IS NULL OR (weight_increment > 0 AND weight_increment <= 50));
+
+ALTER TABLE public.routine_exercises DROP CONSTRAINT IF EXISTS routine_exercises_deload_check;
+ALTER TABLE public.routine_exercises
+ADD CONSTRAINT routine_exercises_deload_check CHECK (
+    (deload_after_misses IS NULL OR deload_after_misses BETWEEN 0 AND 10)
+    AND (deload_percent IS NULL OR (deload_percent > 0 AND deload_percent <= 50))
+);
@@ -0,0 +1,31 @@
+-- Papelera: las series, los días de entrenamiento y las rutinas eliminadas quedan marcadas
+-- con deleted_at y se pueden restaurar hasta que el purgado las borra (30 días).
+ALTER TABLE public.workouts
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE public.workout_days
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE public.user_routines
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Un día en la papelera no impide crear otro con la misma fecha
+ALTER TABLE public.workout_days
+DROP CONSTRAINT IF EXISTS workout_days_user_id_date_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workout_days_user_date_live
+ON public.workout_days(user_id, date)
+WHERE deleted_at IS NULL;
+
+-- Para listar la papelera y purgar lo vencido
+CREATE INDEX IF NOT EXISTS idx_workouts_deleted_at
+ON public.workouts(deleted_at)
+WHERE deleted_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_workout_days_deleted_at
+ON public.workout_days(deleted_at)
+WHERE deleted_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_user_routines_deleted_at
+ON public.user_routines(deleted_at)
+WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,8 @@
+-- Zona horaria por usuario (IANA). Se usa para calcular el "hoy" de cada
+-- usuario al registrar entrenamientos y para mostrar fechas.
+ALTER TABLE public.user_settings
+ADD COLUMN IF NOT EXISTS timezone TEXT DEFAULT 'America/Argentina/Buenos_Aires';
+
+UPDATE public.user_settings
+SET timezone = 'America/Argentina/Buenos_Aires'
+WHERE timezone IS NULL;
@@ -0,0 +1,11 @@
+-- Unidad de peso preferida por usuario (kg o lb). Los pesos se guardan siempre
+-- en kilos (workouts.weight, routine_exercises.weight, personal_records) y se
+-- convierten a la unidad de cada usuario al responder.
+ALTER TABLE public.user_settings
+ADD COLUMN IF NOT EXISTS weight_unit TEXT NOT NULL DEFAULT 'kg';
+
+ALTER TABLE public.user_settings
+DROP CONSTRAINT IF EXISTS user_settings_weight_unit_check;
+
+ALTER TABLE public.user_settings
+ADD CONSTRAINT user_settings_weight_unit_check CHECK (weight_unit IN ('kg', 'lb'));
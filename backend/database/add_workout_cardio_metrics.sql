@@ -0,0 +1,32 @@
+-- Métricas de cardio y deportes para ejercicios is_sport. La duración sigue
+-- siendo workouts.seconds; el ritmo y la velocidad se calculan al leer.
+ALTER TABLE public.workouts
+ADD COLUMN IF NOT EXISTS distance_meters DOUBLE PRECISION,
+ADD COLUMN IF NOT EXISTS elevation_gain_meters DOUBLE PRECISION,
+ADD COLUMN IF NOT EXISTS avg_heart_rate INTEGER,
+ADD COLUMN IF NOT EXISTS max_heart_rate INTEGER,
+ADD COLUMN IF NOT EXISTS calories INTEGER;
+
+ALTER TABLE public.workouts DROP CONSTRAINT IF EXISTS workouts_distance_check;
+ALTER TABLE public.workouts
+ADD CONSTRAINT workouts_distance_check CHECK (distance_meters IS NULL OR distance_meters > 0);
+
+ALTER TABLE public.workouts DROP CONSTRAINT IF EXISTS workouts_heart_rate_check;
+ALTER TABLE public.workouts
+ADD CONSTRAINT workouts_heart_rate_check CHECK (
+    (avg_heart_rate IS NULL OR avg_heart_rate BETWEEN 30 AND 250) AND
+    (max_heart_rate IS NULL OR max_heart_rate BETWEEN 30 AND 250) AND
+    (avg_heart_rate IS NULL OR max_heart_rate IS NULL OR avg_heart_rate <= max_heart_rate)
+);
+
+-- Deportes que se miden por distancia (correr, bici, natación, remo, caminata).
+-- Solo en estos se acepta distancia y desnivel.
+ALTER TABLE public.exercises
+ADD COLUMN IF NOT EXISTS tracks_distance BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE public.exercises
+SET tracks_distance = true
+WHERE is_sport
+  AND (name ILIKE '%correr%' OR name ILIKE '%running%' OR name ILIKE '%trote%'
+    OR name ILIKE '%cicli%' OR name ILIKE '%bici%' OR name ILIKE '%nataci%'
+    OR name ILIKE '%remo%' OR name ILIKE '%caminata%' OR name ILIKE '%trekking%');
@@ -0,0 +1,3 @@
+-- Notas libres del día de entrenamiento (sensaciones, lesiones, contexto)
+ALTER TABLE public.workout_days
+ADD COLUMN IF NOT EXISTS notes TEXT;
@@ -0,0 +1,6 @@
+-- Rutina con la que se inició un día de entrenamiento. Permite comparar
+-- lo planificado en routine_exercises contra las series registradas.
+ALTER TABLE public.workout_days
+ADD COLUMN IF NOT EXISTS routine_id INTEGER REFERENCES public.user_routines(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_workout_days_routine_id ON public.workout_days(routine_id);
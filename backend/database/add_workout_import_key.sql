@@ -0,0 +1,8 @@
+-- Clave de importación de series traídas desde otras apps (Strong, Hevy).
+-- Permite volver a importar el mismo CSV sin duplicar series.
+ALTER TABLE public.workouts
+ADD COLUMN IF NOT EXISTS import_key TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workouts_user_import_key
+ON public.workouts(user_id, import_key)
+WHERE import_key IS NOT NULL;
@@ -0,0 +1,18 @@
+-- Tipo de serie, esfuerzo percibido (RPE) y repeticiones en reserva (RIR).
+-- Las series de calentamiento no cuentan para volumen, récords ni progreso.
+ALTER TABLE public.workouts
+ADD COLUMN IF NOT EXISTS set_type TEXT NOT NULL DEFAULT 'working',
+ADD COLUMN IF NOT EXISTS rpe NUMERIC(3,1),
+ADD COLUMN IF NOT EXISTS rir INTEGER;
+
+ALTER TABLE public.workouts DROP CONSTRAINT IF EXISTS workouts_set_type_check;
+ALTER TABLE public.workouts
+ADD CONSTRAINT workouts_set_type_check CHECK (set_type IN ('warmup', 'working', 'drop', 'failure', 'amrap'));
+
+ALTER TABLE public.workouts DROP CONSTRAINT IF EXISTS workouts_rpe_check;
+ALTER TABLE public.workouts
+ADD CONSTRAINT workouts_rpe_check CHECK (rpe IS NULL OR (rpe >= 1 AND rpe <= 10));
+
+ALTER TABLE public.workouts DROP CONSTRAINT IF EXISTS workouts_rir_check;
+ALTER TABLE public.workouts
+ADD CONSTRAINT workouts_rir_check CHECK (rir IS NULL OR (rir >= 0 AND rir <= 10));
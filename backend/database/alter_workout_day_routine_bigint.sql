@@ -0,0 +1,4 @@
+-- routine_id se creó como INTEGER pero user_routines.id es BIGINT: se iguala el tipo
+-- para que las rutinas con id grande puedan iniciar un día de entrenamiento.
+ALTER TABLE public.workout_days
+ALTER COLUMN routine_id TYPE BIGINT;
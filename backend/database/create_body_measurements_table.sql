@@ -0,0 +1,26 @@
+-- Medidas corporales: peso corporal (kg), porcentaje de grasa y contornos (cm).
+-- Hay como máximo una medida de cada tipo por día.
+CREATE TABLE IF NOT EXISTS public.body_measurements (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    measurement_type TEXT NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    measured_on DATE NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT body_measurements_pkey PRIMARY KEY (id),
+    CONSTRAINT body_measurements_user_type_date_key UNIQUE (user_id, measurement_type, measured_on),
+    CONSTRAINT body_measurements_type_check CHECK (measurement_type IN (
+        'bodyweight', 'body_fat', 'arm', 'waist', 'thigh', 'chest', 'hips'
+    )),
+    CONSTRAINT body_measurements_value_check CHECK (value > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_body_measurements_user_type_date
+    ON public.body_measurements(user_id, measurement_type, measured_on DESC);
+
+-- Peso corporal del usuario al registrar una serie de un ejercicio con peso corporal
+-- (dominadas, fondos). La carga de la serie es bodyweight_kg + weight (lastre).
+ALTER TABLE public.workouts
+ADD COLUMN IF NOT EXISTS bodyweight_kg DOUBLE PRECISION;
@@ -0,0 +1,20 @@
+-- Sesión en vivo: estado del entrenamiento en curso de cada usuario (ejercicio actual
+-- y temporizador de descanso). Se guarda en el servidor para retomarla desde cualquier
+-- dispositivo. Hay como máximo una por usuario y vence sola si queda sin actividad.
+CREATE TABLE IF NOT EXISTS public.live_sessions (
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    workout_day_id BIGINT NOT NULL REFERENCES public.workout_days(id) ON DELETE CASCADE,
+    current_exercise_id BIGINT REFERENCES public.exercises(id) ON DELETE SET NULL,
+    rest_started_at TIMESTAMP WITH TIME ZONE,
+    rest_duration_seconds INTEGER,
+    paused_at TIMESTAMP WITH TIME ZONE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT live_sessions_pkey PRIMARY KEY (user_id),
+    CONSTRAINT live_sessions_rest_check CHECK (rest_duration_seconds IS NULL OR (rest_duration_seconds >= 0 AND rest_duration_seconds <= 3600)),
+    CONSTRAINT live_sessions_pause_check CHECK (paused_at IS NULL OR rest_started_at IS NOT NULL)
+);
+
+-- Para purgar sesiones vencidas
+CREATE INDEX IF NOT EXISTS idx_live_sessions_updated_at
+    ON public.live_sessions(updated_at);
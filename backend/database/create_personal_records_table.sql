@@ -0,0 +1,25 @@
+-- Récords personales por ejercicio. Cada fila es un récord batido en un momento
+-- dado, por lo que la tabla guarda también el historial.
+CREATE TABLE IF NOT EXISTS public.personal_records (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    exercise_id BIGINT NOT NULL REFERENCES public.exercises(id) ON DELETE CASCADE,
+    record_type TEXT NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    weight DOUBLE PRECISION,
+    reps INTEGER,
+    previous_value DOUBLE PRECISION,
+    workout_id BIGINT REFERENCES public.workouts(id) ON DELETE CASCADE,
+    workout_day_id BIGINT REFERENCES public.workout_days(id) ON DELETE CASCADE,
+    achieved_on DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT personal_records_pkey PRIMARY KEY (id),
+    CONSTRAINT personal_records_type_check CHECK (record_type IN (
+        'max_weight', 'max_reps_at_weight', 'estimated_1rm', 'best_set_volume', 'best_session_volume'
+    ))
+);
+
+CREATE INDEX IF NOT EXISTS idx_personal_records_user_exercise
+    ON public.personal_records(user_id, exercise_id, record_type);
+CREATE INDEX IF NOT EXISTS idx_personal_records_workout_id
+    ON public.personal_records(workout_id);
@@ -0,0 +1,73 @@
+-- Programas de entrenamiento (mesociclos) creados por profesores: una semana tipo con
+-- rutinas ordenadas que se repite durante N semanas, con ajustes por semana.
+CREATE TABLE IF NOT EXISTS public.programs (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+    author_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT,
+    weeks INTEGER NOT NULL,
+    is_published BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT programs_pkey PRIMARY KEY (id),
+    CONSTRAINT programs_weeks_check CHECK (weeks BETWEEN 1 AND 52)
+);
+
+CREATE INDEX IF NOT EXISTS idx_programs_author_id ON public.programs(author_id);
+
+-- Días de la semana tipo, en orden. Cada día es una rutina del autor del programa.
+CREATE TABLE IF NOT EXISTS public.program_days (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+    program_id BIGINT NOT NULL REFERENCES public.programs(id) ON DELETE CASCADE,
+    day_number INTEGER NOT NULL,
+    routine_id BIGINT NOT NULL REFERENCES public.user_routines(id) ON DELETE CASCADE,
+    CONSTRAINT program_days_pkey PRIMARY KEY (id),
+    CONSTRAINT program_days_program_day_key UNIQUE (program_id, day_number),
+    CONSTRAINT program_days_day_number_check CHECK (day_number BETWEEN 1 AND 14)
+);
+
+-- Ajustes de una semana del programa sobre lo planificado en las rutinas.
+-- Las semanas sin fila usan las rutinas tal cual.
+CREATE TABLE IF NOT EXISTS public.program_weeks (
+    program_id BIGINT NOT NULL REFERENCES public.programs(id) ON DELETE CASCADE,
+    week_number INTEGER NOT NULL,
+    sets_adjustment INTEGER NOT NULL DEFAULT 0,
+    reps_adjustment INTEGER NOT NULL DEFAULT 0,
+    intensity_percent NUMERIC(5,2) NOT NULL DEFAULT 100,
+    is_deload BOOLEAN NOT NULL DEFAULT false,
+    notes TEXT,
+    CONSTRAINT program_weeks_pkey PRIMARY KEY (program_id, week_number),
+    CONSTRAINT program_weeks_week_number_check CHECK (week_number BETWEEN 1 AND 52),
+    CONSTRAINT program_weeks_adjustments_check CHECK (
+        sets_adjustment BETWEEN -10 AND 10
+        AND reps_adjustment BETWEEN -50 AND 50
+        AND intensity_percent > 0 AND intensity_percent <= 200
+    )
+);
+
+-- Seguimiento de un programa por un usuario: semana y día en curso y, si ya se inició,
+-- el día de entrenamiento de la sesión actual. Cada usuario sigue un solo programa a la vez.
+CREATE TABLE IF NOT EXISTS public.program_enrollments (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+    program_id BIGINT NOT NULL REFERENCES public.programs(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'active',
+    current_week INTEGER NOT NULL DEFAULT 1,
+    current_day INTEGER NOT NULL DEFAULT 1,
+    current_workout_day_id BIGINT REFERENCES public.workout_days(id) ON DELETE SET NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT program_enrollments_pkey PRIMARY KEY (id),
+    CONSTRAINT program_enrollments_status_check CHECK (status IN ('active', 'completed', 'abandoned'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_program_enrollments_user_active
+    ON public.program_enrollments(user_id) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_program_enrollments_program_id ON public.program_enrollments(program_id);
+
+-- Semana del programa de la sesión registrada en el día, para aplicar sus ajustes al plan
+ALTER TABLE public.workout_days
+ADD COLUMN IF NOT EXISTS program_enrollment_id BIGINT REFERENCES public.program_enrollments(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS program_week INTEGER,
+ADD COLUMN IF NOT EXISTS program_day INTEGER;
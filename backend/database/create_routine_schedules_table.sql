@@ -0,0 +1,15 @@
+-- Agenda semanal de rutinas: qué rutina toca cada día de la semana (1 = lunes ... 7 = domingo)
+-- y a qué hora prefiere entrenar el usuario. Cada día tiene a lo sumo una rutina, igual que
+-- cada fecha tiene un solo día de entrenamiento.
+CREATE TABLE IF NOT EXISTS public.routine_schedules (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    routine_id BIGINT NOT NULL REFERENCES public.user_routines(id) ON DELETE CASCADE,
+    weekday INTEGER NOT NULL,
+    preferred_time TIME,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT routine_schedules_pkey PRIMARY KEY (id),
+    CONSTRAINT routine_schedules_user_weekday_key UNIQUE (user_id, weekday),
+    CONSTRAINT routine_schedules_weekday_check CHECK (weekday BETWEEN 1 AND 7)
+);
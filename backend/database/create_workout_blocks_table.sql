@@ -0,0 +1,34 @@
+-- Bloques de superseries y circuitos. Agrupan series de distintos ejercicios
+-- de un mismo día que se hacen alternadas, con un descanso compartido.
+CREATE TABLE IF NOT EXISTS public.workout_blocks (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    workout_day_id BIGINT NOT NULL REFERENCES public.workout_days(id) ON DELETE CASCADE,
+    block_type TEXT NOT NULL DEFAULT 'superset',
+    order_index INTEGER NOT NULL DEFAULT 0,
+    rest_time_seconds INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT workout_blocks_pkey PRIMARY KEY (id),
+    CONSTRAINT workout_blocks_type_check CHECK (block_type IN ('superset', 'circuit')),
+    CONSTRAINT workout_blocks_rest_check CHECK (rest_time_seconds IS NULL OR (rest_time_seconds >= 0 AND rest_time_seconds <= 3600))
+);
+
+CREATE INDEX IF NOT EXISTS idx_workout_blocks_workout_day_id
+    ON public.workout_blocks(workout_day_id, order_index);
+
+-- Bloque al que pertenece cada serie (NULL si se hizo sola)
+ALTER TABLE public.workouts
+ADD COLUMN IF NOT EXISTS block_id BIGINT REFERENCES public.workout_blocks(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_workouts_block_id ON public.workouts(block_id);
+
+-- En las rutinas, los ejercicios con el mismo block_index forman un bloque
+ALTER TABLE public.routine_exercises
+ADD COLUMN IF NOT EXISTS block_index INTEGER,
+ADD COLUMN IF NOT EXISTS block_type TEXT,
+ADD COLUMN IF NOT EXISTS block_rest_time_seconds INTEGER;
+
+ALTER TABLE public.routine_exercises DROP CONSTRAINT IF EXISTS routine_exercises_block_type_check;
+ALTER TABLE public.routine_exercises
+ADD CONSTRAINT routine_exercises_block_type_check CHECK (block_type IS NULL OR block_type IN ('superset', 'circuit'));
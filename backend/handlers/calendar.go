@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+// defaultWeeklySessionTarget es el objetivo de sesiones semanales (fullbody lunes/miércoles/viernes)
+const defaultWeeklySessionTarget = 3
+
+// maxWeeklySessionTarget limita el objetivo que se puede pedir por query
+const maxWeeklySessionTarget = 7
+
+// heatmapLevel devuelve la intensidad 0-4 de un día según sus series respecto del máximo del período
+func heatmapLevel(sets, maxSets int) int {
+	if sets <= 0 || maxSets <= 0 {
+		return 0
+	}
+	level := (sets*4 + maxSets - 1) / maxSets
+	if level > 4 {
+		level = 4
+	}
+	return level
+}
+
+// buildCalendar arma los días de [from, to] y las semanas que los contienen a partir de los días entrenados.
+// days puede incluir días fuera de [from, to] para completar las semanas de los extremos.
+func buildCalendar(days []daySummary, from, to, today time.Time, target int) models.TrainingCalendar {
+	calendar := models.TrainingCalendar{
+		From:         from.Format("2006-01-02"),
+		To:           to.Format("2006-01-02"),
+		WeeklyTarget: target,
+		Days:         []models.CalendarDay{},
+		Weeks:        []models.CalendarWeek{},
+	}
+
+	byDate := make(map[string]daySummary)
+	weekSessions := make(map[string]int)
+	maxSets := 0
+	for _, day := range days {
+		key := day.Date.Format("2006-01-02")
+		byDate[key] = day
+		weekSessions[periodStart(day.Date, "week").Format("2006-01-02")]++
+		if !day.Date.Before(from) && !day.Date.After(to) && day.Sets > maxSets {
+			maxSets = day.Sets
+		}
+	}
+
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		key := date.Format("2006-01-02")
+		calendarDay := models.CalendarDay{Date: key, Status: models.CalendarDayRest}
+		if day, ok := byDate[key]; ok {
+			id := day.ID
+			calendarDay.Status = models.CalendarDayTrained
+			calendarDay.WorkoutDayID = &id
+			calendarDay.Sets = day.Sets
+			calendarDay.Volume = day.Volume
+			calendarDay.Effort = day.Effort
+			calendarDay.Mood = day.Mood
+			calendarDay.Level = heatmapLevel(day.Sets, maxSets)
+			calendar.TotalSessions++
+		} else if date.After(today) {
+			calendarDay.Status = models.CalendarDayFuture
+		}
+		calendar.Days = append(calendar.Days, calendarDay)
+	}
+
+	for start := periodStart(from, "week"); !start.After(to); start = nextPeriod(start, "week") {
+		key := start.Format("2006-01-02")
+		week := models.CalendarWeek{
+			WeekStart: key,
+			Sessions:  weekSessions[key],
+			Target:    target,
+			Completed: start.AddDate(0, 0, 6).Before(today),
+		}
+		week.Met = week.Sessions >= target
+		if week.Completed {
+			calendar.WeeksCompleted++
+			if week.Met {
+				calendar.WeeksMet++
+			}
+		}
+		calendar.Weeks = append(calendar.Weeks, week)
+	}
+	if calendar.WeeksCompleted > 0 {
+		calendar.AdherenceRate = float64(calendar.WeeksMet) / float64(calendar.WeeksCompleted)
+	}
+
+	return calendar
+}
+
+// GetCalendarHandler obtiene el calendario de entrenamiento de un mes (?month=YYYY-MM) o un año (?year=YYYY)
+// con el estado de cada día y el cumplimiento del objetivo semanal (?target, por defecto 3)
+func GetCalendarHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	loc := getUserLocation(r, userID)
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	monthParam := r.URL.Query().Get("month")
+	yearParam := r.URL.Query().Get("year")
+	if monthParam != "" && yearParam != "" {
+		http.Error(w, "Usar month o year, no ambos", http.StatusBadRequest)
+		return
+	}
+
+	period := "month"
+	var from, to time.Time
+	switch {
+	case yearParam != "":
+		year, err := strconv.Atoi(yearParam)
+		if err != nil || year < 2000 || year > 2100 {
+			http.Error(w, "year inválido, usar formato YYYY", http.StatusBadRequest)
+			return
+		}
+		period = "year"
+		from = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		to = from.AddDate(1, 0, -1)
+	case monthParam != "":
+		parsed, err := time.Parse("2006-01", monthParam)
+		if err != nil {
+			http.Error(w, "month inválido, usar formato YYYY-MM", http.StatusBadRequest)
+			return
+		}
+		from = parsed
+		to = from.AddDate(0, 1, -1)
+	default:
+		from = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+		to = from.AddDate(0, 1, -1)
+	}
+
+	target := defaultWeeklySessionTarget
+	if targetParam := r.URL.Query().Get("target"); targetParam != "" {
+		var err error
+		target, err = strconv.Atoi(targetParam)
+		if err != nil || target < 1 || target > maxWeeklySessionTarget {
+			http.Error(w, fmt.Sprintf("target debe estar entre 1 y %d", maxWeeklySessionTarget), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Se consultan las semanas completas de los extremos para el resumen semanal
+	queryFrom := periodStart(from, "week")
+	queryTo := periodStart(to, "week").AddDate(0, 0, 6)
+	days, err := loadDaySummaries(userID, queryFrom.Format("2006-01-02"), queryTo.Format("2006-01-02"))
+	if err != nil {
+		fmt.Printf("Error consultando calendario: %v\n", err)
+		http.Error(w, "Error obteniendo calendario", http.StatusInternalServerError)
+		return
+	}
+
+	calendar := buildCalendar(days, from, to, today, target)
+	calendar.Period = period
+	calendar.Timezone = loc.String()
+	calendar.WeightUnit = getUserWeightUnit(userID)
+	convertCalendarVolumes(&calendar, calendar.WeightUnit)
+
+	json.NewEncoder(w).Encode(calendar)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+func TestHeatmapLevel(t *testing.T) {
+	tests := []struct {
+		sets, maxSets, want int
+	}{
+		{0, 20, 0},
+		{1, 20, 1},
+		{5, 20, 1},
+		{6, 20, 2},
+		{20, 20, 4},
+		{3, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := heatmapLevel(tt.sets, tt.maxSets); got != tt.want {
+			t.Errorf("heatmapLevel(%d, %d) = %d, want %d", tt.sets, tt.maxSets, got, tt.want)
+		}
+	}
+}
+
+func TestBuildCalendar(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	// Marzo 2024 empieza un viernes; la semana del 26/02 se completa con días de febrero
+	days := []daySummary{
+		{ID: 1, Date: date("2024-02-26"), Sets: 10},
+		{ID: 2, Date: date("2024-02-28"), Sets: 10},
+		{ID: 3, Date: date("2024-03-01"), Sets: 20},
+		{ID: 4, Date: date("2024-03-04"), Sets: 5},
+	}
+
+	calendar := buildCalendar(days, date("2024-03-01"), date("2024-03-31"), date("2024-03-10"), 3)
+
+	if len(calendar.Days) != 31 {
+		t.Fatalf("cantidad de días incorrecta: %d", len(calendar.Days))
+	}
+	if calendar.TotalSessions != 2 {
+		t.Errorf("sesiones incorrectas: %d", calendar.TotalSessions)
+	}
+	if calendar.Days[0].Status != models.CalendarDayTrained || calendar.Days[0].Level != 4 {
+		t.Errorf("primer día incorrecto: %+v", calendar.Days[0])
+	}
+	if calendar.Days[1].Status != models.CalendarDayRest || calendar.Days[30].Status != models.CalendarDayFuture {
+		t.Errorf("estados incorrectos: %s %s", calendar.Days[1].Status, calendar.Days[30].Status)
+	}
+	if len(calendar.Weeks) != 5 || calendar.Weeks[0].Sessions != 3 || !calendar.Weeks[0].Met {
+		t.Errorf("primera semana incorrecta: %+v", calendar.Weeks)
+	}
+	if calendar.WeeksCompleted != 1 || calendar.WeeksMet != 1 || calendar.AdherenceRate != 1 {
+		t.Errorf("adherencia incorrecta: %d/%d %v", calendar.WeeksMet, calendar.WeeksCompleted, calendar.AdherenceRate)
+	}
+}
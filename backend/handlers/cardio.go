@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+// Límites de las métricas de cardio
+const (
+	maxDistanceMeters      = 1000000 // 1000 km
+	maxElevationGainMeters = 20000
+	minHeartRate           = 30
+	maxHeartRate           = 250
+	maxCalories            = 20000
+)
+
+// metersPerMile es el factor de conversión de millas a metros
+const metersPerMile = 1609.344
+
+// exerciseKind indica cómo se registra un ejercicio: fuerza, deporte o deporte con distancia
+type exerciseKind struct {
+	IsSport        bool
+	TracksDistance bool
+	Bodyweight     bool // Se registra el peso corporal del usuario junto con la serie
+}
+
+// loadExerciseKind obtiene el tipo de un ejercicio; devuelve sql.ErrNoRows si no existe
+func loadExerciseKind(q dbQuerier, exerciseID int) (exerciseKind, error) {
+	var kind exerciseKind
+	err := q.QueryRow("SELECT is_sport, tracks_distance, bodyweight FROM exercises WHERE id = $1", exerciseID).Scan(&kind.IsSport, &kind.TracksDistance, &kind.Bodyweight)
+	return kind, err
+}
+
+// validateCardioMetrics valida las métricas de cardio según el tipo de ejercicio:
+// solo los deportes aceptan métricas y solo los que se miden por distancia aceptan distancia y desnivel
+func validateCardioMetrics(metrics models.CardioMetrics, kind exerciseKind) error {
+	if metrics.IsEmpty() {
+		return nil
+	}
+	if !kind.IsSport {
+		return errors.New("Las métricas de cardio solo se pueden cargar en deportes")
+	}
+	if !kind.TracksDistance && (metrics.DistanceMeters != nil || metrics.ElevationGainMeters != nil) {
+		return errors.New("Este deporte no se mide por distancia")
+	}
+	if metrics.DistanceMeters != nil && (*metrics.DistanceMeters <= 0 || *metrics.DistanceMeters > maxDistanceMeters) {
+		return errors.New("La distancia debe ser mayor a 0 y de hasta 1000 km")
+	}
+	if metrics.ElevationGainMeters != nil && (*metrics.ElevationGainMeters < 0 || *metrics.ElevationGainMeters > maxElevationGainMeters) {
+		return errors.New("El desnivel debe estar entre 0 y 20000 metros")
+	}
+	for _, heartRate := range []*int{metrics.AvgHeartRate, metrics.MaxHeartRate} {
+		if heartRate != nil && (*heartRate < minHeartRate || *heartRate > maxHeartRate) {
+			return errors.New("El pulso debe estar entre 30 y 250 ppm")
+		}
+	}
+	if metrics.AvgHeartRate != nil && metrics.MaxHeartRate != nil && *metrics.AvgHeartRate > *metrics.MaxHeartRate {
+		return errors.New("El pulso promedio no puede ser mayor al máximo")
+	}
+	if metrics.Calories != nil && (*metrics.Calories < 0 || *metrics.Calories > maxCalories) {
+		return errors.New("Las calorías deben estar entre 0 y 20000")
+	}
+	return nil
+}
+
+// writeExerciseKindError responde el error de loadExerciseKind
+func writeExerciseKindError(w http.ResponseWriter, err error) {
+	if err == sql.ErrNoRows {
+		http.Error(w, "Ejercicio no encontrado", http.StatusBadRequest)
+		return
+	}
+	fmt.Printf("Error verificando ejercicio: %v\n", err)
+	http.Error(w, "Error verificando ejercicio", http.StatusInternalServerError)
+}
+
+// cardioPace calcula el ritmo (segundos por km) y la velocidad (km/h) a partir de la distancia y la duración
+func cardioPace(distanceMeters *float64, seconds *int) (*float64, *float64) {
+	if distanceMeters == nil || seconds == nil || *distanceMeters <= 0 || *seconds <= 0 {
+		return nil, nil
+	}
+	km := *distanceMeters / 1000
+	pace := math.Round(float64(*seconds)/km*10) / 10
+	speed := math.Round(km/(float64(*seconds)/3600)*100) / 100
+	return &pace, &speed
+}
+
+// fillCardioPace completa el ritmo y la velocidad calculados de una serie
+func fillCardioPace(workout *models.Workout) {
+	workout.PaceSecondsPerKm, workout.SpeedKmh = cardioPace(workout.DistanceMeters, workout.Seconds)
+}
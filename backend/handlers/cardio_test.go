@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+func TestValidateCardioMetrics(t *testing.T) {
+	floatPtr := func(v float64) *float64 { return &v }
+	intPtr := func(v int) *int { return &v }
+
+	strength := exerciseKind{}
+	sport := exerciseKind{IsSport: true}
+	running := exerciseKind{IsSport: true, TracksDistance: true}
+
+	tests := []struct {
+		name    string
+		metrics models.CardioMetrics
+		kind    exerciseKind
+		wantErr bool
+	}{
+		{"fuerza sin métricas", models.CardioMetrics{}, strength, false},
+		{"fuerza con pulso", models.CardioMetrics{AvgHeartRate: intPtr(120)}, strength, true},
+		{"carrera completa", models.CardioMetrics{
+			DistanceMeters: floatPtr(10000), ElevationGainMeters: floatPtr(120),
+			AvgHeartRate: intPtr(150), MaxHeartRate: intPtr(178), Calories: intPtr(650),
+		}, running, false},
+		{"deporte sin distancia con distancia", models.CardioMetrics{DistanceMeters: floatPtr(5000)}, sport, true},
+		{"deporte sin distancia con pulso", models.CardioMetrics{AvgHeartRate: intPtr(140), Calories: intPtr(500)}, sport, false},
+		{"distancia cero", models.CardioMetrics{DistanceMeters: floatPtr(0)}, running, true},
+		{"pulso fuera de rango", models.CardioMetrics{MaxHeartRate: intPtr(260)}, running, true},
+		{"pulso promedio mayor al máximo", models.CardioMetrics{AvgHeartRate: intPtr(170), MaxHeartRate: intPtr(160)}, running, true},
+		{"calorías negativas", models.CardioMetrics{Calories: intPtr(-1)}, running, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCardioMetrics(tt.metrics, tt.kind)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateCardioMetrics() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCardioPace(t *testing.T) {
+	distance := 10000.0
+	seconds := 3000 // 10 km en 50 minutos
+
+	pace, speed := cardioPace(&distance, &seconds)
+	if pace == nil || *pace != 300 {
+		t.Errorf("ritmo incorrecto: %v, se esperaban 300 s/km", pace)
+	}
+	if speed == nil || *speed != 12 {
+		t.Errorf("velocidad incorrecta: %v, se esperaban 12 km/h", speed)
+	}
+
+	if pace, speed := cardioPace(nil, &seconds); pace != nil || speed != nil {
+		t.Errorf("sin distancia no debería calcularse ritmo")
+	}
+	zero := 0
+	if pace, _ := cardioPace(&distance, &zero); pace != nil {
+		t.Errorf("sin duración no debería calcularse ritmo")
+	}
+}
+
+func TestSummarizeDaysCardioTotals(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	days := []daySummary{
+		{Date: date("2024-03-04"), Sets: 1, Distance: 10000, Duration: 3000},
+		{Date: date("2024-03-06"), Sets: 1, Distance: 5000, Duration: 1500},
+		{Date: date("2024-03-13"), Sets: 8, Volume: 800},
+	}
+
+	stats := summarizeDays(days, time.Time{}, date("2024-03-17"))
+
+	if stats.TotalDistance != 15000 || stats.TotalDuration != 4500 {
+		t.Errorf("totales de cardio incorrectos: %v m, %v s", stats.TotalDistance, stats.TotalDuration)
+	}
+	if len(stats.Weekly) != 2 || stats.Weekly[0].DistanceMeters != 15000 || stats.Weekly[0].DurationSeconds != 4500 {
+		t.Errorf("semana con cardio incorrecta: %+v", stats.Weekly)
+	}
+	if stats.Weekly[1].DistanceMeters != 0 {
+		t.Errorf("la segunda semana no tiene cardio: %+v", stats.Weekly[1])
+	}
+}
@@ -1,3 +1,5 @@
+//go:build integration
+
 package handlers
 
 import (
@@ -74,11 +76,11 @@ func TestWorkoutHandlersWithUtilities(t *testing.T) {
 	t.Run("Create workout with valid data", func(t *testing.T) {
 		workoutData := models.CreateWorkoutRequest{
 			ExerciseID:   1,
-			Weight:       80.5,
-			Reps:         10,
-			Serie:        intPtr(1),
+			Weight:       floatPtr(80.5),
+			Reps:         intPtr(10),
+			Set:          intPtr(1),
 			Seconds:      intPtr(45),
-			Observations: stringPtr("Test workout"),
+			Observations: "Test workout",
 		}
 
 		rr := suite.MakeRequest(testutils.TestRequest{
@@ -99,8 +101,8 @@ func TestWorkoutHandlersWithUtilities(t *testing.T) {
 	t.Run("Create workout with invalid data", func(t *testing.T) {
 		invalidData := models.CreateWorkoutRequest{
 			ExerciseID: 1,
-			Weight:     -10, // Peso inválido
-			Reps:       0,   // Reps inválidas
+			Weight:     floatPtr(-10), // Peso inválido
+			Reps:       intPtr(0),   // Reps inválidas
 		}
 
 		rr := suite.MakeRequest(testutils.TestRequest{
@@ -121,8 +123,8 @@ func TestWorkoutHandlersWithUtilities(t *testing.T) {
 	t.Run("Create workout without authentication", func(t *testing.T) {
 		workoutData := models.CreateWorkoutRequest{
 			ExerciseID: 1,
-			Weight:     80.5,
-			Reps:       10,
+			Weight:     floatPtr(80.5),
+			Reps:       intPtr(10),
 		}
 
 		rr := suite.MakeRequest(testutils.TestRequest{
@@ -154,8 +156,8 @@ func TestTableDrivenWorkoutValidation(t *testing.T) {
 			name: "Valid workout",
 			data: models.CreateWorkoutRequest{
 				ExerciseID: 1,
-				Weight:     80.5,
-				Reps:       10,
+				Weight:     floatPtr(80.5),
+				Reps:       intPtr(10),
 			},
 			expectedStatus: 500, // Sin DB real, esperamos 500 no 400
 			shouldContain:  "",
@@ -164,8 +166,8 @@ func TestTableDrivenWorkoutValidation(t *testing.T) {
 			name: "Zero weight",
 			data: models.CreateWorkoutRequest{
 				ExerciseID: 1,
-				Weight:     0,
-				Reps:       10,
+				Weight:     floatPtr(0),
+				Reps:       intPtr(10),
 			},
 			expectedStatus: 400,
 			shouldContain:  "peso",
@@ -174,8 +176,8 @@ func TestTableDrivenWorkoutValidation(t *testing.T) {
 			name: "Negative weight",
 			data: models.CreateWorkoutRequest{
 				ExerciseID: 1,
-				Weight:     -10,
-				Reps:       10,
+				Weight:     floatPtr(-10),
+				Reps:       intPtr(10),
 			},
 			expectedStatus: 400,
 			shouldContain:  "peso",
@@ -184,8 +186,8 @@ func TestTableDrivenWorkoutValidation(t *testing.T) {
 			name: "Zero reps",
 			data: models.CreateWorkoutRequest{
 				ExerciseID: 1,
-				Weight:     80.5,
-				Reps:       0,
+				Weight:     floatPtr(80.5),
+				Reps:       intPtr(0),
 			},
 			expectedStatus: 400,
 			shouldContain:  "repeticiones",
@@ -194,8 +196,8 @@ func TestTableDrivenWorkoutValidation(t *testing.T) {
 			name: "High weight (boundary test)",
 			data: models.CreateWorkoutRequest{
 				ExerciseID: 1,
-				Weight:     999.99,
-				Reps:       1,
+				Weight:     floatPtr(999.99),
+				Reps:       intPtr(1),
 			},
 			expectedStatus: 500, // Debería ser válido
 			shouldContain:  "",
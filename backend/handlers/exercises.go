@@ -16,7 +16,7 @@ func GetExercisesHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	// Query simple para obtener solo id y name para el select del frontend
-	query := `SELECT id, name, bodyweight, is_sport FROM exercises ORDER BY name ASC`
+	query := `SELECT id, name, bodyweight, is_sport, tracks_distance FROM exercises ORDER BY name ASC`
 
 	rows, err := database.DB.Query(query)
 	if err != nil {
@@ -31,13 +31,15 @@ func GetExercisesHandler(w http.ResponseWriter, r *http.Request) {
 		Name       string `json:"name"`
 		Bodyweight bool   `json:"bodyweight"`
 		IsSport    bool   `json:"is_sport"`
+		// Deportes que se registran con distancia (correr, bici, natación)
+		TracksDistance bool `json:"tracks_distance"`
 	}
 
 	var exercises []SimpleExercise
 	for rows.Next() {
 		var exercise SimpleExercise
 
-		err := rows.Scan(&exercise.ID, &exercise.Name, &exercise.Bodyweight, &exercise.IsSport)
+		err := rows.Scan(&exercise.ID, &exercise.Name, &exercise.Bodyweight, &exercise.IsSport, &exercise.TracksDistance)
 		if err != nil {
 			http.Error(w, "Error escaneando ejercicio", http.StatusInternalServerError)
 			return
@@ -64,7 +66,7 @@ func GetExerciseHandler(w http.ResponseWriter, r *http.Request) {
 		SELECT e.id, e.name, e.muscle_group,
 			   COALESCE(array_agg(DISTINCT mp.name) FILTER (WHERE mp.name IS NOT NULL AND emg_p.role = 'primary'), '{}') as primary_muscles,
 			   COALESCE(array_agg(DISTINCT ms.name) FILTER (WHERE ms.name IS NOT NULL AND emg_s.role = 'secondary'), '{}') as secondary_muscles,
-			   eq.name as equipment, e.video_url, e.bodyweight, e.is_sport, e.created_at
+			   eq.name as equipment, e.video_url, e.bodyweight, e.is_sport, e.tracks_distance, e.created_at
 		FROM exercises e
 		LEFT JOIN equipment eq ON e.equipment_id = eq.id
 		LEFT JOIN exercise_muscle_groups emg_p ON e.id = emg_p.exercise_id AND emg_p.role = 'primary'
@@ -72,7 +74,7 @@ func GetExerciseHandler(w http.ResponseWriter, r *http.Request) {
 		LEFT JOIN exercise_muscle_groups emg_s ON e.id = emg_s.exercise_id AND emg_s.role = 'secondary'
 		LEFT JOIN muscle_groups ms ON emg_s.muscle_group_id = ms.id
 		WHERE e.id = $1
-		GROUP BY e.id, e.name, e.muscle_group, eq.name, e.video_url, e.bodyweight, e.is_sport, e.created_at
+		GROUP BY e.id, e.name, e.muscle_group, eq.name, e.video_url, e.bodyweight, e.is_sport, e.tracks_distance, e.created_at
 	`
 
 	var exercise models.Exercise
@@ -89,6 +91,7 @@ func GetExerciseHandler(w http.ResponseWriter, r *http.Request) {
 		&exercise.VideoURL,
 		&exercise.Bodyweight,
 		&exercise.IsSport,
+		&exercise.TracksDistance,
 		&exercise.CreatedAt,
 	)
 
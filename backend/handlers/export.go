@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/lib/pq"
+)
+
+// exportFlushEvery indica cada cuántas filas se envía lo escrito al cliente
+const exportFlushEvery = 500
+
+// exportHeader son las columnas del CSV de exportación
+var exportHeader = []string{
+	"date", "day_name", "exercise", "set", "weight", "reps", "seconds", "observations", "effort", "mood",
+	"set_type", "rpe", "rir", "weight_unit",
+	"distance_meters", "elevation_gain_meters", "avg_heart_rate", "max_heart_rate", "calories",
+}
+
+// exportFilename arma el nombre del archivo según el rango exportado
+func exportFilename(from, to, today string) string {
+	switch {
+	case from != "" && to != "":
+		return fmt.Sprintf("entrenamientos_%s_%s.csv", from, to)
+	case from != "":
+		return fmt.Sprintf("entrenamientos_desde_%s.csv", from)
+	case to != "":
+		return fmt.Sprintf("entrenamientos_hasta_%s.csv", to)
+	default:
+		return fmt.Sprintf("entrenamientos_%s.csv", today)
+	}
+}
+
+// formatExportFloat escribe un número sin ceros de más (60 en vez de 60.000000)
+func formatExportFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// escapeExportText evita que una planilla interprete un texto cargado por el usuario como fórmula
+// (inyección CSV): si empieza con =, +, -, @, tabulación o retorno de carro se antepone un apóstrofo
+func escapeExportText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// formatExportOptionalFloat escribe un número opcional, vacío si no se cargó
+func formatExportOptionalFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return formatExportFloat(*value)
+}
+
+// formatExportOptionalInt escribe un entero opcional, vacío si no se cargó
+func formatExportOptionalInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+// ExportWorkoutsHandler exporta el historial de series del usuario como CSV.
+// Filtros opcionales: from, to (YYYY-MM-DD), exercise_ids (separados por coma) e is_sport.
+// Las filas se escriben a medida que se leen de la base para no cargar todo el historial en memoria.
+func ExportWorkoutsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	exerciseIDs, err := parseIDList(r, "exercise_ids")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	isSport, err := parseOptionalBool(r, "is_sport")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query, args := appendDateRangeFilter(`
+		SELECT wd.date::text, wd.name, e.name, w.set, w.weight, w.reps, w.seconds,
+			COALESCE(w.observations, ''), wd.effort, wd.mood, w.set_type, w.rpe, w.rir,
+			w.distance_meters, w.elevation_gain_meters, w.avg_heart_rate, w.max_heart_rate, w.calories
+		FROM workouts w
+		JOIN workout_days wd ON w.workout_day_id = wd.id
+		JOIN exercises e ON w.exercise_id = e.id
+		WHERE w.user_id = $1 AND w.deleted_at IS NULL`, "wd.date", from, to, []interface{}{userID})
+	if len(exerciseIDs) > 0 {
+		args = append(args, pq.Array(exerciseIDs))
+		query += fmt.Sprintf(" AND w.exercise_id = ANY($%d)", len(args))
+	}
+	if isSport != nil {
+		args = append(args, *isSport)
+		query += fmt.Sprintf(" AND COALESCE(e.is_sport, false) = $%d", len(args))
+	}
+	query += " ORDER BY wd.date ASC, w.created_at ASC, w.set ASC"
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Error consultando exportación: %v\n", err)
+		http.Error(w, "Error exportando entrenamientos", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	today := time.Now().In(getUserLocation(r, userID)).Format("2006-01-02")
+	// El peso se exporta en la unidad del usuario, indicada en cada fila
+	unit := getUserWeightUnit(userID)
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, exportFilename(from, to, today)))
+	w.Header().Set("Cache-Control", "no-store")
+
+	flusher, _ := w.(http.Flusher)
+	writer := csv.NewWriter(w)
+	writer.Write(exportHeader)
+
+	count := 0
+	for rows.Next() {
+		var date, dayName, exerciseName, observations string
+		var set, reps, effort, mood int
+		var weight float64
+		var seconds, rir *int
+		var rpe *float64
+		var setType string
+		var cardio models.CardioMetrics
+		if err := rows.Scan(&date, &dayName, &exerciseName, &set, &weight, &reps, &seconds, &observations, &effort, &mood, &setType, &rpe, &rir,
+			&cardio.DistanceMeters, &cardio.ElevationGainMeters, &cardio.AvgHeartRate, &cardio.MaxHeartRate, &cardio.Calories); err != nil {
+			// Los headers ya se enviaron: solo se puede cortar la exportación
+			fmt.Printf("Error escaneando fila de exportación: %v\n", err)
+			break
+		}
+
+		writer.Write([]string{
+			date,
+			escapeExportText(dayName),
+			escapeExportText(exerciseName),
+			strconv.Itoa(set),
+			formatExportFloat(fromKilograms(weight, unit)),
+			strconv.Itoa(reps),
+			formatExportOptionalInt(seconds),
+			escapeExportText(observations),
+			strconv.Itoa(effort),
+			strconv.Itoa(mood),
+			setType,
+			formatExportOptionalFloat(rpe),
+			formatExportOptionalInt(rir),
+			unit,
+			formatExportOptionalFloat(cardio.DistanceMeters),
+			formatExportOptionalFloat(cardio.ElevationGainMeters),
+			formatExportOptionalInt(cardio.AvgHeartRate),
+			formatExportOptionalInt(cardio.MaxHeartRate),
+			formatExportOptionalInt(cardio.Calories),
+		})
+
+		count++
+		if count%exportFlushEvery == 0 {
+			writer.Flush()
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		fmt.Printf("Error leyendo exportación: %v\n", err)
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		fmt.Printf("Error escribiendo CSV: %v\n", err)
+	}
+}
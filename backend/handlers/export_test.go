@@ -0,0 +1,50 @@
+package handlers
+
+import "testing"
+
+func TestExportFilename(t *testing.T) {
+	tests := []struct {
+		from, to, want string
+	}{
+		{"2024-01-01", "2024-03-31", "entrenamientos_2024-01-01_2024-03-31.csv"},
+		{"2024-01-01", "", "entrenamientos_desde_2024-01-01.csv"},
+		{"", "2024-03-31", "entrenamientos_hasta_2024-03-31.csv"},
+		{"", "", "entrenamientos_2024-06-15.csv"},
+	}
+
+	for _, tt := range tests {
+		if got := exportFilename(tt.from, tt.to, "2024-06-15"); got != tt.want {
+			t.Errorf("exportFilename(%q, %q) = %q, want %q", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestFormatExportFloat(t *testing.T) {
+	if got := formatExportFloat(60); got != "60" {
+		t.Errorf("got %q want 60", got)
+	}
+	if got := formatExportFloat(62.5); got != "62.5" {
+		t.Errorf("got %q want 62.5", got)
+	}
+}
+
+func TestEscapeExportText(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"=HYPERLINK(\"http://x\")", "'=HYPERLINK(\"http://x\")"},
+		{"+5 kg", "'+5 kg"},
+		{"-1 rep", "'-1 rep"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1+1", "'\t=1+1"},
+		{"Press banca", "Press banca"},
+		{"buena serie = 5 reps", "buena serie = 5 reps"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := escapeExportText(tt.value); got != tt.want {
+			t.Errorf("escapeExportText(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package handlers
+
+// Helpers para punteros
+func intPtr(i int) *int {
+	return &i
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/lib/pq"
+)
+
+const (
+	defaultHistorySets = 100
+	maxHistorySets     = 500
+	defaultHistoryDays = 10
+	maxHistoryDays     = 60
+	maxHistorySearch   = 100
+)
+
+var errInvalidHistoryCursor = errors.New("cursor inválido")
+
+// historyCursor marca dónde termina una página del historial. Agrupando por día alcanza con
+// la fecha; sin agrupar se agrega la última serie, porque dentro del día van en orden cronológico.
+type historyCursor struct {
+	Date      string
+	CreatedAt time.Time
+	WorkoutID int
+}
+
+// encodeHistoryCursor arma el cursor opaco que se devuelve como next_cursor
+func encodeHistoryCursor(cursor historyCursor) string {
+	raw := cursor.Date
+	if cursor.WorkoutID > 0 {
+		raw += "|" + cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + strconv.Itoa(cursor.WorkoutID)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeHistoryCursor lee un cursor generado por encodeHistoryCursor
+func decodeHistoryCursor(value string) (historyCursor, error) {
+	var cursor historyCursor
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, errInvalidHistoryCursor
+	}
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 1 && len(parts) != 3 {
+		return cursor, errInvalidHistoryCursor
+	}
+	if _, err := time.Parse("2006-01-02", parts[0]); err != nil {
+		return cursor, errInvalidHistoryCursor
+	}
+	cursor.Date = parts[0]
+	if len(parts) == 3 {
+		if cursor.CreatedAt, err = time.Parse(time.RFC3339Nano, parts[1]); err != nil {
+			return cursor, errInvalidHistoryCursor
+		}
+		if cursor.WorkoutID, err = strconv.Atoi(parts[2]); err != nil || cursor.WorkoutID <= 0 {
+			return cursor, errInvalidHistoryCursor
+		}
+	}
+	return cursor, nil
+}
+
+// historyFilter son los filtros del historial sobre las series (w = workouts, e = exercises, wd = workout_days)
+type historyFilter struct {
+	From          string
+	To            string
+	ExerciseIDs   []int
+	MuscleGroupID *int
+	IsSport       *bool
+	Search        string
+}
+
+// likePattern arma el patrón de ILIKE que busca text en cualquier parte, escapando los comodines
+func likePattern(text string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text) + "%"
+}
+
+// appendConditions agrega a la query las condiciones de los filtros enviados
+func (f historyFilter) appendConditions(query string, args []interface{}) (string, []interface{}) {
+	query, args = appendDateRangeFilter(query, "wd.date", f.From, f.To, args)
+	if len(f.ExerciseIDs) > 0 {
+		args = append(args, pq.Array(f.ExerciseIDs))
+		query += fmt.Sprintf(" AND w.exercise_id = ANY($%d)", len(args))
+	}
+	if f.MuscleGroupID != nil {
+		args = append(args, *f.MuscleGroupID)
+		query += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM exercise_muscle_groups emg WHERE emg.exercise_id = w.exercise_id AND emg.muscle_group_id = $%d)", len(args))
+	}
+	if f.IsSport != nil {
+		args = append(args, *f.IsSport)
+		query += fmt.Sprintf(" AND COALESCE(e.is_sport, false) = $%d", len(args))
+	}
+	if f.Search != "" {
+		args = append(args, likePattern(f.Search))
+		query += fmt.Sprintf(" AND w.observations ILIKE $%d", len(args))
+	}
+	return query, args
+}
+
+// parseHistoryFilter lee los filtros del historial de la query
+func parseHistoryFilter(r *http.Request) (historyFilter, error) {
+	var f historyFilter
+	var err error
+	if f.From, f.To, err = parseDateRange(r); err != nil {
+		return f, err
+	}
+	if f.ExerciseIDs, err = parseIDList(r, "exercise_ids"); err != nil {
+		return f, err
+	}
+	if f.MuscleGroupID, err = parseOptionalID(r, "muscle_group_id"); err != nil {
+		return f, err
+	}
+	if f.IsSport, err = parseOptionalBool(r, "is_sport"); err != nil {
+		return f, err
+	}
+	f.Search = strings.TrimSpace(r.URL.Query().Get("q"))
+	if len(f.Search) > maxHistorySearch {
+		return f, fmt.Errorf("q no puede tener más de %d caracteres", maxHistorySearch)
+	}
+	return f, nil
+}
+
+// GetWorkoutHistoryHandler devuelve el historial de series del usuario paginado con cursor, de los
+// días más recientes a los más antiguos. Filtros opcionales: from, to (YYYY-MM-DD), exercise_ids
+// (separados por coma), muscle_group_id, is_sport y q (texto en las observaciones).
+// Con group_by=day cada página trae limit días, con sus series agrupadas por ejercicio.
+func GetWorkoutHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	filter, err := parseHistoryFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	groupBy := r.URL.Query().Get("group_by")
+	if groupBy == "" {
+		groupBy = "set"
+	}
+	if groupBy != "set" && groupBy != "day" {
+		http.Error(w, "group_by inválido: usar set o day", http.StatusBadRequest)
+		return
+	}
+
+	var cursor *historyCursor
+	if value := r.URL.Query().Get("cursor"); value != "" {
+		decoded, err := decodeHistoryCursor(value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		cursor = &decoded
+	}
+
+	defaultLimit, maxLimit := defaultHistorySets, maxHistorySets
+	if groupBy == "day" {
+		defaultLimit, maxLimit = defaultHistoryDays, maxHistoryDays
+	}
+	limit, err := parseLimit(r, defaultLimit, maxLimit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	loc := getUserLocation(r, userID)
+	page := models.WorkoutHistoryPage{GroupBy: groupBy, WeightUnit: getUserWeightUnit(userID)}
+	if groupBy == "day" {
+		err = loadHistoryDays(&page, userID, filter, cursor, limit, loc)
+	} else {
+		err = loadHistorySets(&page, userID, filter, cursor, limit, loc)
+	}
+	if err != nil {
+		fmt.Printf("Error consultando historial: %v\n", err)
+		http.Error(w, "Error consultando historial", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(page)
+}
+
+// loadHistorySets carga una página de series sin agrupar: días más recientes primero
+// y, dentro de cada día, las series en el orden en que se hicieron
+func loadHistorySets(page *models.WorkoutHistoryPage, userID string, filter historyFilter, cursor *historyCursor, limit int, loc *time.Location) error {
+	query, args := filter.appendConditions(`
+		SELECT `+workoutColumns+`, wd.date::text
+		FROM workouts w
+		JOIN exercises e ON w.exercise_id = e.id
+		JOIN workout_days wd ON w.workout_day_id = wd.id
+		WHERE w.user_id = $1 AND w.deleted_at IS NULL`, []interface{}{userID})
+	if cursor != nil {
+		args = append(args, cursor.Date)
+		if cursor.WorkoutID > 0 {
+			args = append(args, cursor.CreatedAt, cursor.WorkoutID)
+			query += fmt.Sprintf(" AND (wd.date < $%d OR (wd.date = $%d AND (w.created_at, w.id) > ($%d, $%d)))", len(args)-2, len(args)-2, len(args)-1, len(args))
+		} else {
+			query += fmt.Sprintf(" AND wd.date < $%d", len(args))
+		}
+	}
+	args = append(args, limit+1)
+	query += fmt.Sprintf(" ORDER BY wd.date DESC, w.created_at ASC, w.id ASC LIMIT $%d", len(args))
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	page.Workouts = []models.Workout{}
+	var dates []string
+	for rows.Next() {
+		var workout models.Workout
+		var date string
+		if err := scanWorkout(rows, &workout, &date); err != nil {
+			return err
+		}
+		page.Workouts = append(page.Workouts, workout)
+		dates = append(dates, date)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(page.Workouts) > limit {
+		page.Workouts = page.Workouts[:limit]
+		last := page.Workouts[limit-1]
+		next := encodeHistoryCursor(historyCursor{Date: dates[limit-1], CreatedAt: last.CreatedAt, WorkoutID: last.ID})
+		page.NextCursor = &next
+	}
+	for i := range page.Workouts {
+		page.Workouts[i].CreatedAt = convertToUserTime(page.Workouts[i].CreatedAt, loc)
+	}
+	convertWorkoutWeights(page.Workouts, page.WeightUnit)
+	return nil
+}
+
+// loadHistoryDays carga una página de días con al menos una serie que cumpla los filtros.
+// Cada día trae solo esas series; los bloques y el plan de rutina están en GET /workout-days/{id}.
+func loadHistoryDays(page *models.WorkoutHistoryPage, userID string, filter historyFilter, cursor *historyCursor, limit int, loc *time.Location) error {
+	setsQuery, args := filter.appendConditions(`
+		SELECT 1
+		FROM workouts w
+		JOIN exercises e ON w.exercise_id = e.id
+		WHERE w.workout_day_id = wd.id AND w.deleted_at IS NULL`, []interface{}{userID})
+	query := `
+		SELECT ` + workoutDayColumns + `, wd.date::text
+		FROM workout_days wd
+		WHERE wd.user_id = $1 AND wd.deleted_at IS NULL AND EXISTS (` + setsQuery + `)`
+	if cursor != nil {
+		args = append(args, cursor.Date)
+		query += fmt.Sprintf(" AND wd.date < $%d", len(args))
+	}
+	args = append(args, limit+1)
+	query += fmt.Sprintf(" ORDER BY wd.date DESC LIMIT $%d", len(args))
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	page.Days = []models.WorkoutDayWithExercises{}
+	var dates []string
+	for rows.Next() {
+		var day models.WorkoutDayWithExercises
+		var date string
+		d := &day.WorkoutDay
+		err := rows.Scan(&d.ID, &d.UserID, &d.Date, &d.Name, &d.Effort, &d.Mood, &d.Notes, &d.RoutineID, &d.CreatedAt, &d.UpdatedAt, &date)
+		if err != nil {
+			return err
+		}
+		d.CreatedAt = convertToUserTime(d.CreatedAt, loc)
+		d.UpdatedAt = convertToUserTime(d.UpdatedAt, loc)
+		day.ExerciseGroups = []models.ExerciseGroup{}
+		day.Sets = []models.Workout{}
+		day.Blocks = []models.WorkoutBlock{}
+		page.Days = append(page.Days, day)
+		dates = append(dates, date)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(page.Days) > limit {
+		page.Days = page.Days[:limit]
+		next := encodeHistoryCursor(historyCursor{Date: dates[limit-1]})
+		page.NextCursor = &next
+	}
+	if len(page.Days) == 0 {
+		return nil
+	}
+
+	dayIndex := make(map[int]int, len(page.Days))
+	dayIDs := make([]int, len(page.Days))
+	for i, day := range page.Days {
+		dayIndex[day.WorkoutDay.ID] = i
+		dayIDs[i] = day.WorkoutDay.ID
+	}
+
+	setsQuery, args = filter.appendConditions(`
+		SELECT `+workoutColumns+`
+		FROM workouts w
+		JOIN exercises e ON w.exercise_id = e.id
+		JOIN workout_days wd ON w.workout_day_id = wd.id
+		WHERE w.user_id = $1 AND w.workout_day_id = ANY($2) AND w.deleted_at IS NULL`, []interface{}{userID, pq.Array(dayIDs)})
+	setsQuery += " ORDER BY w.created_at ASC, w.id ASC"
+
+	setRows, err := database.DB.Query(setsQuery, args...)
+	if err != nil {
+		return err
+	}
+	defer setRows.Close()
+
+	for setRows.Next() {
+		var workout models.Workout
+		if err := scanWorkout(setRows, &workout); err != nil {
+			return err
+		}
+		workout.CreatedAt = convertToUserTime(workout.CreatedAt, loc)
+		appendWorkoutToDay(&page.Days[dayIndex[workout.WorkoutDayID]], workout)
+	}
+	if err := setRows.Err(); err != nil {
+		return err
+	}
+
+	for i := range page.Days {
+		convertWorkoutDayWeights(&page.Days[i], page.WeightUnit)
+	}
+	return nil
+}
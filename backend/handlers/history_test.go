@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistoryCursorRoundTrip(t *testing.T) {
+	tests := []historyCursor{
+		{Date: "2024-05-10"},
+		{Date: "2024-05-10", CreatedAt: time.Date(2024, 5, 10, 18, 30, 15, 123456000, time.UTC), WorkoutID: 42},
+	}
+
+	for _, want := range tests {
+		got, err := decodeHistoryCursor(encodeHistoryCursor(want))
+		if err != nil {
+			t.Fatalf("error decodificando %+v: %v", want, err)
+		}
+		if got.Date != want.Date || !got.CreatedAt.Equal(want.CreatedAt) || got.WorkoutID != want.WorkoutID {
+			t.Errorf("cursor %+v, se esperaba %+v", got, want)
+		}
+	}
+
+	for _, invalid := range []string{"%%%", "bm8tZmVjaGE", encodeHistoryCursor(historyCursor{Date: "2024-13-40"})} {
+		if _, err := decodeHistoryCursor(invalid); err != errInvalidHistoryCursor {
+			t.Errorf("%q debería ser un cursor inválido, error %v", invalid, err)
+		}
+	}
+}
+
+func TestHistoryFilterConditions(t *testing.T) {
+	muscleGroupID := 3
+	isSport := false
+	filter := historyFilter{
+		From:          "2024-01-01",
+		ExerciseIDs:   []int{1, 2},
+		MuscleGroupID: &muscleGroupID,
+		IsSport:       &isSport,
+		Search:        "50%_rodilla",
+	}
+
+	query, args := filter.appendConditions("WHERE w.user_id = $1", []interface{}{"user"})
+	if len(args) != 6 {
+		t.Fatalf("cantidad de parámetros incorrecta: %d", len(args))
+	}
+	for _, condition := range []string{"wd.date >= $2", "w.exercise_id = ANY($3)", "emg.muscle_group_id = $4", "= $5", "w.observations ILIKE $6"} {
+		if !strings.Contains(query, condition) {
+			t.Errorf("falta la condición %q en %s", condition, query)
+		}
+	}
+	if args[5] != `%50\%\_rodilla%` {
+		t.Errorf("patrón de búsqueda sin escapar: %v", args[5])
+	}
+}
@@ -0,0 +1,669 @@
+package handlers
+
+import (
+	"crypto/sha1"
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/lib/pq"
+)
+
+// Límites de la importación
+const (
+	maxImportFileSize = 10 << 20 // 10 MB
+	maxImportRows     = 50000
+)
+
+var errUnknownImportFormat = errors.New("formato de CSV no reconocido: se aceptan exportaciones de Strong y Hevy")
+
+// importRow representa una serie leída del CSV, ya normalizada
+type importRow struct {
+	Line         int
+	Date         string // YYYY-MM-DD
+	StartedAt    string // Inicio del entrenamiento tal como viene en el CSV
+	WorkoutName  string
+	ExerciseName string
+	Weight       float64 // En kg
+	Reps         int
+	Seconds      *int
+	Distance     *float64 // En metros
+	Notes        string
+	SetType      string
+	RPE          *float64
+	Key          string
+}
+
+// importColumns indica la posición de cada columna necesaria en el CSV
+type importColumns struct {
+	started, workout, exercise, weight, reps, seconds, distance, notes, setType, rpe int
+	weightInPounds, distanceInMiles                                                  bool
+}
+
+// columnIndex busca una columna por nombre (sin distinguir mayúsculas) y devuelve -1 si no está
+func columnIndex(header []string, names ...string) int {
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		for _, name := range names {
+			if column == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// detectImportFormat reconoce el formato del CSV por sus columnas
+func detectImportFormat(header []string, weightUnit string) (string, importColumns, error) {
+	if columnIndex(header, "exercise_title") >= 0 && columnIndex(header, "start_time") >= 0 {
+		cols := importColumns{
+			started:  columnIndex(header, "start_time"),
+			workout:  columnIndex(header, "title"),
+			exercise: columnIndex(header, "exercise_title"),
+			weight:   columnIndex(header, "weight_kg"),
+			reps:     columnIndex(header, "reps"),
+			seconds:  columnIndex(header, "duration_seconds"),
+			distance: columnIndex(header, "distance_km"),
+			notes:    columnIndex(header, "exercise_notes"),
+			setType:  columnIndex(header, "set_type"),
+			rpe:      columnIndex(header, "rpe"),
+		}
+		if cols.weight < 0 {
+			cols.weight = columnIndex(header, "weight_lbs")
+			cols.weightInPounds = cols.weight >= 0
+		}
+		if cols.distance < 0 {
+			cols.distance = columnIndex(header, "distance_miles")
+			cols.distanceInMiles = cols.distance >= 0
+		}
+		return models.ImportFormatHevy, cols, nil
+	}
+
+	if columnIndex(header, "exercise name") >= 0 && columnIndex(header, "date") >= 0 {
+		cols := importColumns{
+			started:  columnIndex(header, "date"),
+			workout:  columnIndex(header, "workout name"),
+			exercise: columnIndex(header, "exercise name"),
+			weight:   columnIndex(header, "weight"),
+			reps:     columnIndex(header, "reps"),
+			seconds:  columnIndex(header, "seconds"),
+			distance: columnIndex(header, "distance"),
+			notes:    columnIndex(header, "notes"),
+			setType:  columnIndex(header, "set order"),
+			rpe:      columnIndex(header, "rpe"),
+		}
+		// Strong exporta el peso y la distancia en las unidades configuradas en la app
+		cols.weightInPounds = weightUnit == models.WeightUnitLb
+		cols.distanceInMiles = weightUnit == models.WeightUnitLb
+		return models.ImportFormatStrong, cols, nil
+	}
+
+	return "", importColumns{}, errUnknownImportFormat
+}
+
+// parseImportDate obtiene la fecha (YYYY-MM-DD) del inicio de un entrenamiento en los formatos de Strong y Hevy
+func parseImportDate(value string) (string, error) {
+	layouts := []string{
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04",
+		"2 Jan 2006, 15:04",
+		"Jan 2, 2006, 15:04",
+		time.RFC3339,
+		"2006-01-02",
+	}
+	value = strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.Format("2006-01-02"), nil
+		}
+	}
+	return "", fmt.Errorf("fecha no reconocida: %q", value)
+}
+
+// importSetType traduce el tipo de serie de Strong (letra en "Set Order") o de Hevy (columna set_type)
+func importSetType(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "w", "warmup":
+		return models.SetTypeWarmup
+	case "d", "dropset":
+		return models.SetTypeDrop
+	case "f", "failure":
+		return models.SetTypeFailure
+	default:
+		return models.SetTypeWorking
+	}
+}
+
+// parseImportNumber lee un número que puede venir vacío o con coma decimal
+func parseImportNumber(value string) (float64, error) {
+	value = strings.TrimSpace(strings.Replace(value, ",", ".", 1))
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// importKey identifica una serie importada: formato, inicio del entrenamiento, ejercicio y posición
+func importKey(format, startedAt, exerciseName string, position int) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%s|%s|%d", format, startedAt, exerciseName, position)))
+	return hex.EncodeToString(sum[:])
+}
+
+// detectDelimiter elige entre coma y punto y coma según la primera línea
+func detectDelimiter(firstLine string) rune {
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		return ';'
+	}
+	return ','
+}
+
+// parseImportCSV lee un CSV de Strong o Hevy. Las filas inválidas se informan como errores sin cortar la lectura.
+func parseImportCSV(content string, weightUnit string) (string, []importRow, []models.ImportRowError, error) {
+	firstLine := content
+	if i := strings.IndexByte(content, '\n'); i >= 0 {
+		firstLine = content[:i]
+	}
+
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.Comma = detectDelimiter(firstLine)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return "", nil, nil, errUnknownImportFormat
+	}
+	format, cols, err := detectImportFormat(header, weightUnit)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	field := func(record []string, i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []importRow
+	var rowErrors []models.ImportRowError
+	// Posición de cada serie dentro de su ejercicio y entrenamiento
+	positions := make(map[string]int)
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			rowErrors = append(rowErrors, models.ImportRowError{Line: line, Reason: "fila mal formada"})
+			continue
+		}
+		if len(rows)+len(rowErrors) >= maxImportRows {
+			return "", nil, nil, fmt.Errorf("el archivo no puede tener más de %d filas", maxImportRows)
+		}
+
+		row := importRow{
+			Line:         line,
+			StartedAt:    field(record, cols.started),
+			WorkoutName:  field(record, cols.workout),
+			ExerciseName: field(record, cols.exercise),
+			Notes:        field(record, cols.notes),
+		}
+		if row.ExerciseName == "" {
+			rowErrors = append(rowErrors, models.ImportRowError{Line: line, Reason: "falta el nombre del ejercicio"})
+			continue
+		}
+		if row.Date, err = parseImportDate(row.StartedAt); err != nil {
+			rowErrors = append(rowErrors, models.ImportRowError{Line: line, Reason: err.Error()})
+			continue
+		}
+
+		weight, err := parseImportNumber(field(record, cols.weight))
+		if err != nil || weight < 0 {
+			rowErrors = append(rowErrors, models.ImportRowError{Line: line, Reason: "peso inválido"})
+			continue
+		}
+		if cols.weightInPounds {
+			weight *= poundsToKg
+		}
+		row.Weight = math.Round(weight*100) / 100
+
+		reps, err := parseImportNumber(field(record, cols.reps))
+		if err != nil || reps < 0 {
+			rowErrors = append(rowErrors, models.ImportRowError{Line: line, Reason: "repeticiones inválidas"})
+			continue
+		}
+		row.Reps = int(reps)
+
+		seconds, err := parseImportNumber(field(record, cols.seconds))
+		if err != nil || seconds < 0 {
+			rowErrors = append(rowErrors, models.ImportRowError{Line: line, Reason: "segundos inválidos"})
+			continue
+		}
+		if seconds > 0 {
+			s := int(seconds)
+			row.Seconds = &s
+		}
+
+		distance, err := parseImportNumber(field(record, cols.distance))
+		if err != nil || distance < 0 {
+			rowErrors = append(rowErrors, models.ImportRowError{Line: line, Reason: "distancia inválida"})
+			continue
+		}
+		if distance > 0 {
+			meters := distance * 1000
+			if cols.distanceInMiles {
+				meters = distance * metersPerMile
+			}
+			meters = math.Round(meters)
+			row.Distance = &meters
+		}
+
+		row.SetType = importSetType(field(record, cols.setType))
+		// Un RPE fuera de rango se descarta sin invalidar la serie
+		if rpe, err := parseImportNumber(field(record, cols.rpe)); err == nil && rpe >= 1 && rpe <= 10 {
+			rpe = math.Round(rpe*2) / 2
+			row.RPE = &rpe
+		}
+
+		if row.Reps == 0 && row.Seconds == nil && row.Distance == nil {
+			rowErrors = append(rowErrors, models.ImportRowError{Line: line, Reason: "la serie no tiene repeticiones, duración ni distancia"})
+			continue
+		}
+
+		positionKey := row.StartedAt + "|" + row.ExerciseName
+		positions[positionKey]++
+		row.Key = importKey(format, row.StartedAt, row.ExerciseName, positions[positionKey])
+		rows = append(rows, row)
+	}
+
+	return format, rows, rowErrors, nil
+}
+
+// normalizeExerciseName simplifica un nombre para compararlo con el catálogo
+func normalizeExerciseName(name string) string {
+	replacer := strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n")
+	name = replacer.Replace(strings.ToLower(strings.TrimSpace(name)))
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// catalogExercise representa un ejercicio del catálogo usado para resolver nombres
+type catalogExercise struct {
+	ID   int
+	Name string
+}
+
+// matchImportExercise resuelve un nombre del CSV: primero los mapeos del usuario,
+// después el nombre exacto del catálogo y por último el nombre sin el equipamiento entre paréntesis
+func matchImportExercise(sourceName string, mappings map[string]*int, catalog map[string]catalogExercise, byID map[int]catalogExercise) models.ImportExerciseMatch {
+	match := models.ImportExerciseMatch{SourceName: sourceName}
+
+	if mapped, ok := mappings[sourceName]; ok {
+		if mapped == nil {
+			match.MatchedBy = "skipped"
+			return match
+		}
+		if exercise, ok := byID[*mapped]; ok {
+			match.ExerciseID = &exercise.ID
+			match.ExerciseName = &exercise.Name
+			match.MatchedBy = "mapping"
+			return match
+		}
+	}
+
+	candidates := []string{normalizeExerciseName(sourceName)}
+	if i := strings.Index(sourceName, "("); i > 0 {
+		candidates = append(candidates, normalizeExerciseName(sourceName[:i]))
+	}
+	for _, candidate := range candidates {
+		if exercise, ok := catalog[candidate]; ok {
+			match.ExerciseID = &exercise.ID
+			match.ExerciseName = &exercise.Name
+			match.MatchedBy = "name"
+			return match
+		}
+	}
+
+	return match
+}
+
+// loadExerciseCatalog obtiene el catálogo de ejercicios indexado por nombre normalizado y por ID
+func loadExerciseCatalog() (map[string]catalogExercise, map[int]catalogExercise, error) {
+	rows, err := database.DB.Query("SELECT id, name FROM exercises")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	byName := make(map[string]catalogExercise)
+	byID := make(map[int]catalogExercise)
+	for rows.Next() {
+		var exercise catalogExercise
+		if err := rows.Scan(&exercise.ID, &exercise.Name); err != nil {
+			return nil, nil, err
+		}
+		byName[normalizeExerciseName(exercise.Name)] = exercise
+		byID[exercise.ID] = exercise
+	}
+	return byName, byID, rows.Err()
+}
+
+// getOrCreateImportedWorkoutDay busca el día del usuario o lo crea con el nombre del entrenamiento importado
+func getOrCreateImportedWorkoutDay(tx *sql.Tx, userID, date, name string) (int, bool, error) {
+	var workoutDayID int
+	err := tx.QueryRow(`SELECT id FROM workout_days WHERE user_id = $1 AND date = $2 AND deleted_at IS NULL`, userID, date).Scan(&workoutDayID)
+	if err == nil {
+		return workoutDayID, false, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, false, err
+	}
+
+	if name == "" {
+		name = defaultWorkoutDayName
+	}
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	err = tx.QueryRow(`
+		INSERT INTO workout_days (user_id, date, name, effort, mood)
+		VALUES ($1, $2, $3, 0, 0)
+		RETURNING id
+	`, userID, date, name).Scan(&workoutDayID)
+	return workoutDayID, true, err
+}
+
+// ImportWorkoutsHandler importa series desde un CSV exportado de Strong o Hevy (multipart, campo "file").
+// Por defecto solo devuelve una vista previa con los ejercicios sin reconocer; con commit=true guarda todo
+// en una transacción. Campos opcionales: mappings (JSON nombre -> exercise_id, o null para omitir)
+// y weight_unit (kg o lb, solo para Strong). Las series ya importadas se omiten.
+func ImportWorkoutsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize+(1<<20))
+	if err := r.ParseMultipartForm(maxImportFileSize); err != nil {
+		http.Error(w, fmt.Sprintf("Se esperaba un formulario multipart con el archivo (máximo %d MB)", maxImportFileSize>>20), http.StatusBadRequest)
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Falta el archivo CSV en el campo file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "Error leyendo el archivo", http.StatusBadRequest)
+		return
+	}
+
+	// Sin weight_unit se asume la unidad preferida del usuario
+	weightUnit := r.FormValue("weight_unit")
+	if weightUnit == "" {
+		weightUnit = getUserWeightUnit(userID)
+	}
+	if !isValidWeightUnit(weightUnit) {
+		http.Error(w, errInvalidWeightUnit.Error(), http.StatusBadRequest)
+		return
+	}
+
+	mappings := make(map[string]*int)
+	if raw := r.FormValue("mappings"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mappings); err != nil {
+			http.Error(w, "mappings inválido: usar un objeto JSON nombre -> exercise_id", http.StatusBadRequest)
+			return
+		}
+	}
+	commit := r.FormValue("commit") == "true"
+
+	format, rows, rowErrors, err := parseImportCSV(string(content), weightUnit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	catalog, byID, err := loadExerciseCatalog()
+	if err != nil {
+		fmt.Printf("Error cargando catálogo de ejercicios: %v\n", err)
+		http.Error(w, "Error cargando ejercicios", http.StatusInternalServerError)
+		return
+	}
+	for name, id := range mappings {
+		if id != nil {
+			if _, ok := byID[*id]; !ok {
+				http.Error(w, fmt.Sprintf("El ejercicio %d del mapeo de %q no existe", *id, name), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	summary := models.ImportSummary{
+		Format:           format,
+		TotalRows:        len(rows) + len(rowErrors),
+		InvalidRows:      len(rowErrors),
+		Exercises:        []models.ImportExerciseMatch{},
+		UnknownExercises: []string{},
+		Errors:           rowErrors,
+	}
+	if summary.Errors == nil {
+		summary.Errors = []models.ImportRowError{}
+	}
+
+	// No se importan series en el futuro del usuario
+	today := time.Now().In(getUserLocation(r, userID)).Format("2006-01-02")
+
+	matches := make(map[string]*models.ImportExerciseMatch)
+	var order []string
+	keys := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if _, ok := matches[row.ExerciseName]; !ok {
+			match := matchImportExercise(row.ExerciseName, mappings, catalog, byID)
+			matches[row.ExerciseName] = &match
+			order = append(order, row.ExerciseName)
+		}
+		matches[row.ExerciseName].Rows++
+		keys = append(keys, row.Key)
+	}
+
+	existing := make(map[string]bool)
+	if len(keys) > 0 {
+		keyRows, err := database.DB.Query(
+			"SELECT import_key FROM workouts WHERE user_id = $1 AND import_key = ANY($2)",
+			userID, pq.Array(keys),
+		)
+		if err != nil {
+			fmt.Printf("Error consultando series importadas: %v\n", err)
+			http.Error(w, "Error verificando series ya importadas", http.StatusInternalServerError)
+			return
+		}
+		for keyRows.Next() {
+			var key string
+			if err := keyRows.Scan(&key); err == nil {
+				existing[key] = true
+			}
+		}
+		keyRows.Close()
+	}
+
+	// Filas a importar agrupadas por fecha
+	toImport := make(map[string][]importRow)
+	for _, row := range rows {
+		match := matches[row.ExerciseName]
+		switch {
+		case row.Date > today:
+			summary.InvalidRows++
+			summary.Errors = append(summary.Errors, models.ImportRowError{Line: row.Line, Reason: "la fecha es posterior a hoy"})
+		case existing[row.Key]:
+			summary.DuplicateRows++
+		case match.MatchedBy == "skipped":
+			summary.SkippedRows++
+		case match.ExerciseID == nil:
+			summary.UnknownRows++
+		default:
+			summary.ImportableRows++
+			toImport[row.Date] = append(toImport[row.Date], row)
+		}
+	}
+
+	for _, name := range order {
+		match := matches[name]
+		summary.Exercises = append(summary.Exercises, *match)
+		if match.ExerciseID == nil && match.MatchedBy != "skipped" {
+			summary.UnknownExercises = append(summary.UnknownExercises, name)
+		}
+	}
+
+	dates := make([]string, 0, len(toImport))
+	for date := range toImport {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	summary.Days = len(dates)
+	if len(dates) > 0 {
+		summary.From = dates[0]
+		summary.To = dates[len(dates)-1]
+	}
+
+	if !commit {
+		json.NewEncoder(w).Encode(summary)
+		return
+	}
+
+	// Para confirmar, todos los ejercicios tienen que estar resueltos (o mapeados como omitidos)
+	if len(summary.UnknownExercises) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(summary)
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	// La distancia solo se guarda si el ejercicio se mide por distancia y el peso corporal del día
+	// solo si es un ejercicio con peso corporal
+	insertQuery := `
+		INSERT INTO workouts (user_id, workout_day_id, exercise_id, weight, reps, set, seconds, observations, set_type, rpe, import_key,
+			distance_meters, bodyweight_kg)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
+			CASE WHEN (SELECT tracks_distance FROM exercises WHERE id = $3) THEN $12::double precision END,
+			CASE WHEN (SELECT bodyweight FROM exercises WHERE id = $3) THEN $13::double precision END)
+		ON CONFLICT (user_id, import_key) WHERE import_key IS NOT NULL DO NOTHING
+		RETURNING id
+	`
+
+	var importedIDs []int
+
+	for _, date := range dates {
+		dayRows := toImport[date]
+		workoutDayID, created, err := getOrCreateImportedWorkoutDay(tx, userID, date, dayRows[0].WorkoutName)
+		if err != nil {
+			fmt.Printf("Error creando día importado %s: %v\n", date, err)
+			http.Error(w, "Error guardando la importación", http.StatusInternalServerError)
+			return
+		}
+		if created {
+			summary.CreatedDays++
+		}
+
+		bodyweight, err := loadBodyweightOn(tx, userID, workoutDayID)
+		if err != nil {
+			fmt.Printf("Error obteniendo peso corporal del día importado %s: %v\n", date, err)
+			http.Error(w, "Error guardando la importación", http.StatusInternalServerError)
+			return
+		}
+
+		// Las series se numeran a continuación de las que ya tenga el día
+		nextSet := make(map[int]int)
+		setRows, err := tx.Query(`
+			SELECT exercise_id, MAX(set) FROM workouts
+			WHERE user_id = $1 AND workout_day_id = $2 AND deleted_at IS NULL
+			GROUP BY exercise_id
+		`, userID, workoutDayID)
+		if err != nil {
+			fmt.Printf("Error consultando series del día importado: %v\n", err)
+			http.Error(w, "Error guardando la importación", http.StatusInternalServerError)
+			return
+		}
+		for setRows.Next() {
+			var exerciseID, maxSet int
+			if err := setRows.Scan(&exerciseID, &maxSet); err == nil {
+				nextSet[exerciseID] = maxSet
+			}
+		}
+		setRows.Close()
+
+		for _, row := range dayRows {
+			exerciseID := *matches[row.ExerciseName].ExerciseID
+			if nextSet[exerciseID] >= maxSetNumber {
+				summary.ImportableRows--
+				summary.InvalidRows++
+				summary.Errors = append(summary.Errors, models.ImportRowError{
+					Line:   row.Line,
+					Reason: fmt.Sprintf("el ejercicio ya tiene %d series ese día", maxSetNumber),
+				})
+				continue
+			}
+
+			var workoutID int
+			err := tx.QueryRow(
+				insertQuery,
+				userID, workoutDayID, exerciseID, row.Weight, row.Reps,
+				nextSet[exerciseID]+1, row.Seconds, row.Notes, row.SetType, row.RPE, row.Key, row.Distance, bodyweight,
+			).Scan(&workoutID)
+			if err == sql.ErrNoRows {
+				// Importada en paralelo por otra request
+				summary.ImportableRows--
+				summary.DuplicateRows++
+				continue
+			}
+			if err != nil {
+				fmt.Printf("Error importando fila %d: %v\n", row.Line, err)
+				http.Error(w, "Error guardando la importación", http.StatusInternalServerError)
+				return
+			}
+			nextSet[exerciseID]++
+			summary.ImportedSets++
+			importedIDs = append(importedIDs, workoutID)
+		}
+	}
+
+	// Los récords se detectan en orden de fecha, igual que si las series se hubieran cargado una a una
+	_, beaten := detectPersonalRecordsInTx(tx, userID, importedIDs...)
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, "Error confirmando transacción", http.StatusInternalServerError)
+		return
+	}
+	notifyPersonalRecords(userID, beaten)
+
+	fmt.Printf("Importación %s: %d series en %d días para el usuario %s\n", format, summary.ImportedSets, summary.Days, userID)
+
+	summary.Committed = true
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(summary)
+}
@@ -0,0 +1,103 @@
+//go:build integration
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/goalritmo/gym/backend/testutils"
+)
+
+// TestSupabaseImportWorkoutsCommit prueba que las series importadas guardan el peso corporal del día
+// en los ejercicios con peso corporal y que la importación detecta récords personales
+func TestSupabaseImportWorkoutsCommit(t *testing.T) {
+	testutils.SetupTestDatabase(t)
+
+	var exerciseID int
+	err := database.DB.QueryRow("SELECT id FROM exercises WHERE bodyweight ORDER BY id LIMIT 1").Scan(&exerciseID)
+	if err != nil {
+		t.Skipf("No hay ejercicios con peso corporal en la base de prueba: %v", err)
+	}
+
+	testUserID := testutils.GetTestUserID(t)
+	testutils.CreateTestUserInDB(t, testUserID)
+	t.Cleanup(func() {
+		testutils.CleanupTestUser(t, testUserID)
+	})
+
+	_, err = database.DB.Exec(`
+		INSERT INTO body_measurements (user_id, measurement_type, value, measured_on) VALUES ($1, $2, 80, '2024-01-01')
+	`, testUserID, models.MeasurementBodyweight)
+	if err != nil {
+		t.Fatalf("Error creando medida: %v", err)
+	}
+
+	content := "Date;Workout Name;Duration;Exercise Name;Set Order;Weight;Reps;Distance;Seconds;Notes;Workout Notes;RPE\n" +
+		"2024-02-01 10:00:00;Test;1h;Importado;1;0;10;0;0;;;\n" +
+		"2024-02-08 10:00:00;Test;1h;Importado;1;0;12;0;0;;;\n"
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	file, err := form.CreateFormFile("file", "strong.csv")
+	if err != nil {
+		t.Fatalf("Error armando el formulario: %v", err)
+	}
+	file.Write([]byte(content))
+	form.WriteField("weight_unit", "kg")
+	form.WriteField("mappings", fmt.Sprintf(`{"Importado": %d}`, exerciseID))
+	form.WriteField("commit", "true")
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/me/import", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req = req.WithContext(context.WithValue(req.Context(), "user_id", testUserID))
+	rr := httptest.NewRecorder()
+	ImportWorkoutsHandler(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status %d, se esperaba %d: %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT w.id, w.bodyweight_kg
+		FROM workouts w
+		JOIN workout_days wd ON w.workout_day_id = wd.id
+		WHERE w.user_id = $1
+		ORDER BY wd.date
+	`, testUserID)
+	if err != nil {
+		t.Fatalf("Error leyendo series importadas: %v", err)
+	}
+	var workoutIDs []int
+	for rows.Next() {
+		var id int
+		var bodyweight *float64
+		if err := rows.Scan(&id, &bodyweight); err != nil {
+			t.Fatalf("Error escaneando serie: %v", err)
+		}
+		if bodyweight == nil || *bodyweight != 80 {
+			t.Errorf("serie %d: peso corporal %v, se esperaba 80", id, bodyweight)
+		}
+		workoutIDs = append(workoutIDs, id)
+	}
+	rows.Close()
+	if len(workoutIDs) != 2 {
+		t.Fatalf("se importaron %d series, se esperaban 2", len(workoutIDs))
+	}
+
+	var records int
+	err = database.DB.QueryRow("SELECT COUNT(*) FROM personal_records WHERE user_id = $1 AND workout_id = $2", testUserID, workoutIDs[1]).Scan(&records)
+	if err != nil {
+		t.Fatalf("Error leyendo récords: %v", err)
+	}
+	if records == 0 {
+		t.Errorf("la segunda serie importada debería batir récords de la primera")
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+func TestParseImportCSVStrong(t *testing.T) {
+	content := "Date;Workout Name;Duration;Exercise Name;Set Order;Weight;Reps;Distance;Seconds;Notes;Workout Notes;RPE\n" +
+		"2023-01-15 10:30:00;Fullbody A;1h;Bench Press (Barbell);W;100;5;0;0;;;\n" +
+		"2023-01-15 10:30:00;Fullbody A;1h;Bench Press (Barbell);1;100;4;0;0;;;8,5\n" +
+		"2023-01-15 10:30:00;Fullbody A;1h;Plank;1;0;0;0;60;;;\n" +
+		"2023-01-15 10:30:00;Fullbody A;1h;Squat (Barbell);1;abc;5;0;0;;;\n"
+
+	format, rows, rowErrors, err := parseImportCSV(content, "lb")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if format != models.ImportFormatStrong {
+		t.Errorf("formato incorrecto: %s", format)
+	}
+	if len(rows) != 3 || len(rowErrors) != 1 || rowErrors[0].Line != 5 {
+		t.Fatalf("filas incorrectas: %d filas, errores %+v", len(rows), rowErrors)
+	}
+	if rows[0].Date != "2023-01-15" || rows[0].Weight != 45.36 || rows[0].Reps != 5 {
+		t.Errorf("primera fila incorrecta: %+v", rows[0])
+	}
+	if rows[0].SetType != models.SetTypeWarmup || rows[1].SetType != models.SetTypeWorking {
+		t.Errorf("tipos de serie incorrectos: %s %s", rows[0].SetType, rows[1].SetType)
+	}
+	if rows[1].RPE == nil || *rows[1].RPE != 8.5 {
+		t.Errorf("RPE incorrecto: %v", rows[1].RPE)
+	}
+	if rows[0].Key == rows[1].Key {
+		t.Error("dos series distintas no pueden tener la misma clave")
+	}
+	if rows[2].Seconds == nil || *rows[2].Seconds != 60 {
+		t.Errorf("la plancha debería tener 60 segundos: %+v", rows[2])
+	}
+}
+
+func TestParseImportCSVHevy(t *testing.T) {
+	content := "title,start_time,end_time,description,exercise_title,superset_id,exercise_notes,set_index,set_type,weight_kg,reps,distance_km,duration_seconds,rpe\n" +
+		"\"Push\",\"15 Jan 2023, 10:30\",\"15 Jan 2023, 11:30\",\"\",\"Bench Press (Barbell)\",,\"\",0,normal,80,8,,,\n"
+
+	format, rows, rowErrors, err := parseImportCSV(content, "kg")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if format != models.ImportFormatHevy || len(rows) != 1 || len(rowErrors) != 0 {
+		t.Fatalf("resultado incorrecto: %s %d %v", format, len(rows), rowErrors)
+	}
+	if rows[0].Date != "2023-01-15" || rows[0].Weight != 80 || rows[0].WorkoutName != "Push" {
+		t.Errorf("fila incorrecta: %+v", rows[0])
+	}
+}
+
+func TestParseImportCSVUnknownFormat(t *testing.T) {
+	if _, _, _, err := parseImportCSV("a,b,c\n1,2,3\n", "kg"); err != errUnknownImportFormat {
+		t.Errorf("se esperaba errUnknownImportFormat, got %v", err)
+	}
+}
+
+func TestMatchImportExercise(t *testing.T) {
+	catalog := map[string]catalogExercise{
+		"bench press": {ID: 1, Name: "Bench Press"},
+		"sentadilla":  {ID: 2, Name: "Sentadilla"},
+	}
+	byID := map[int]catalogExercise{1: catalog["bench press"], 2: catalog["sentadilla"]}
+	squat := 2
+	mappings := map[string]*int{"Squat (Barbell)": &squat, "Face Pull": nil}
+
+	tests := []struct {
+		name      string
+		matchedBy string
+		wantID    int
+	}{
+		{"Bench Press (Barbell)", "name", 1},
+		{"Squat (Barbell)", "mapping", 2},
+		{"Face Pull", "skipped", 0},
+		{"Deadlift (Barbell)", "", 0},
+	}
+
+	for _, tt := range tests {
+		match := matchImportExercise(tt.name, mappings, catalog, byID)
+		if match.MatchedBy != tt.matchedBy {
+			t.Errorf("%s: matched_by %q, want %q", tt.name, match.MatchedBy, tt.matchedBy)
+		}
+		if tt.wantID != 0 && (match.ExerciseID == nil || *match.ExerciseID != tt.wantID) {
+			t.Errorf("%s: exercise_id incorrecto: %v", tt.name, match.ExerciseID)
+		}
+	}
+}
+
+func TestImportSetType(t *testing.T) {
+	tests := map[string]string{
+		"1":       models.SetTypeWorking,
+		"W":       models.SetTypeWarmup,
+		"warmup":  models.SetTypeWarmup,
+		"dropset": models.SetTypeDrop,
+		"F":       models.SetTypeFailure,
+		"normal":  models.SetTypeWorking,
+		"":        models.SetTypeWorking,
+	}
+
+	for value, want := range tests {
+		if got := importSetType(value); got != want {
+			t.Errorf("importSetType(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
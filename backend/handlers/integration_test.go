@@ -1,3 +1,5 @@
+//go:build integration
+
 package handlers
 
 import (
@@ -63,10 +65,10 @@ func TestEndToEndWorkoutFlow(t *testing.T) {
 	t.Run("Create workout", func(t *testing.T) {
 		workoutData := models.CreateWorkoutRequest{
 			ExerciseID:   1,
-			Weight:       80.5,
-			Reps:         10,
-			Serie:        intPtr(1),
-			Observations: stringPtr("Test workout"),
+			Weight:       floatPtr(80.5),
+			Reps:         intPtr(10),
+			Set:          intPtr(1),
+			Observations: "Test workout",
 		}
 
 		body, _ := json.Marshal(workoutData)
@@ -104,8 +106,8 @@ func TestEndToEndWorkoutFlow(t *testing.T) {
 func BenchmarkCreateWorkout(b *testing.B) {
 	workoutData := models.CreateWorkoutRequest{
 		ExerciseID: 1,
-		Weight:     80.5,
-		Reps:       10,
+		Weight:     floatPtr(80.5),
+		Reps:       intPtr(10),
 	}
 
 	body, _ := json.Marshal(workoutData)
@@ -35,7 +35,7 @@ func CreateKudosNotificationHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Verificar que existe el workout day
 	var workoutDate time.Time
-	err = database.DB.QueryRow("SELECT created_at FROM workout_days WHERE id = $1 AND user_id = $2", req.WorkoutDayID, req.ToUserID).Scan(&workoutDate)
+	err = database.DB.QueryRow("SELECT created_at FROM workout_days WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL", req.WorkoutDayID, req.ToUserID).Scan(&workoutDate)
 	if err != nil {
 		http.Error(w, "Entrenamiento no encontrado", http.StatusNotFound)
 		return
@@ -0,0 +1,542 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+)
+
+const (
+	// liveSessionTTL es el tiempo sin actividad tras el cual una sesión en vivo vence
+	liveSessionTTL = 4 * time.Hour
+	// defaultRestSeconds es el descanso que se usa si no hay uno planificado
+	defaultRestSeconds = 90
+	maxRestSeconds     = 3600
+)
+
+var (
+	errLiveSessionNotFound = errors.New("no hay una sesión en curso")
+	errInvalidRestSeconds  = fmt.Errorf("el descanso debe estar entre 0 y %d segundos", maxRestSeconds)
+)
+
+// liveRestRemaining devuelve los segundos de descanso que faltan en now.
+// Con el descanso pausado el tiempo queda congelado en paused_at.
+func liveRestRemaining(session *models.LiveSession, now time.Time) int {
+	if session.RestStartedAt == nil || session.RestDurationSeconds == nil {
+		return 0
+	}
+	end := now
+	if session.PausedAt != nil {
+		end = *session.PausedAt
+	}
+	remaining := time.Duration(*session.RestDurationSeconds)*time.Second - end.Sub(*session.RestStartedAt)
+	if remaining <= 0 {
+		return 0
+	}
+	return int(math.Ceil(remaining.Seconds()))
+}
+
+// fillLiveSessionStatus calcula el estado, el descanso restante y el vencimiento de la sesión en now
+func fillLiveSessionStatus(session *models.LiveSession, now time.Time) {
+	session.ServerTime = now
+	session.ExpiresAt = session.UpdatedAt.Add(liveSessionTTL)
+	session.RestRemainingSeconds = liveRestRemaining(session, now)
+	session.RestEndsAt = nil
+
+	switch {
+	case session.RestRemainingSeconds == 0:
+		session.Status = models.LiveSessionActive
+	case session.PausedAt != nil:
+		session.Status = models.LiveSessionPaused
+	default:
+		session.Status = models.LiveSessionResting
+		ends := session.RestStartedAt.Add(time.Duration(*session.RestDurationSeconds) * time.Second)
+		session.RestEndsAt = &ends
+	}
+}
+
+// nextLiveSet determina la próxima serie. Con una rutina iniciada es la primera pendiente del
+// ejercicio actual o, si ya las terminó, la primera pendiente del plan. Si el ejercicio actual
+// no está en la rutina (o no hay rutina) es la siguiente serie de ese ejercicio.
+func nextLiveSet(day *models.WorkoutDayWithExercises, currentExerciseID *int, currentExerciseName string) *models.LiveSessionNextSet {
+	inPlan := false
+	if day.Plan != nil {
+		var first *models.LiveSessionNextSet
+		for _, exercise := range day.Plan.Exercises {
+			current := currentExerciseID != nil && exercise.ExerciseID == *currentExerciseID
+			inPlan = inPlan || current
+			for _, set := range exercise.Sets {
+				if set.Done {
+					continue
+				}
+				reps := set.PlannedReps
+				rest := exercise.RestTimeSeconds
+				next := &models.LiveSessionNextSet{
+					ExerciseID:      exercise.ExerciseID,
+					ExerciseName:    exercise.ExerciseName,
+					Set:             set.Set,
+					PlannedReps:     &reps,
+					PlannedWeight:   set.PlannedWeight,
+					RestTimeSeconds: &rest,
+					FromRoutine:     true,
+				}
+				if current {
+					return next
+				}
+				if first == nil {
+					first = next
+				}
+				break
+			}
+		}
+		if first != nil && (currentExerciseID == nil || inPlan) {
+			return first
+		}
+	}
+
+	if currentExerciseID == nil {
+		return nil
+	}
+	next := &models.LiveSessionNextSet{
+		ExerciseID:   *currentExerciseID,
+		ExerciseName: currentExerciseName,
+		Set:          1,
+	}
+	for _, group := range day.ExerciseGroups {
+		if group.ExerciseID != *currentExerciseID {
+			continue
+		}
+		for _, workout := range group.Workouts {
+			if workout.Set >= next.Set {
+				next.Set = workout.Set + 1
+			}
+		}
+	}
+	return next
+}
+
+// loadLiveSession obtiene la sesión en vivo del usuario. Antes borra su sesión si venció,
+// así una sesión sin actividad nunca se devuelve. El estado se calcula con la hora de la base.
+func loadLiveSession(q dbQuerier, userID string) (*models.LiveSession, error) {
+	_, err := q.Exec(`
+		DELETE FROM live_sessions WHERE user_id = $1 AND updated_at < NOW() - $2 * INTERVAL '1 second'
+	`, userID, liveSessionTTL.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	var session models.LiveSession
+	var now time.Time
+	err = q.QueryRow(`
+		SELECT ls.user_id, ls.workout_day_id, wd.routine_id, ls.current_exercise_id, e.name,
+			ls.rest_started_at, ls.rest_duration_seconds, ls.paused_at, ls.started_at, ls.updated_at, NOW()
+		FROM live_sessions ls
+		JOIN workout_days wd ON wd.id = ls.workout_day_id
+		LEFT JOIN exercises e ON e.id = ls.current_exercise_id
+		WHERE ls.user_id = $1
+	`, userID).Scan(
+		&session.UserID,
+		&session.WorkoutDayID,
+		&session.RoutineID,
+		&session.CurrentExerciseID,
+		&session.CurrentExerciseName,
+		&session.RestStartedAt,
+		&session.RestDurationSeconds,
+		&session.PausedAt,
+		&session.StartedAt,
+		&session.UpdatedAt,
+		&now,
+	)
+	if err == sql.ErrNoRows {
+		return nil, errLiveSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fillLiveSessionStatus(&session, now)
+	return &session, nil
+}
+
+// loadPlannedRestSeconds devuelve el descanso planificado para el ejercicio en la rutina
+// iniciada en el día, o defaultRestSeconds si no hay rutina o el ejercicio no está en ella
+func loadPlannedRestSeconds(q dbQuerier, workoutDayID, exerciseID int) (int, error) {
+	var rest int
+	err := q.QueryRow(`
+		SELECT re.rest_time_seconds
+		FROM routine_exercises re
+		JOIN workout_days wd ON wd.routine_id = re.routine_id
+		JOIN user_routines ur ON ur.id = re.routine_id
+		WHERE wd.id = $1 AND re.exercise_id = $2 AND ur.deleted_at IS NULL
+		ORDER BY re.order_index ASC, re.id ASC
+		LIMIT 1
+	`, workoutDayID, exerciseID).Scan(&rest)
+	if err == sql.ErrNoRows {
+		return defaultRestSeconds, nil
+	}
+	return rest, err
+}
+
+// advanceLiveSession actualiza la sesión en vivo al registrar una serie en su día: el ejercicio
+// pasa a ser el actual y arranca el descanso planificado. Sin sesión en ese día no hace nada.
+func advanceLiveSession(userID string, workoutDayID, exerciseID int) {
+	rest, err := loadPlannedRestSeconds(database.DB, workoutDayID, exerciseID)
+	if err != nil {
+		fmt.Printf("Error obteniendo descanso planificado: %v\n", err)
+		return
+	}
+
+	_, err = database.DB.Exec(`
+		UPDATE live_sessions
+		SET current_exercise_id = $3,
+			rest_started_at = NOW(),
+			rest_duration_seconds = $4,
+			paused_at = NULL,
+			updated_at = NOW()
+		WHERE user_id = $1 AND workout_day_id = $2
+			AND updated_at >= NOW() - $5 * INTERVAL '1 second'
+	`, userID, workoutDayID, exerciseID, rest, liveSessionTTL.Seconds())
+	if err != nil {
+		fmt.Printf("Error actualizando sesión en vivo: %v\n", err)
+	}
+}
+
+// writeLiveSessionError responde con el status correspondiente a un error de loadLiveSession
+func writeLiveSessionError(w http.ResponseWriter, err error) {
+	if err == errLiveSessionNotFound {
+		http.Error(w, "No hay una sesión en curso", http.StatusNotFound)
+		return
+	}
+	fmt.Printf("Error obteniendo sesión en vivo: %v\n", err)
+	http.Error(w, "Error obteniendo sesión en vivo", http.StatusInternalServerError)
+}
+
+// writeLiveSession responde con la sesión en vivo del usuario, con la próxima serie
+// y los horarios en su zona horaria
+func writeLiveSession(w http.ResponseWriter, r *http.Request, userID string, status int) {
+	session, err := loadLiveSession(database.DB, userID)
+	if err != nil {
+		writeLiveSessionError(w, err)
+		return
+	}
+
+	loc := getUserLocation(r, userID)
+	day, err := loadWorkoutDayWithExercises(database.DB, userID, session.WorkoutDayID, loc)
+	if err != nil {
+		writeWorkoutDayError(w, err)
+		return
+	}
+
+	currentExerciseName := ""
+	if session.CurrentExerciseName != nil {
+		currentExerciseName = *session.CurrentExerciseName
+	}
+	session.NextSet = nextLiveSet(day, session.CurrentExerciseID, currentExerciseName)
+	if session.NextSet != nil {
+		session.NextSet.WeightUnit = getUserWeightUnit(userID)
+		session.NextSet.PlannedWeight = weightPtrFromKilograms(session.NextSet.PlannedWeight, session.NextSet.WeightUnit)
+	}
+
+	for _, t := range []*time.Time{session.RestStartedAt, session.PausedAt, session.RestEndsAt} {
+		if t != nil {
+			*t = convertToUserTime(*t, loc)
+		}
+	}
+	session.StartedAt = convertToUserTime(session.StartedAt, loc)
+	session.UpdatedAt = convertToUserTime(session.UpdatedAt, loc)
+	session.ExpiresAt = convertToUserTime(session.ExpiresAt, loc)
+	session.ServerTime = convertToUserTime(session.ServerTime, loc)
+
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(session)
+}
+
+// GetLiveSessionHandler obtiene la sesión en vivo del usuario para retomarla desde cualquier dispositivo
+func GetLiveSessionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	writeLiveSession(w, r, userID, http.StatusOK)
+}
+
+// StartLiveSessionHandler inicia una sesión en vivo en un día de entrenamiento (hoy por defecto).
+// Si el usuario ya tenía una, la reemplaza.
+func StartLiveSessionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	// El body es opcional
+	var req models.StartLiveSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	if req.CurrentExerciseID != nil {
+		if _, err := loadExerciseKind(database.DB, *req.CurrentExerciseID); err != nil {
+			writeExerciseKindError(w, err)
+			return
+		}
+	}
+
+	loc := getUserLocation(r, userID)
+	workoutDayID, err := resolveWorkoutDayID(database.DB, userID, loc, req.Date, req.WorkoutDayID)
+	if err != nil {
+		writeWorkoutDayError(w, err)
+		return
+	}
+
+	_, err = database.DB.Exec(`
+		INSERT INTO live_sessions (user_id, workout_day_id, current_exercise_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET workout_day_id = EXCLUDED.workout_day_id,
+			current_exercise_id = EXCLUDED.current_exercise_id,
+			rest_started_at = NULL,
+			rest_duration_seconds = NULL,
+			paused_at = NULL,
+			started_at = NOW(),
+			updated_at = NOW()
+	`, userID, workoutDayID, req.CurrentExerciseID)
+	if err != nil {
+		fmt.Printf("Error iniciando sesión en vivo: %v\n", err)
+		http.Error(w, "Error iniciando sesión en vivo", http.StatusInternalServerError)
+		return
+	}
+
+	writeLiveSession(w, r, userID, http.StatusCreated)
+}
+
+// UpdateLiveSessionHandler cambia el ejercicio actual de la sesión en vivo
+func UpdateLiveSessionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.UpdateLiveSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if req.CurrentExerciseID == nil || *req.CurrentExerciseID <= 0 {
+		http.Error(w, "current_exercise_id es requerido", http.StatusBadRequest)
+		return
+	}
+	if _, err := loadExerciseKind(database.DB, *req.CurrentExerciseID); err != nil {
+		writeExerciseKindError(w, err)
+		return
+	}
+
+	if _, err := loadLiveSession(database.DB, userID); err != nil {
+		writeLiveSessionError(w, err)
+		return
+	}
+
+	_, err := database.DB.Exec(`
+		UPDATE live_sessions SET current_exercise_id = $2, updated_at = NOW() WHERE user_id = $1
+	`, userID, *req.CurrentExerciseID)
+	if err != nil {
+		fmt.Printf("Error actualizando sesión en vivo: %v\n", err)
+		http.Error(w, "Error actualizando sesión en vivo", http.StatusInternalServerError)
+		return
+	}
+
+	writeLiveSession(w, r, userID, http.StatusOK)
+}
+
+// StartLiveRestHandler inicia el descanso. Sin seconds usa el descanso planificado del
+// ejercicio actual en la rutina del día, o defaultRestSeconds.
+func StartLiveRestHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	// El body es opcional
+	var req models.StartRestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if req.Seconds != nil && (*req.Seconds < 0 || *req.Seconds > maxRestSeconds) {
+		http.Error(w, errInvalidRestSeconds.Error(), http.StatusBadRequest)
+		return
+	}
+
+	session, err := loadLiveSession(database.DB, userID)
+	if err != nil {
+		writeLiveSessionError(w, err)
+		return
+	}
+
+	rest := defaultRestSeconds
+	switch {
+	case req.Seconds != nil:
+		rest = *req.Seconds
+	case session.CurrentExerciseID != nil:
+		rest, err = loadPlannedRestSeconds(database.DB, session.WorkoutDayID, *session.CurrentExerciseID)
+		if err != nil {
+			fmt.Printf("Error obteniendo descanso planificado: %v\n", err)
+			http.Error(w, "Error obteniendo descanso planificado", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	_, err = database.DB.Exec(`
+		UPDATE live_sessions
+		SET rest_started_at = NOW(), rest_duration_seconds = $2, paused_at = NULL, updated_at = NOW()
+		WHERE user_id = $1
+	`, userID, rest)
+	if err != nil {
+		fmt.Printf("Error iniciando descanso: %v\n", err)
+		http.Error(w, "Error iniciando descanso", http.StatusInternalServerError)
+		return
+	}
+
+	writeLiveSession(w, r, userID, http.StatusOK)
+}
+
+// StopLiveRestHandler termina el descanso antes de tiempo
+func StopLiveRestHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	if _, err := loadLiveSession(database.DB, userID); err != nil {
+		writeLiveSessionError(w, err)
+		return
+	}
+
+	_, err := database.DB.Exec(`
+		UPDATE live_sessions
+		SET rest_started_at = NULL, rest_duration_seconds = NULL, paused_at = NULL, updated_at = NOW()
+		WHERE user_id = $1
+	`, userID)
+	if err != nil {
+		fmt.Printf("Error terminando descanso: %v\n", err)
+		http.Error(w, "Error terminando descanso", http.StatusInternalServerError)
+		return
+	}
+
+	writeLiveSession(w, r, userID, http.StatusOK)
+}
+
+// PauseLiveRestHandler pausa el descanso en curso
+func PauseLiveRestHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	session, err := loadLiveSession(database.DB, userID)
+	if err != nil {
+		writeLiveSessionError(w, err)
+		return
+	}
+	if session.Status != models.LiveSessionResting {
+		http.Error(w, "No hay un descanso en curso", http.StatusConflict)
+		return
+	}
+
+	_, err = database.DB.Exec(`
+		UPDATE live_sessions SET paused_at = NOW(), updated_at = NOW() WHERE user_id = $1 AND paused_at IS NULL
+	`, userID)
+	if err != nil {
+		fmt.Printf("Error pausando descanso: %v\n", err)
+		http.Error(w, "Error pausando descanso", http.StatusInternalServerError)
+		return
+	}
+
+	writeLiveSession(w, r, userID, http.StatusOK)
+}
+
+// ResumeLiveRestHandler reanuda el descanso pausado. El inicio se corre lo que duró la pausa,
+// así el tiempo restante es el mismo que al pausar.
+func ResumeLiveRestHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	session, err := loadLiveSession(database.DB, userID)
+	if err != nil {
+		writeLiveSessionError(w, err)
+		return
+	}
+	if session.Status != models.LiveSessionPaused {
+		http.Error(w, "El descanso no está pausado", http.StatusConflict)
+		return
+	}
+
+	_, err = database.DB.Exec(`
+		UPDATE live_sessions
+		SET rest_started_at = rest_started_at + (NOW() - paused_at), paused_at = NULL, updated_at = NOW()
+		WHERE user_id = $1 AND paused_at IS NOT NULL
+	`, userID)
+	if err != nil {
+		fmt.Printf("Error reanudando descanso: %v\n", err)
+		http.Error(w, "Error reanudando descanso", http.StatusInternalServerError)
+		return
+	}
+
+	writeLiveSession(w, r, userID, http.StatusOK)
+}
+
+// EndLiveSessionHandler termina la sesión en vivo. Las series registradas no se modifican.
+func EndLiveSessionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := database.DB.Exec(`DELETE FROM live_sessions WHERE user_id = $1`, userID)
+	if err != nil {
+		fmt.Printf("Error terminando sesión en vivo: %v\n", err)
+		http.Error(w, "Error terminando sesión en vivo", http.StatusInternalServerError)
+		return
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		http.Error(w, "No hay una sesión en curso", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
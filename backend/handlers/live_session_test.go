@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+func TestFillLiveSessionStatus(t *testing.T) {
+	start := time.Date(2024, 5, 10, 18, 0, 0, 0, time.UTC)
+	duration := 90
+	pausedAt := start.Add(20 * time.Second)
+
+	tests := []struct {
+		name      string
+		pausedAt  *time.Time
+		now       time.Time
+		status    string
+		remaining int
+	}{
+		{"descanso corriendo", nil, start.Add(30 * time.Second), models.LiveSessionResting, 60},
+		{"redondea hacia arriba", nil, start.Add(30*time.Second + 200*time.Millisecond), models.LiveSessionResting, 60},
+		{"descanso terminado", nil, start.Add(2 * time.Minute), models.LiveSessionActive, 0},
+		{"pausado congela el tiempo", &pausedAt, start.Add(10 * time.Minute), models.LiveSessionPaused, 70},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := models.LiveSession{
+				RestStartedAt:       &start,
+				RestDurationSeconds: &duration,
+				PausedAt:            tt.pausedAt,
+				UpdatedAt:           start,
+			}
+			fillLiveSessionStatus(&session, tt.now)
+			if session.Status != tt.status || session.RestRemainingSeconds != tt.remaining {
+				t.Errorf("estado %s con %d segundos, se esperaba %s con %d", session.Status, session.RestRemainingSeconds, tt.status, tt.remaining)
+			}
+			if (session.RestEndsAt != nil) != (tt.status == models.LiveSessionResting) {
+				t.Errorf("rest_ends_at solo debería estar con el descanso corriendo: %v", session.RestEndsAt)
+			}
+			if !session.ExpiresAt.Equal(start.Add(liveSessionTTL)) {
+				t.Errorf("vencimiento incorrecto: %v", session.ExpiresAt)
+			}
+		})
+	}
+
+	var idle models.LiveSession
+	fillLiveSessionStatus(&idle, start)
+	if idle.Status != models.LiveSessionActive || idle.RestRemainingSeconds != 0 {
+		t.Errorf("sin descanso la sesión debería estar activa: %+v", idle)
+	}
+}
+
+func TestNextLiveSet(t *testing.T) {
+	weight := 60.0
+	planned := []models.RoutineExercise{
+		{ID: 1, ExerciseID: 10, ExerciseName: "Sentadilla", Sets: 2, Reps: 8, Weight: &weight, RestTimeSeconds: 120},
+		{ID: 2, ExerciseID: 20, ExerciseName: "Dominadas", Sets: 2, Reps: 6, RestTimeSeconds: 90},
+	}
+	workouts := []models.Workout{
+		{ID: 100, ExerciseID: 10, Set: 1, Weight: 60, Reps: 8},
+		{ID: 101, ExerciseID: 30, Set: 1, Weight: 10, Reps: 12},
+		{ID: 102, ExerciseID: 30, Set: 2, Weight: 10, Reps: 12},
+	}
+	plans, unplanned := matchRoutinePlan(planned, workouts)
+	day := &models.WorkoutDayWithExercises{
+		ExerciseGroups: []models.ExerciseGroup{
+			{ExerciseID: 10, Workouts: workouts[:1]},
+			{ExerciseID: 30, ExerciseName: "Curl", Workouts: workouts[1:]},
+		},
+		Plan: &models.WorkoutDayPlan{Exercises: plans, UnplannedWorkouts: unplanned},
+	}
+
+	next := nextLiveSet(day, nil, "")
+	if next == nil || next.ExerciseID != 10 || next.Set != 2 || !next.FromRoutine || *next.RestTimeSeconds != 120 {
+		t.Errorf("sin ejercicio actual debería seguir la primera serie pendiente: %+v", next)
+	}
+
+	dominadas := 20
+	next = nextLiveSet(day, &dominadas, "Dominadas")
+	if next == nil || next.ExerciseID != 20 || next.Set != 1 || *next.PlannedReps != 6 {
+		t.Errorf("debería seguir la serie pendiente del ejercicio actual: %+v", next)
+	}
+
+	curl := 30
+	next = nextLiveSet(day, &curl, "Curl")
+	if next == nil || next.ExerciseID != 30 || next.Set != 3 || next.FromRoutine {
+		t.Errorf("un ejercicio fuera de la rutina debería seguir con su próxima serie: %+v", next)
+	}
+
+	// Sin rutina y sin series del ejercicio actual empieza por la serie 1
+	press := 40
+	next = nextLiveSet(&models.WorkoutDayWithExercises{}, &press, "Press banca")
+	if next == nil || next.Set != 1 || next.ExerciseName != "Press banca" || next.RestTimeSeconds != nil {
+		t.Errorf("sin rutina debería empezar por la serie 1: %+v", next)
+	}
+	if next := nextLiveSet(&models.WorkoutDayWithExercises{}, nil, ""); next != nil {
+		t.Errorf("sin rutina ni ejercicio actual no hay próxima serie: %+v", next)
+	}
+}
@@ -0,0 +1,481 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// defaultMeasurementWindow son los días que abarca el promedio móvil si no se indica window
+	defaultMeasurementWindow = 7
+	maxMeasurementWindow     = 90
+	maxBodyweightKg          = 500
+	maxCircumferenceCm       = 300
+)
+
+var (
+	errInvalidMeasurementType = fmt.Errorf("tipo de medida inválido, usar uno de: %s", strings.Join(models.MeasurementTypes, ", "))
+	errInvalidMeasurementDate = errors.New("measured_on debe tener formato YYYY-MM-DD y no puede ser futura")
+	errMeasurementExists      = errors.New("ya hay una medida de ese tipo en esa fecha")
+)
+
+// isValidMeasurementType indica si el tipo de medida es uno de los permitidos
+func isValidMeasurementType(measurementType string) bool {
+	for _, t := range models.MeasurementTypes {
+		if t == measurementType {
+			return true
+		}
+	}
+	return false
+}
+
+// measurementUnit devuelve la unidad en que se expresa el tipo de medida
+func measurementUnit(measurementType, weightUnit string) string {
+	switch measurementType {
+	case models.MeasurementBodyweight:
+		return weightUnit
+	case models.MeasurementBodyFat:
+		return "%"
+	default:
+		return "cm"
+	}
+}
+
+// validateMeasurementValue valida el valor de una medida ya expresado en la unidad en que se guarda
+func validateMeasurementValue(measurementType string, value float64) error {
+	switch {
+	case value <= 0:
+		return errors.New("el valor debe ser mayor a 0")
+	case measurementType == models.MeasurementBodyweight && value > maxBodyweightKg:
+		return fmt.Errorf("el peso corporal no puede superar %d kg", maxBodyweightKg)
+	case measurementType == models.MeasurementBodyFat && value > 100:
+		return errors.New("el porcentaje de grasa debe estar entre 0 y 100")
+	case measurementType != models.MeasurementBodyweight && measurementType != models.MeasurementBodyFat && value > maxCircumferenceCm:
+		return fmt.Errorf("el contorno no puede superar %d cm", maxCircumferenceCm)
+	}
+	return nil
+}
+
+// parseMeasurementDate valida una fecha YYYY-MM-DD que no sea posterior a hoy
+func parseMeasurementDate(date string, loc *time.Location) (string, error) {
+	parsed, err := time.ParseInLocation("2006-01-02", date, loc)
+	if err != nil || parsed.After(time.Now().In(loc)) {
+		return "", errInvalidMeasurementDate
+	}
+	return date, nil
+}
+
+// measurementMovingAverage completa el promedio móvil de cada punto con las medidas de los
+// últimos windowDays días, incluido el del punto. Los puntos deben estar ordenados por fecha.
+func measurementMovingAverage(points []models.MeasurementTrendPoint, windowDays int) {
+	start := 0
+	sum := 0.0
+	for i := range points {
+		date, _ := time.Parse("2006-01-02", points[i].Date)
+		sum += points[i].Value
+		for {
+			startDate, _ := time.Parse("2006-01-02", points[start].Date)
+			if !startDate.Before(date.AddDate(0, 0, -windowDays+1)) {
+				break
+			}
+			sum -= points[start].Value
+			start++
+		}
+		points[i].MovingAverage = math.Round(sum/float64(i-start+1)*100) / 100
+	}
+}
+
+// loadBodyweightOn devuelve el último peso corporal (kg) registrado hasta la fecha del día
+// de entrenamiento, o nil si el usuario no registró ninguno. Se guarda como foto en la serie al
+// crearla; las medidas que se cargan o corrigen después no cambian las series ya registradas.
+func loadBodyweightOn(q dbQuerier, userID string, workoutDayID int) (*float64, error) {
+	var bodyweight float64
+	err := q.QueryRow(`
+		SELECT m.value
+		FROM body_measurements m
+		JOIN workout_days wd ON wd.id = $2
+		WHERE m.user_id = $1 AND m.measurement_type = $3 AND m.measured_on <= wd.date
+		ORDER BY m.measured_on DESC
+		LIMIT 1
+	`, userID, workoutDayID, models.MeasurementBodyweight).Scan(&bodyweight)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &bodyweight, nil
+}
+
+// measurementColumns son las columnas que se leen de una medida.
+// Debe mantenerse en el mismo orden que scanBodyMeasurement.
+const measurementColumns = `id, user_id, measurement_type, value, measured_on::text, notes, created_at, updated_at`
+
+// scanBodyMeasurement lee una fila seleccionada con measurementColumns
+func scanBodyMeasurement(row interface{ Scan(...interface{}) error }, m *models.BodyMeasurement) error {
+	return row.Scan(&m.ID, &m.UserID, &m.Type, &m.Value, &m.MeasuredOn, &m.Notes, &m.CreatedAt, &m.UpdatedAt)
+}
+
+// prepareMeasurementResponse expresa la medida en la unidad del usuario y sus horarios en su zona horaria
+func prepareMeasurementResponse(m *models.BodyMeasurement, weightUnit string, loc *time.Location) {
+	if m.Type == models.MeasurementBodyweight {
+		m.Value = fromKilograms(m.Value, weightUnit)
+	}
+	m.Unit = measurementUnit(m.Type, weightUnit)
+	m.CreatedAt = convertToUserTime(m.CreatedAt, loc)
+	m.UpdatedAt = convertToUserTime(m.UpdatedAt, loc)
+}
+
+// GetBodyMeasurementsHandler lista las medidas del usuario, las más recientes primero.
+// Filtros opcionales: type, from y to (YYYY-MM-DD).
+func GetBodyMeasurementsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query, args := appendDateRangeFilter(`SELECT `+measurementColumns+` FROM body_measurements WHERE user_id = $1`, "measured_on", from, to, []interface{}{userID})
+	if measurementType := r.URL.Query().Get("type"); measurementType != "" {
+		if !isValidMeasurementType(measurementType) {
+			http.Error(w, errInvalidMeasurementType.Error(), http.StatusBadRequest)
+			return
+		}
+		args = append(args, measurementType)
+		query += fmt.Sprintf(" AND measurement_type = $%d", len(args))
+	}
+	query += " ORDER BY measured_on DESC, measurement_type ASC"
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Error consultando medidas: %v\n", err)
+		http.Error(w, "Error obteniendo medidas", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	loc := getUserLocation(r, userID)
+	unit := getUserWeightUnit(userID)
+	measurements := []models.BodyMeasurement{}
+	for rows.Next() {
+		var m models.BodyMeasurement
+		if err := scanBodyMeasurement(rows, &m); err != nil {
+			fmt.Printf("Error escaneando medida: %v\n", err)
+			continue
+		}
+		prepareMeasurementResponse(&m, unit, loc)
+		measurements = append(measurements, m)
+	}
+
+	json.NewEncoder(w).Encode(measurements)
+}
+
+// CreateBodyMeasurementHandler registra una medida. El peso corporal se envía en weight_unit
+// (por defecto la unidad del usuario) y se guarda en kg.
+func CreateBodyMeasurementHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateBodyMeasurementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if !isValidMeasurementType(req.Type) {
+		http.Error(w, errInvalidMeasurementType.Error(), http.StatusBadRequest)
+		return
+	}
+
+	unit := getUserWeightUnit(userID)
+	value := req.Value
+	if req.Type == models.MeasurementBodyweight {
+		inputUnit, err := requestWeightUnit(req.WeightUnit, unit)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		value = toKilograms(value, inputUnit)
+	}
+	if err := validateMeasurementValue(req.Type, value); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	loc := getUserLocation(r, userID)
+	measuredOn := time.Now().In(loc).Format("2006-01-02")
+	if req.MeasuredOn != nil && *req.MeasuredOn != "" {
+		var err error
+		if measuredOn, err = parseMeasurementDate(*req.MeasuredOn, loc); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	var m models.BodyMeasurement
+	err := scanBodyMeasurement(database.DB.QueryRow(`
+		INSERT INTO body_measurements (user_id, measurement_type, value, measured_on, notes)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, measurement_type, measured_on) DO NOTHING
+		RETURNING `+measurementColumns, userID, req.Type, value, measuredOn, req.Notes), &m)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, errMeasurementExists.Error(), http.StatusConflict)
+		} else {
+			fmt.Printf("Error creando medida: %v\n", err)
+			http.Error(w, "Error creando medida", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	prepareMeasurementResponse(&m, unit, loc)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(m)
+}
+
+// UpdateBodyMeasurementHandler corrige el valor, la fecha o las notas de una medida
+func UpdateBodyMeasurementHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.UpdateBodyMeasurementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if req.Value == nil && req.MeasuredOn == nil && req.Notes == nil {
+		http.Error(w, "no hay campos para actualizar", http.StatusBadRequest)
+		return
+	}
+
+	var measurementType string
+	err = database.DB.QueryRow("SELECT measurement_type FROM body_measurements WHERE id = $1 AND user_id = $2", id, userID).Scan(&measurementType)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Medida no encontrada", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error verificando medida", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	unit := getUserWeightUnit(userID)
+	var value *float64
+	if req.Value != nil {
+		converted := *req.Value
+		if measurementType == models.MeasurementBodyweight {
+			inputUnit, err := requestWeightUnit(req.WeightUnit, unit)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			converted = toKilograms(converted, inputUnit)
+		}
+		if err := validateMeasurementValue(measurementType, converted); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		value = &converted
+	}
+
+	loc := getUserLocation(r, userID)
+	var measuredOn *string
+	if req.MeasuredOn != nil {
+		date, err := parseMeasurementDate(*req.MeasuredOn, loc)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var taken bool
+		err = database.DB.QueryRow(`
+			SELECT EXISTS(
+				SELECT 1 FROM body_measurements
+				WHERE user_id = $1 AND measurement_type = $2 AND measured_on = $3 AND id <> $4
+			)
+		`, userID, measurementType, date, id).Scan(&taken)
+		if err != nil {
+			http.Error(w, "Error verificando medida", http.StatusInternalServerError)
+			return
+		}
+		if taken {
+			http.Error(w, errMeasurementExists.Error(), http.StatusConflict)
+			return
+		}
+		measuredOn = &date
+	}
+
+	var m models.BodyMeasurement
+	err = scanBodyMeasurement(database.DB.QueryRow(`
+		UPDATE body_measurements
+		SET value = COALESCE($3, value),
+			measured_on = COALESCE($4::date, measured_on),
+			notes = CASE WHEN $5 THEN $6 ELSE notes END,
+			updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+		RETURNING `+measurementColumns, id, userID, value, measuredOn, req.Notes != nil, req.Notes), &m)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Medida no encontrada", http.StatusNotFound)
+		} else {
+			fmt.Printf("Error actualizando medida: %v\n", err)
+			http.Error(w, "Error actualizando medida", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	prepareMeasurementResponse(&m, unit, loc)
+	json.NewEncoder(w).Encode(m)
+}
+
+// DeleteBodyMeasurementHandler elimina una medida. Las series que ya guardaron ese peso corporal lo conservan.
+func DeleteBodyMeasurementHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := database.DB.Exec("DELETE FROM body_measurements WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		fmt.Printf("Error eliminando medida: %v\n", err)
+		http.Error(w, "Error eliminando medida", http.StatusInternalServerError)
+		return
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		http.Error(w, "Medida no encontrada", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetMeasurementTrendHandler devuelve la evolución de un tipo de medida (type, por defecto
+// bodyweight) con su promedio móvil de window días (por defecto 7). Filtros opcionales: from y to.
+func GetMeasurementTrendHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	measurementType := r.URL.Query().Get("type")
+	if measurementType == "" {
+		measurementType = models.MeasurementBodyweight
+	}
+	if !isValidMeasurementType(measurementType) {
+		http.Error(w, errInvalidMeasurementType.Error(), http.StatusBadRequest)
+		return
+	}
+
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	window := defaultMeasurementWindow
+	if windowParam := r.URL.Query().Get("window"); windowParam != "" {
+		window, err = strconv.Atoi(windowParam)
+		if err != nil || window < 1 || window > maxMeasurementWindow {
+			http.Error(w, fmt.Sprintf("window debe estar entre 1 y %d días", maxMeasurementWindow), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Se leen también las medidas de la ventana anterior a from para que el primer promedio sea completo
+	queryFrom := from
+	if from != "" {
+		fromDate, _ := time.Parse("2006-01-02", from)
+		queryFrom = fromDate.AddDate(0, 0, -window+1).Format("2006-01-02")
+	}
+	query, args := appendDateRangeFilter(`
+		SELECT measured_on::text, value
+		FROM body_measurements
+		WHERE user_id = $1 AND measurement_type = $2`, "measured_on", queryFrom, to, []interface{}{userID, measurementType})
+	query += " ORDER BY measured_on ASC"
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Error consultando medidas: %v\n", err)
+		http.Error(w, "Error obteniendo evolución", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	unit := getUserWeightUnit(userID)
+	var points []models.MeasurementTrendPoint
+	for rows.Next() {
+		var point models.MeasurementTrendPoint
+		if err := rows.Scan(&point.Date, &point.Value); err != nil {
+			fmt.Printf("Error escaneando medida: %v\n", err)
+			continue
+		}
+		if measurementType == models.MeasurementBodyweight {
+			point.Value = fromKilograms(point.Value, unit)
+		}
+		points = append(points, point)
+	}
+	measurementMovingAverage(points, window)
+
+	trend := models.MeasurementTrend{
+		Type:       measurementType,
+		Unit:       measurementUnit(measurementType, unit),
+		From:       from,
+		To:         to,
+		WindowDays: window,
+		Points:     []models.MeasurementTrendPoint{},
+	}
+	for _, point := range points {
+		if from == "" || point.Date >= from {
+			trend.Points = append(trend.Points, point)
+		}
+	}
+	if n := len(trend.Points); n > 1 {
+		change := math.Round((trend.Points[n-1].MovingAverage-trend.Points[0].MovingAverage)*100) / 100
+		trend.Change = &change
+	}
+
+	json.NewEncoder(w).Encode(trend)
+}
@@ -0,0 +1,60 @@
+//go:build integration
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/goalritmo/gym/backend/testutils"
+)
+
+// TestSupabaseLoadBodyweightOn prueba que cada día toma el último peso corporal registrado hasta su fecha
+func TestSupabaseLoadBodyweightOn(t *testing.T) {
+	testutils.SetupTestDatabase(t)
+
+	testUserID := testutils.GetTestUserID(t)
+	testutils.CreateTestUserInDB(t, testUserID)
+	t.Cleanup(func() {
+		testutils.CleanupTestUser(t, testUserID)
+	})
+
+	for date, value := range map[string]float64{"2024-03-01": 80, "2024-03-10": 82} {
+		_, err := database.DB.Exec(`
+			INSERT INTO body_measurements (user_id, measurement_type, value, measured_on) VALUES ($1, $2, $3, $4)
+		`, testUserID, models.MeasurementBodyweight, value, date)
+		if err != nil {
+			t.Fatalf("Error creando medida: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		date string
+		want *float64
+	}{
+		{"antes de la primera medida", "2024-02-20", nil},
+		{"entre medidas toma la anterior", "2024-03-05", floatPtr(80)},
+		{"el mismo día de la medida", "2024-03-10", floatPtr(82)},
+		{"después de la última medida", "2024-04-01", floatPtr(82)},
+	}
+
+	for _, tt := range tests {
+		var dayID int
+		err := database.DB.QueryRow(`
+			INSERT INTO workout_days (user_id, date, name) VALUES ($1, $2, 'Test') RETURNING id
+		`, testUserID, tt.date).Scan(&dayID)
+		if err != nil {
+			t.Fatalf("Error creando día de entrenamiento: %v", err)
+		}
+
+		got, err := loadBodyweightOn(database.DB, testUserID, dayID)
+		if err != nil {
+			t.Fatalf("%s: error inesperado %v", tt.name, err)
+		}
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("%s: %v, se esperaba %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+func TestMeasurementMovingAverage(t *testing.T) {
+	points := []models.MeasurementTrendPoint{
+		{Date: "2024-05-01", Value: 80},
+		{Date: "2024-05-03", Value: 79},
+		{Date: "2024-05-07", Value: 78},
+		{Date: "2024-05-08", Value: 77.5},
+		{Date: "2024-05-20", Value: 76},
+	}
+
+	measurementMovingAverage(points, 7)
+
+	want := []float64{80, 79.5, 79, 78.17, 76}
+	for i, point := range points {
+		if point.MovingAverage != want[i] {
+			t.Errorf("%s: promedio %v, se esperaba %v", point.Date, point.MovingAverage, want[i])
+		}
+	}
+
+	measurementMovingAverage(nil, 7)
+}
+
+func TestValidateMeasurementValue(t *testing.T) {
+	tests := []struct {
+		name            string
+		measurementType string
+		value           float64
+		valid           bool
+	}{
+		{"peso corporal", models.MeasurementBodyweight, 82.5, true},
+		{"peso corporal imposible", models.MeasurementBodyweight, 900, false},
+		{"grasa corporal", models.MeasurementBodyFat, 18, true},
+		{"grasa mayor a 100", models.MeasurementBodyFat, 120, false},
+		{"cintura", models.MeasurementWaist, 84, true},
+		{"valor cero", models.MeasurementArm, 0, false},
+	}
+
+	for _, tt := range tests {
+		if err := validateMeasurementValue(tt.measurementType, tt.value); (err == nil) != tt.valid {
+			t.Errorf("%s: error inesperado %v", tt.name, err)
+		}
+	}
+
+	if unit := measurementUnit(models.MeasurementBodyweight, models.WeightUnitLb); unit != models.WeightUnitLb {
+		t.Errorf("el peso corporal debería expresarse en la unidad del usuario, no %s", unit)
+	}
+	if unit := measurementUnit(models.MeasurementThigh, models.WeightUnitKg); unit != "cm" {
+		t.Errorf("los contornos se expresan en cm, no %s", unit)
+	}
+}
@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// defaultSecondaryFraction es cuánto cuenta una serie para un grupo muscular secundario
+	defaultSecondaryFraction = 0.5
+	// defaultMuscleVolumeWeeks es la cantidad de semanas que se muestran si no se indica rango
+	defaultMuscleVolumeWeeks = 4
+	maxMuscleTargetSets      = 100
+)
+
+// hardSetFilter son las series efectivas: sin calentamiento y, si se cargó el esfuerzo,
+// a 4 repeticiones o menos del fallo (RIR <= 4 o RPE >= 6)
+const hardSetFilter = `(w.rir IS NULL OR w.rir <= 4) AND (w.rpe IS NULL OR w.rpe >= 6)`
+
+// muscleVolumeSample representa las series de un día que trabajaron un grupo muscular con un rol
+type muscleVolumeSample struct {
+	Date          time.Time
+	MuscleGroupID int
+	Role          string // primary o secondary
+	HardSets      int
+	Volume        float64
+}
+
+// muscleVolumeStatus compara las series efectivas con el rango objetivo
+func muscleVolumeStatus(sets float64, minSets, maxSets int) string {
+	switch {
+	case sets < float64(minSets):
+		return models.MuscleVolumeUnder
+	case sets > float64(maxSets):
+		return models.MuscleVolumeOver
+	default:
+		return models.MuscleVolumeWithin
+	}
+}
+
+// buildMuscleVolumeReport arma el volumen semanal de cada grupo muscular entre from y to.
+// Todas las semanas incluyen todos los grupos, así los que no se entrenaron aparecen como subentrenados.
+func buildMuscleVolumeReport(samples []muscleVolumeSample, groups []models.MuscleGroupTarget, fraction float64, from, to time.Time) models.MuscleVolumeReport {
+	report := models.MuscleVolumeReport{
+		From:              from.Format("2006-01-02"),
+		To:                to.Format("2006-01-02"),
+		SecondaryFraction: fraction,
+		Weeks:             []models.WeeklyMuscleVolume{},
+		Average:           []models.MuscleGroupVolume{},
+	}
+
+	newGroups := func() []models.MuscleGroupVolume {
+		volumes := make([]models.MuscleGroupVolume, len(groups))
+		for i, group := range groups {
+			volumes[i] = models.MuscleGroupVolume{
+				MuscleGroupID:   group.MuscleGroupID,
+				MuscleGroupName: group.MuscleGroupName,
+				TargetMinSets:   group.TargetMinSets,
+				TargetMaxSets:   group.TargetMaxSets,
+			}
+		}
+		return volumes
+	}
+
+	groupIndex := make(map[int]int, len(groups))
+	for i, group := range groups {
+		groupIndex[group.MuscleGroupID] = i
+	}
+	weekIndex := make(map[string]int)
+	for week := periodStart(from, "week"); !week.After(to); week = nextPeriod(week, "week") {
+		key := week.Format("2006-01-02")
+		weekIndex[key] = len(report.Weeks)
+		report.Weeks = append(report.Weeks, models.WeeklyMuscleVolume{WeekStart: key, MuscleGroups: newGroups()})
+	}
+
+	for _, sample := range samples {
+		w, ok := weekIndex[periodStart(sample.Date, "week").Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		g, ok := groupIndex[sample.MuscleGroupID]
+		if !ok {
+			continue
+		}
+		volume := &report.Weeks[w].MuscleGroups[g]
+		if sample.Role == "primary" {
+			volume.PrimarySets += sample.HardSets
+			volume.HardSets += float64(sample.HardSets)
+			volume.Volume += sample.Volume
+		} else {
+			volume.SecondarySets += sample.HardSets
+			volume.HardSets += float64(sample.HardSets) * fraction
+			volume.Volume += sample.Volume * fraction
+		}
+	}
+
+	report.Average = newGroups()
+	weeks := float64(len(report.Weeks))
+	for w := range report.Weeks {
+		for g := range report.Weeks[w].MuscleGroups {
+			volume := &report.Weeks[w].MuscleGroups[g]
+			volume.HardSets = math.Round(volume.HardSets*100) / 100
+			volume.Volume = math.Round(volume.Volume*100) / 100
+			volume.Status = muscleVolumeStatus(volume.HardSets, volume.TargetMinSets, volume.TargetMaxSets)
+
+			average := &report.Average[g]
+			average.PrimarySets += volume.PrimarySets
+			average.SecondarySets += volume.SecondarySets
+			average.HardSets += volume.HardSets / weeks
+			average.Volume += volume.Volume / weeks
+		}
+	}
+	for g := range report.Average {
+		average := &report.Average[g]
+		average.HardSets = math.Round(average.HardSets*100) / 100
+		average.Volume = math.Round(average.Volume*100) / 100
+		average.Status = muscleVolumeStatus(average.HardSets, average.TargetMinSets, average.TargetMaxSets)
+	}
+
+	return report
+}
+
+// GetMuscleVolumeHandler devuelve las series efectivas y el volumen por grupo muscular de cada semana,
+// comparados con el rango objetivo de cada grupo. Por defecto muestra las últimas 4 semanas.
+func GetMuscleVolumeHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	fraction := defaultSecondaryFraction
+	if fractionParam := r.URL.Query().Get("secondary_fraction"); fractionParam != "" {
+		fraction, err = strconv.ParseFloat(fractionParam, 64)
+		if err != nil || fraction < 0 || fraction > 1 {
+			http.Error(w, "secondary_fraction debe estar entre 0 y 1", http.StatusBadRequest)
+			return
+		}
+	}
+
+	now := time.Now().In(getUserLocation(r, userID))
+	if to == "" {
+		to = now.Format("2006-01-02")
+	}
+	toDate, _ := time.Parse("2006-01-02", to)
+	if from == "" {
+		from = periodStart(toDate, "week").AddDate(0, 0, -7*(defaultMuscleVolumeWeeks-1)).Format("2006-01-02")
+	}
+	fromDate, _ := time.Parse("2006-01-02", from)
+	if fromDate.After(toDate) {
+		http.Error(w, errInvalidDateRange.Error(), http.StatusBadRequest)
+		return
+	}
+
+	groupRows, err := database.DB.Query(`SELECT id, name, target_min_sets, target_max_sets FROM muscle_groups ORDER BY name ASC`)
+	if err != nil {
+		fmt.Printf("Error consultando grupos musculares: %v\n", err)
+		http.Error(w, "Error obteniendo grupos musculares", http.StatusInternalServerError)
+		return
+	}
+	defer groupRows.Close()
+
+	var groups []models.MuscleGroupTarget
+	for groupRows.Next() {
+		var group models.MuscleGroupTarget
+		if err := groupRows.Scan(&group.MuscleGroupID, &group.MuscleGroupName, &group.TargetMinSets, &group.TargetMaxSets); err != nil {
+			fmt.Printf("Error escaneando grupo muscular: %v\n", err)
+			continue
+		}
+		groups = append(groups, group)
+	}
+
+	query, args := appendDateRangeFilter(`
+		SELECT wd.date, emg.muscle_group_id, emg.role,
+			COUNT(*) FILTER (WHERE `+hardSetFilter+`),
+			COALESCE(SUM(`+workoutLoad+` * w.reps), 0)
+		FROM workouts w
+		JOIN workout_days wd ON w.workout_day_id = wd.id
+		JOIN exercises e ON w.exercise_id = e.id
+		JOIN exercise_muscle_groups emg ON emg.exercise_id = w.exercise_id
+		WHERE w.user_id = $1 AND w.deleted_at IS NULL AND NOT e.is_sport AND `+workingSetFilter, "wd.date", from, to, []interface{}{userID})
+	query += " GROUP BY wd.date, emg.muscle_group_id, emg.role"
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Error consultando volumen por grupo muscular: %v\n", err)
+		http.Error(w, "Error obteniendo volumen por grupo muscular", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	var samples []muscleVolumeSample
+	for rows.Next() {
+		var sample muscleVolumeSample
+		if err := rows.Scan(&sample.Date, &sample.MuscleGroupID, &sample.Role, &sample.HardSets, &sample.Volume); err != nil {
+			fmt.Printf("Error escaneando volumen: %v\n", err)
+			continue
+		}
+		samples = append(samples, sample)
+	}
+
+	report := buildMuscleVolumeReport(samples, groups, fraction, fromDate, toDate)
+	report.WeightUnit = getUserWeightUnit(userID)
+	for w := range report.Weeks {
+		for g := range report.Weeks[w].MuscleGroups {
+			report.Weeks[w].MuscleGroups[g].Volume = fromKilograms(report.Weeks[w].MuscleGroups[g].Volume, report.WeightUnit)
+		}
+	}
+	for g := range report.Average {
+		report.Average[g].Volume = fromKilograms(report.Average[g].Volume, report.WeightUnit)
+	}
+
+	json.NewEncoder(w).Encode(report)
+}
+
+// UpdateMuscleGroupTargetsHandler configura el rango objetivo de series semanales de un grupo muscular
+func UpdateMuscleGroupTargetsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	muscleGroupID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID de grupo muscular inválido", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateMuscleGroupTargetsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if req.TargetMinSets < 0 || req.TargetMaxSets > maxMuscleTargetSets || req.TargetMinSets > req.TargetMaxSets {
+		http.Error(w, fmt.Sprintf("el rango objetivo debe cumplir 0 <= target_min_sets <= target_max_sets <= %d", maxMuscleTargetSets), http.StatusBadRequest)
+		return
+	}
+
+	var name string
+	err = database.DB.QueryRow(`
+		UPDATE muscle_groups SET target_min_sets = $2, target_max_sets = $3 WHERE id = $1 RETURNING name
+	`, muscleGroupID, req.TargetMinSets, req.TargetMaxSets).Scan(&name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Grupo muscular no encontrado", http.StatusNotFound)
+		} else {
+			fmt.Printf("Error actualizando grupo muscular: %v\n", err)
+			http.Error(w, "Error actualizando grupo muscular", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	json.NewEncoder(w).Encode(models.MuscleGroupTarget{
+		MuscleGroupID:   muscleGroupID,
+		MuscleGroupName: name,
+		TargetMinSets:   req.TargetMinSets,
+		TargetMaxSets:   req.TargetMaxSets,
+	})
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+func TestBuildMuscleVolumeReport(t *testing.T) {
+	groups := []models.MuscleGroupTarget{
+		{MuscleGroupID: 1, MuscleGroupName: "Cuádriceps", TargetMinSets: 10, TargetMaxSets: 20},
+		{MuscleGroupID: 2, MuscleGroupName: "Glúteos", TargetMinSets: 4, TargetMaxSets: 8},
+		{MuscleGroupID: 3, MuscleGroupName: "Pecho", TargetMinSets: 10, TargetMaxSets: 20},
+	}
+	// Lunes 6 y lunes 13 de mayo de 2024
+	monday := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	samples := []muscleVolumeSample{
+		{Date: monday, MuscleGroupID: 1, Role: "primary", HardSets: 6, Volume: 3000},
+		{Date: monday.AddDate(0, 0, 3), MuscleGroupID: 1, Role: "primary", HardSets: 6, Volume: 3000},
+		{Date: monday, MuscleGroupID: 2, Role: "secondary", HardSets: 6, Volume: 3000},
+		{Date: monday.AddDate(0, 0, 3), MuscleGroupID: 2, Role: "secondary", HardSets: 12, Volume: 6000},
+		{Date: monday.AddDate(0, 0, 7), MuscleGroupID: 1, Role: "primary", HardSets: 4, Volume: 2000},
+		{Date: monday.AddDate(0, 0, 30), MuscleGroupID: 1, Role: "primary", HardSets: 4, Volume: 2000}, // Fuera del rango
+	}
+
+	report := buildMuscleVolumeReport(samples, groups, 0.5, monday.AddDate(0, 0, 2), monday.AddDate(0, 0, 9))
+
+	if len(report.Weeks) != 2 || report.Weeks[0].WeekStart != "2024-05-06" || report.Weeks[1].WeekStart != "2024-05-13" {
+		t.Fatalf("semanas incorrectas: %+v", report.Weeks)
+	}
+	for _, week := range report.Weeks {
+		if len(week.MuscleGroups) != len(groups) {
+			t.Fatalf("cada semana debería incluir todos los grupos: %+v", week)
+		}
+	}
+
+	quads := report.Weeks[0].MuscleGroups[0]
+	if quads.PrimarySets != 12 || quads.HardSets != 12 || quads.Volume != 6000 || quads.Status != models.MuscleVolumeWithin {
+		t.Errorf("cuádriceps semana 1 incorrecto: %+v", quads)
+	}
+	glutes := report.Weeks[0].MuscleGroups[1]
+	if glutes.SecondarySets != 18 || glutes.HardSets != 9 || glutes.Volume != 4500 || glutes.Status != models.MuscleVolumeOver {
+		t.Errorf("las series secundarias deberían contar a la mitad: %+v", glutes)
+	}
+	if chest := report.Weeks[1].MuscleGroups[2]; chest.HardSets != 0 || chest.Status != models.MuscleVolumeUnder {
+		t.Errorf("un grupo sin series debería quedar subentrenado: %+v", chest)
+	}
+
+	average := report.Average[0]
+	if average.HardSets != 8 || average.PrimarySets != 16 || average.Status != models.MuscleVolumeUnder {
+		t.Errorf("promedio de cuádriceps incorrecto: %+v", average)
+	}
+}
+
+func TestMuscleVolumeStatus(t *testing.T) {
+	tests := []struct {
+		sets float64
+		want string
+	}{
+		{9.5, models.MuscleVolumeUnder},
+		{10, models.MuscleVolumeWithin},
+		{20, models.MuscleVolumeWithin},
+		{20.5, models.MuscleVolumeOver},
+	}
+	for _, tt := range tests {
+		if got := muscleVolumeStatus(tt.sets, 10, 20); got != tt.want {
+			t.Errorf("muscleVolumeStatus(%v) = %s, se esperaba %s", tt.sets, got, tt.want)
+		}
+	}
+}
@@ -35,6 +35,8 @@ func GetNotificationsHandler(w http.ResponseWriter, r *http.Request) {
 
 	fmt.Printf("GetNotificationsHandler llamado para usuario: %s\n", userID)
 
+	loc := getUserLocation(r, userID)
+
 	// Obtener parámetros de paginación
 	limit := 20
 	offset := 0
@@ -86,8 +88,8 @@ func GetNotificationsHandler(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		// Convertir fecha a zona horaria de Argentina
-		notification.CreatedAt = convertToArgentinaTime(notification.CreatedAt)
+		// Convertir fecha a zona horaria del usuario
+		notification.CreatedAt = convertToUserTime(notification.CreatedAt, loc)
 		notifications = append(notifications, notification)
 
 	}
@@ -139,7 +141,7 @@ func MarkNotificationAsReadHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	notification.CreatedAt = convertToArgentinaTime(notification.CreatedAt)
+	notification.CreatedAt = convertToUserTime(notification.CreatedAt, getUserLocation(r, userID))
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(notification)
@@ -208,6 +210,9 @@ func GetUnreadNotificationsCountHandler(w http.ResponseWriter, r *http.Request)
 func GetSystemNotificationsHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
+	userID, _ := r.Context().Value("user_id").(string)
+	loc := getUserLocation(r, userID)
+
 	query := `
 		SELECT id, title, message, type, created_at
 		FROM admin_notifications 
@@ -234,8 +239,8 @@ func GetSystemNotificationsHandler(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		// Convertir fecha a zona horaria de Argentina
-		createdAt = convertToArgentinaTime(createdAt)
+		// Convertir fecha a zona horaria del usuario
+		createdAt = convertToUserTime(createdAt, loc)
 		
 		// Determinar prioridad basada en el tipo
 		priority := "medium"
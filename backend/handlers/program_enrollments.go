@@ -0,0 +1,614 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/gorilla/mux"
+)
+
+var (
+	errNoActiveProgram     = errors.New("no estás siguiendo ningún programa")
+	errProgramCompleted    = errors.New("ya completaste el programa")
+	errProgramSessionTaken = errors.New("ya iniciaste la sesión del programa en otro día con series registradas")
+	errProgramRoutineEmpty = errors.New("la rutina de la sesión no tiene ejercicios")
+	errProgramNoRoutines   = errors.New("el programa no tiene rutinas disponibles")
+)
+
+// nextProgramPosition avanza a la sesión siguiente: el próximo día de la semana tipo o el primer día
+// de la semana siguiente. Indica si con eso se completó el programa.
+func nextProgramPosition(week, day, weeks, daysPerWeek int) (int, int, bool) {
+	day++
+	if day > daysPerWeek {
+		week++
+		day = 1
+	}
+	return week, day, week > weeks
+}
+
+// programProgress calcula las sesiones completadas según la semana y el día en curso
+func programProgress(enrollment *models.ProgramEnrollment) {
+	enrollment.TotalSessions = enrollment.Weeks * enrollment.DaysPerWeek
+	if enrollment.Status == models.ProgramEnrollmentCompleted {
+		enrollment.CompletedSessions = enrollment.TotalSessions
+	} else {
+		enrollment.CompletedSessions = (enrollment.CurrentWeek-1)*enrollment.DaysPerWeek + enrollment.CurrentDay - 1
+	}
+	if enrollment.CompletedSessions > enrollment.TotalSessions {
+		enrollment.CompletedSessions = enrollment.TotalSessions
+	}
+	if enrollment.TotalSessions > 0 {
+		enrollment.Progress = math.Round(float64(enrollment.CompletedSessions)/float64(enrollment.TotalSessions)*100) / 100
+	}
+}
+
+// enrollmentColumns son las columnas que se leen de un seguimiento. Debe mantenerse en el mismo orden que scanEnrollment.
+const enrollmentColumns = `pe.id, pe.program_id, p.name, p.weeks, pe.status, pe.current_week, pe.current_day,
+	pe.current_workout_day_id, pe.started_at, pe.completed_at`
+
+// scanEnrollment lee una fila seleccionada con enrollmentColumns
+func scanEnrollment(row interface{ Scan(...interface{}) error }, enrollment *models.ProgramEnrollment) error {
+	return row.Scan(
+		&enrollment.ID,
+		&enrollment.ProgramID,
+		&enrollment.ProgramName,
+		&enrollment.Weeks,
+		&enrollment.Status,
+		&enrollment.CurrentWeek,
+		&enrollment.CurrentDay,
+		&enrollment.CurrentWorkoutDayID,
+		&enrollment.StartedAt,
+		&enrollment.CompletedAt,
+	)
+}
+
+// lockActiveEnrollment bloquea el programa que el usuario está siguiendo
+func lockActiveEnrollment(q dbQuerier, userID string) (*models.ProgramEnrollment, error) {
+	var enrollment models.ProgramEnrollment
+	err := scanEnrollment(q.QueryRow(`
+		SELECT `+enrollmentColumns+`
+		FROM program_enrollments pe
+		JOIN programs p ON p.id = pe.program_id
+		WHERE pe.user_id = $1 AND pe.status = $2
+		FOR UPDATE OF pe
+	`, userID, models.ProgramEnrollmentActive), &enrollment)
+	if err == sql.ErrNoRows {
+		return nil, errNoActiveProgram
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+// programSessionDone indica si la sesión iniciada en el día ya se hizo: tiene series registradas y
+// es de un día anterior a hoy o se completaron todas las series planificadas. Devuelve false sin error
+// si el día ya no existe o está en la papelera.
+func programSessionDone(q dbQuerier, userID string, workoutDayID int, today string, loc *time.Location) (bool, error) {
+	var date string
+	var sets int
+	err := q.QueryRow(`
+		SELECT wd.date::text, COUNT(w.id)
+		FROM workout_days wd
+		LEFT JOIN workouts w ON w.workout_day_id = wd.id AND w.deleted_at IS NULL
+		WHERE wd.id = $1 AND wd.user_id = $2 AND wd.deleted_at IS NULL
+		GROUP BY wd.id, wd.date
+	`, workoutDayID, userID).Scan(&date, &sets)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil || sets == 0 {
+		return false, err
+	}
+	if date < today {
+		return true, nil
+	}
+
+	day, err := loadWorkoutDayWithExercises(q, userID, workoutDayID, loc)
+	if err != nil {
+		return false, err
+	}
+	return day.Plan != nil && day.Plan.PlannedSets > 0 && day.Plan.CompletedSets >= day.Plan.PlannedSets, nil
+}
+
+// advanceEnrollment pasa a la sesión siguiente y completa el seguimiento al terminar la última semana
+func advanceEnrollment(enrollment *models.ProgramEnrollment) {
+	var completed bool
+	enrollment.CurrentWeek, enrollment.CurrentDay, completed = nextProgramPosition(
+		enrollment.CurrentWeek, enrollment.CurrentDay, enrollment.Weeks, enrollment.DaysPerWeek)
+	enrollment.CurrentWorkoutDayID = nil
+	if completed {
+		now := time.Now()
+		enrollment.Status = models.ProgramEnrollmentCompleted
+		enrollment.CurrentWeek = enrollment.Weeks
+		enrollment.CurrentDay = enrollment.DaysPerWeek
+		enrollment.CompletedAt = &now
+	}
+}
+
+// reconcileEnrollment ajusta la posición del seguimiento a los cambios del programa (semanas o días eliminados):
+// si la sesión en curso ya no existe pasa a la siguiente que sí existe
+func reconcileEnrollment(enrollment *models.ProgramEnrollment) {
+	if enrollment.DaysPerWeek > 0 && enrollment.CurrentDay > enrollment.DaysPerWeek {
+		enrollment.CurrentDay = enrollment.DaysPerWeek
+		advanceEnrollment(enrollment)
+	}
+	if enrollment.Status == models.ProgramEnrollmentActive && enrollment.CurrentWeek > enrollment.Weeks {
+		enrollment.CurrentWeek = enrollment.Weeks
+		enrollment.CurrentDay = enrollment.DaysPerWeek
+		advanceEnrollment(enrollment)
+	}
+}
+
+// syncEnrollment avanza el seguimiento si la sesión en curso ya se hizo y lo ajusta a los cambios
+// del programa. Guarda los cambios.
+func syncEnrollment(q dbQuerier, userID string, enrollment *models.ProgramEnrollment, today string, loc *time.Location) error {
+	before := *enrollment
+
+	reconcileEnrollment(enrollment)
+
+	if enrollment.Status == models.ProgramEnrollmentActive && enrollment.CurrentWorkoutDayID != nil {
+		done, err := programSessionDone(q, userID, *enrollment.CurrentWorkoutDayID, today, loc)
+		if err != nil {
+			return err
+		}
+		if done {
+			advanceEnrollment(enrollment)
+		}
+	}
+
+	if enrollment.Status == before.Status && enrollment.CurrentWeek == before.CurrentWeek &&
+		enrollment.CurrentDay == before.CurrentDay && enrollment.CurrentWorkoutDayID == before.CurrentWorkoutDayID {
+		return nil
+	}
+	return saveEnrollment(q, enrollment)
+}
+
+// saveEnrollment guarda la posición y el estado de un seguimiento
+func saveEnrollment(q dbQuerier, enrollment *models.ProgramEnrollment) error {
+	_, err := q.Exec(`
+		UPDATE program_enrollments
+		SET status = $1, current_week = $2, current_day = $3, current_workout_day_id = $4,
+			completed_at = $5, updated_at = NOW()
+		WHERE id = $6
+	`, enrollment.Status, enrollment.CurrentWeek, enrollment.CurrentDay, enrollment.CurrentWorkoutDayID,
+		enrollment.CompletedAt, enrollment.ID)
+	return err
+}
+
+// loadSyncedEnrollment bloquea el seguimiento activo del usuario, lo pone al día y devuelve la semana tipo del programa
+func loadSyncedEnrollment(q dbQuerier, userID string, loc *time.Location) (*models.ProgramEnrollment, []models.ProgramDay, error) {
+	enrollment, days, err := lockEnrollmentWithDays(q, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	today := time.Now().In(loc).Format("2006-01-02")
+	if err := syncEnrollment(q, userID, enrollment, today, loc); err != nil {
+		return nil, nil, err
+	}
+	return enrollment, days, nil
+}
+
+// buildProgramSession arma la sesión en curso del seguimiento con la rutina ajustada a la semana
+func buildProgramSession(q dbQuerier, enrollment *models.ProgramEnrollment, days []models.ProgramDay, unit string) (*models.ProgramSession, error) {
+	if enrollment.Status != models.ProgramEnrollmentActive || enrollment.CurrentDay < 1 || enrollment.CurrentDay > len(days) {
+		return nil, nil
+	}
+	day := days[enrollment.CurrentDay-1]
+
+	weeks, err := loadProgramWeeks(q, enrollment.ProgramID)
+	if err != nil {
+		return nil, err
+	}
+	exercises, err := loadRoutineExercises(q, day.RoutineID)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.ProgramSession{
+		Week:        enrollment.CurrentWeek,
+		DayNumber:   day.DayNumber,
+		RoutineID:   day.RoutineID,
+		RoutineName: day.RoutineName,
+		WeightUnit:  unit,
+		Adjustments: programWeekFor(weeks, enrollment.CurrentWeek),
+		Exercises:   []models.RoutineExercise{},
+	}
+	applyProgramWeek(exercises, session.Adjustments)
+	convertRoutineExerciseWeights(exercises, unit)
+	if exercises != nil {
+		session.Exercises = exercises
+	}
+	return session, nil
+}
+
+// writeEnrollmentError responde con el código HTTP que corresponde a un error del seguimiento de programas
+func writeEnrollmentError(w http.ResponseWriter, err error) {
+	switch err {
+	case errNoActiveProgram:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errProgramCompleted, errProgramSessionTaken, errDayHasOtherRoutine:
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errProgramRoutineEmpty, errProgramNoRoutines:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errWorkoutDayNotFound, errInvalidWorkoutDate, errWorkoutDateOutOfRange:
+		writeWorkoutDayError(w, err)
+	default:
+		fmt.Printf("Error en seguimiento de programa: %v\n", err)
+		http.Error(w, "Error procesando el programa", http.StatusInternalServerError)
+	}
+}
+
+// respondEnrollment confirma la transacción y responde con el seguimiento y la sesión que toca
+func respondEnrollment(w http.ResponseWriter, tx *sql.Tx, userID string, enrollment *models.ProgramEnrollment, days []models.ProgramDay, status int) {
+	unit := getUserWeightUnit(userID)
+	session, err := buildProgramSession(tx, enrollment, days, unit)
+	if err != nil {
+		writeEnrollmentError(w, err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		http.Error(w, "Error confirmando transacción", http.StatusInternalServerError)
+		return
+	}
+
+	programProgress(enrollment)
+	enrollment.NextSession = session
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(enrollment)
+}
+
+// EnrollProgramHandler empieza a seguir un programa desde la semana 1, día 1.
+// Cada usuario sigue un solo programa a la vez.
+func EnrollProgramHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	programID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID de programa inválido", http.StatusBadRequest)
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	program, err := loadProgram(tx, userID, programID)
+	if err != nil {
+		writeProgramError(w, err)
+		return
+	}
+	if !program.IsPublished && program.AuthorID != userID {
+		http.Error(w, "Programa no encontrado", http.StatusNotFound)
+		return
+	}
+	if len(program.Days) == 0 {
+		http.Error(w, "El programa no tiene rutinas", http.StatusBadRequest)
+		return
+	}
+
+	var enrollmentID int
+	err = tx.QueryRow(`
+		INSERT INTO program_enrollments (program_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) WHERE status = 'active' DO NOTHING
+		RETURNING id
+	`, programID, userID).Scan(&enrollmentID)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Ya estás siguiendo un programa; abandonalo antes de empezar otro", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		fmt.Printf("Error creando seguimiento de programa: %v\n", err)
+		http.Error(w, "Error empezando el programa", http.StatusInternalServerError)
+		return
+	}
+
+	enrollment, err := lockActiveEnrollment(tx, userID)
+	if err != nil {
+		writeEnrollmentError(w, err)
+		return
+	}
+	enrollment.DaysPerWeek = len(program.Days)
+
+	respondEnrollment(w, tx, userID, enrollment, program.Days, http.StatusCreated)
+}
+
+// GetProgramEnrollmentHandler devuelve el programa que el usuario está siguiendo: semana y día en curso,
+// progreso y la próxima sesión con los ajustes de la semana. Si la sesión en curso ya se hizo, avanza.
+func GetProgramEnrollmentHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	enrollment, days, err := loadSyncedEnrollment(tx, userID, getUserLocation(r, userID))
+	if err != nil {
+		writeEnrollmentError(w, err)
+		return
+	}
+
+	respondEnrollment(w, tx, userID, enrollment, days, http.StatusOK)
+}
+
+// StartProgramSessionHandler inicia la sesión que toca del programa en un día de entrenamiento (hoy por defecto)
+// y devuelve el checklist con las series ajustadas a la semana. Sin date ni workout_day_id se usa hoy.
+func StartProgramSessionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	// El body es opcional
+	var req models.StartRoutineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	loc := getUserLocation(r, userID)
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	enrollment, days, err := loadSyncedEnrollment(tx, userID, loc)
+	if err != nil {
+		writeEnrollmentError(w, err)
+		return
+	}
+	if enrollment.Status == models.ProgramEnrollmentCompleted {
+		// El avance se guarda aunque no se pueda iniciar otra sesión
+		if err := tx.Commit(); err != nil {
+			http.Error(w, "Error confirmando transacción", http.StatusInternalServerError)
+			return
+		}
+		writeEnrollmentError(w, errProgramCompleted)
+		return
+	}
+	if err := startProgramSession(tx, userID, enrollment, days, req, loc); err != nil {
+		writeEnrollmentError(w, err)
+		return
+	}
+
+	day, err := loadWorkoutDayWithExercises(tx, userID, *enrollment.CurrentWorkoutDayID, loc)
+	if err != nil {
+		writeWorkoutDayError(w, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, "Error confirmando transacción", http.StatusInternalServerError)
+		return
+	}
+
+	convertWorkoutDayPlanWeights(day.Plan, getUserWeightUnit(userID))
+	json.NewEncoder(w).Encode(day.Plan)
+}
+
+// startProgramSession asocia la rutina de la sesión en curso al día de entrenamiento indicado y
+// registra la semana del programa en el día
+func startProgramSession(tx *sql.Tx, userID string, enrollment *models.ProgramEnrollment, days []models.ProgramDay, req models.StartRoutineRequest, loc *time.Location) error {
+	if enrollment.CurrentDay < 1 || enrollment.CurrentDay > len(days) {
+		return errProgramNoRoutines
+	}
+	programDay := days[enrollment.CurrentDay-1]
+
+	var exerciseCount int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM routine_exercises WHERE routine_id = $1", programDay.RoutineID).Scan(&exerciseCount); err != nil {
+		return err
+	}
+	if exerciseCount == 0 {
+		return errProgramRoutineEmpty
+	}
+
+	workoutDayID, err := resolveWorkoutDayID(tx, userID, loc, req.Date, req.WorkoutDayID)
+	if err != nil {
+		return err
+	}
+
+	// Si la sesión ya se había iniciado en otro día sin series, se mueve al nuevo día
+	if previous := enrollment.CurrentWorkoutDayID; previous != nil && *previous != workoutDayID {
+		var sets int
+		err := tx.QueryRow("SELECT COUNT(*) FROM workouts WHERE workout_day_id = $1 AND deleted_at IS NULL", *previous).Scan(&sets)
+		if err != nil {
+			return err
+		}
+		if sets > 0 {
+			return errProgramSessionTaken
+		}
+		_, err = tx.Exec(`
+			UPDATE workout_days SET program_enrollment_id = NULL, program_week = NULL, program_day = NULL, updated_at = NOW()
+			WHERE id = $1
+		`, *previous)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := attachRoutineToDay(tx, workoutDayID, programDay.RoutineID, programDay.RoutineName); err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		UPDATE workout_days SET program_enrollment_id = $1, program_week = $2, program_day = $3
+		WHERE id = $4
+	`, enrollment.ID, enrollment.CurrentWeek, enrollment.CurrentDay, workoutDayID)
+	if err != nil {
+		return err
+	}
+
+	enrollment.CurrentWorkoutDayID = &workoutDayID
+	return saveEnrollment(tx, enrollment)
+}
+
+// AdvanceProgramHandler da por hecha (o saltea) la sesión en curso y pasa a la siguiente
+func AdvanceProgramHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	enrollment, days, err := lockEnrollmentWithDays(tx, userID)
+	if err != nil {
+		writeEnrollmentError(w, err)
+		return
+	}
+
+	advanceEnrollment(enrollment)
+	if err := saveEnrollment(tx, enrollment); err != nil {
+		writeEnrollmentError(w, err)
+		return
+	}
+
+	respondEnrollment(w, tx, userID, enrollment, days, http.StatusOK)
+}
+
+// lockEnrollmentWithDays bloquea el seguimiento activo y carga la semana tipo sin avanzar la sesión en curso
+func lockEnrollmentWithDays(q dbQuerier, userID string) (*models.ProgramEnrollment, []models.ProgramDay, error) {
+	enrollment, err := lockActiveEnrollment(q, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	days, err := loadProgramDays(q, enrollment.ProgramID)
+	if err != nil {
+		return nil, nil, err
+	}
+	enrollment.DaysPerWeek = len(days)
+	return enrollment, days, nil
+}
+
+// programSessionRoutine indica si un día con la rutina routineID puede tomarse como la sesión en curso:
+// el seguimiento está activo, la sesión todavía no se inició y la rutina es la del día que toca
+func programSessionRoutine(enrollment *models.ProgramEnrollment, days []models.ProgramDay, routineID *int) bool {
+	if enrollment.Status != models.ProgramEnrollmentActive || enrollment.CurrentWorkoutDayID != nil || routineID == nil {
+		return false
+	}
+	if enrollment.CurrentDay < 1 || enrollment.CurrentDay > len(days) {
+		return false
+	}
+	return days[enrollment.CurrentDay-1].RoutineID == *routineID
+}
+
+// syncProgramAfterSets pone al día el programa que sigue el usuario después de registrar series en un día.
+// Si la rutina del día es la de la sesión que toca y no se inició desde el programa, el día pasa a ser
+// esa sesión. Los errores solo se registran: no deben impedir guardar las series.
+func syncProgramAfterSets(userID string, workoutDayID int, loc *time.Location) {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		fmt.Printf("Error iniciando transacción del programa: %v\n", err)
+		return
+	}
+	defer tx.Rollback()
+
+	enrollment, days, err := lockEnrollmentWithDays(tx, userID)
+	if err == errNoActiveProgram {
+		return
+	}
+	if err != nil {
+		fmt.Printf("Error obteniendo programa en curso: %v\n", err)
+		return
+	}
+
+	var routineID *int
+	var enrollmentID *int
+	err = tx.QueryRow("SELECT routine_id, program_enrollment_id FROM workout_days WHERE id = $1 AND user_id = $2",
+		workoutDayID, userID).Scan(&routineID, &enrollmentID)
+	if err != nil {
+		fmt.Printf("Error obteniendo día de entrenamiento %d: %v\n", workoutDayID, err)
+		return
+	}
+	if enrollmentID == nil && programSessionRoutine(enrollment, days, routineID) {
+		_, err = tx.Exec(`
+			UPDATE workout_days SET program_enrollment_id = $1, program_week = $2, program_day = $3
+			WHERE id = $4
+		`, enrollment.ID, enrollment.CurrentWeek, enrollment.CurrentDay, workoutDayID)
+		if err != nil {
+			fmt.Printf("Error asociando el día %d al programa: %v\n", workoutDayID, err)
+			return
+		}
+		enrollment.CurrentWorkoutDayID = &workoutDayID
+		if err := saveEnrollment(tx, enrollment); err != nil {
+			fmt.Printf("Error guardando seguimiento del programa: %v\n", err)
+			return
+		}
+	}
+
+	today := time.Now().In(loc).Format("2006-01-02")
+	if err := syncEnrollment(tx, userID, enrollment, today, loc); err != nil {
+		fmt.Printf("Error avanzando programa: %v\n", err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		fmt.Printf("Error confirmando avance del programa: %v\n", err)
+	}
+}
+
+// AbandonProgramHandler deja de seguir el programa en curso. Las sesiones ya registradas se conservan.
+func AbandonProgramHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := database.DB.Exec(`
+		UPDATE program_enrollments SET status = $1, updated_at = NOW()
+		WHERE user_id = $2 AND status = $3
+	`, models.ProgramEnrollmentAbandoned, userID, models.ProgramEnrollmentActive)
+	if err != nil {
+		fmt.Printf("Error abandonando programa: %v\n", err)
+		http.Error(w, "Error abandonando el programa", http.StatusInternalServerError)
+		return
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		http.Error(w, errNoActiveProgram.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
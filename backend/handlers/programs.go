@@ -0,0 +1,677 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+const (
+	maxProgramWeeks = 52
+	maxProgramDays  = 14
+	// deloadIntensityPercent es el porcentaje del peso que se usa en las semanas de descarga si no se indica otro
+	deloadIntensityPercent = 60
+)
+
+var (
+	errProgramNotFound  = errors.New("programa no encontrado")
+	errProgramForbidden = errors.New("solo el autor puede modificar el programa")
+	errProgramRoutines  = errors.New("las rutinas del programa deben ser tuyas y no estar en la papelera")
+	errProgramDayCount  = fmt.Errorf("routine_ids debe tener entre 1 y %d rutinas", maxProgramDays)
+)
+
+// validateProgramWeeks valida los ajustes semanales de un programa de weeks semanas y completa
+// los valores por defecto
+func validateProgramWeeks(weeks int, requests []models.ProgramWeekRequest) ([]models.ProgramWeek, error) {
+	seen := make(map[int]bool, len(requests))
+	adjustments := make([]models.ProgramWeek, 0, len(requests))
+	for _, req := range requests {
+		if req.Week < 1 || req.Week > weeks {
+			return nil, fmt.Errorf("semana %d: debe estar entre 1 y %d", req.Week, weeks)
+		}
+		if seen[req.Week] {
+			return nil, fmt.Errorf("semana %d: está repetida", req.Week)
+		}
+		seen[req.Week] = true
+		if req.SetsAdjustment < -10 || req.SetsAdjustment > 10 {
+			return nil, fmt.Errorf("semana %d: sets_adjustment debe estar entre -10 y 10", req.Week)
+		}
+		if req.RepsAdjustment < -50 || req.RepsAdjustment > 50 {
+			return nil, fmt.Errorf("semana %d: reps_adjustment debe estar entre -50 y 50", req.Week)
+		}
+
+		week := models.ProgramWeek{
+			Week:             req.Week,
+			SetsAdjustment:   req.SetsAdjustment,
+			RepsAdjustment:   req.RepsAdjustment,
+			IntensityPercent: 100,
+			IsDeload:         req.IsDeload,
+			Notes:            req.Notes,
+		}
+		if req.IsDeload {
+			week.IntensityPercent = deloadIntensityPercent
+		}
+		if req.IntensityPercent != nil {
+			if *req.IntensityPercent <= 0 || *req.IntensityPercent > 200 {
+				return nil, fmt.Errorf("semana %d: intensity_percent debe ser mayor a 0 y hasta 200", req.Week)
+			}
+			week.IntensityPercent = *req.IntensityPercent
+		}
+		if week.Notes != nil && strings.TrimSpace(*week.Notes) == "" {
+			week.Notes = nil
+		}
+		adjustments = append(adjustments, week)
+	}
+	return adjustments, nil
+}
+
+// programWeekFor devuelve los ajustes de una semana; las semanas sin ajustes usan las rutinas tal cual
+func programWeekFor(adjustments []models.ProgramWeek, week int) models.ProgramWeek {
+	for _, adjustment := range adjustments {
+		if adjustment.Week == week {
+			return adjustment
+		}
+	}
+	return models.ProgramWeek{Week: week, IntensityPercent: 100}
+}
+
+// applyProgramWeek ajusta lo planificado en los ejercicios de una rutina a la semana del programa.
+// En las semanas de descarga las series se reducen a la mitad (redondeando hacia arriba) antes del ajuste.
+func applyProgramWeek(exercises []models.RoutineExercise, week models.ProgramWeek) {
+	atLeastOne := func(value int) int {
+		if value < 1 {
+			return 1
+		}
+		return value
+	}
+
+	for i := range exercises {
+		exercise := &exercises[i]
+		sets := exercise.Sets
+		if week.IsDeload {
+			sets = (sets + 1) / 2
+		}
+		exercise.Sets = atLeastOne(sets + week.SetsAdjustment)
+		exercise.Reps = atLeastOne(exercise.Reps + week.RepsAdjustment)
+		if exercise.RepRangeMin != nil {
+			repMin := atLeastOne(*exercise.RepRangeMin + week.RepsAdjustment)
+			exercise.RepRangeMin = &repMin
+		}
+		if exercise.RepRangeMax != nil {
+			repMax := atLeastOne(*exercise.RepRangeMax + week.RepsAdjustment)
+			exercise.RepRangeMax = &repMax
+		}
+		if exercise.Weight != nil && week.IntensityPercent != 100 {
+			weight := math.Round(*exercise.Weight*week.IntensityPercent) / 100
+			exercise.Weight = &weight
+		}
+	}
+}
+
+// validateProgramRoutines verifica que las rutinas existan, sean del autor y no estén en la papelera
+func validateProgramRoutines(q dbQuerier, authorID string, routineIDs []int) error {
+	unique := make(map[int]bool, len(routineIDs))
+	ids := make([]int64, 0, len(routineIDs))
+	for _, id := range routineIDs {
+		if !unique[id] {
+			unique[id] = true
+			ids = append(ids, int64(id))
+		}
+	}
+
+	var found int
+	err := q.QueryRow(`
+		SELECT COUNT(*) FROM user_routines WHERE id = ANY($1) AND user_id = $2 AND deleted_at IS NULL
+	`, pq.Array(ids), authorID).Scan(&found)
+	if err != nil {
+		return err
+	}
+	if found != len(ids) {
+		return errProgramRoutines
+	}
+	return nil
+}
+
+// saveProgramDays reemplaza la semana tipo del programa por las rutinas indicadas, en orden
+func saveProgramDays(q dbQuerier, programID int, routineIDs []int) error {
+	if _, err := q.Exec("DELETE FROM program_days WHERE program_id = $1", programID); err != nil {
+		return err
+	}
+	for i, routineID := range routineIDs {
+		_, err := q.Exec(`
+			INSERT INTO program_days (program_id, day_number, routine_id) VALUES ($1, $2, $3)
+		`, programID, i+1, routineID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveProgramWeeks reemplaza los ajustes semanales del programa
+func saveProgramWeeks(q dbQuerier, programID int, adjustments []models.ProgramWeek) error {
+	if _, err := q.Exec("DELETE FROM program_weeks WHERE program_id = $1", programID); err != nil {
+		return err
+	}
+	for _, week := range adjustments {
+		_, err := q.Exec(`
+			INSERT INTO program_weeks (program_id, week_number, sets_adjustment, reps_adjustment, intensity_percent, is_deload, notes)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, programID, week.Week, week.SetsAdjustment, week.RepsAdjustment, week.IntensityPercent, week.IsDeload, week.Notes)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadProgramDays obtiene la semana tipo del programa con el número de día guardado. Las rutinas
+// de un programa no se pueden mandar a la papelera, así que cada día conserva su rutina.
+func loadProgramDays(q dbQuerier, programID int) ([]models.ProgramDay, error) {
+	rows, err := q.Query(`
+		SELECT pd.day_number, pd.routine_id, ur.name
+		FROM program_days pd
+		JOIN user_routines ur ON ur.id = pd.routine_id
+		WHERE pd.program_id = $1
+		ORDER BY pd.day_number ASC
+	`, programID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	days := []models.ProgramDay{}
+	for rows.Next() {
+		var day models.ProgramDay
+		if err := rows.Scan(&day.DayNumber, &day.RoutineID, &day.RoutineName); err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
+
+// loadProgramWeeks obtiene los ajustes semanales guardados del programa
+func loadProgramWeeks(q dbQuerier, programID int) ([]models.ProgramWeek, error) {
+	rows, err := q.Query(`
+		SELECT week_number, sets_adjustment, reps_adjustment, intensity_percent, is_deload, notes
+		FROM program_weeks
+		WHERE program_id = $1
+		ORDER BY week_number ASC
+	`, programID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var weeks []models.ProgramWeek
+	for rows.Next() {
+		var week models.ProgramWeek
+		if err := rows.Scan(&week.Week, &week.SetsAdjustment, &week.RepsAdjustment, &week.IntensityPercent, &week.IsDeload, &week.Notes); err != nil {
+			return nil, err
+		}
+		weeks = append(weeks, week)
+	}
+	return weeks, rows.Err()
+}
+
+// loadWorkoutDayProgramWeek obtiene los ajustes de la semana del programa con la que se inició el día, si hay
+func loadWorkoutDayProgramWeek(q dbQuerier, workoutDayID int) (*models.ProgramWeek, error) {
+	var programID, week int
+	err := q.QueryRow(`
+		SELECT pe.program_id, wd.program_week
+		FROM workout_days wd
+		JOIN program_enrollments pe ON pe.id = wd.program_enrollment_id
+		WHERE wd.id = $1 AND wd.program_week IS NOT NULL
+	`, workoutDayID).Scan(&programID, &week)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	weeks, err := loadProgramWeeks(q, programID)
+	if err != nil {
+		return nil, err
+	}
+	adjustment := programWeekFor(weeks, week)
+	return &adjustment, nil
+}
+
+// programColumns son las columnas que se leen de un programa. Debe mantenerse en el mismo orden que scanProgram.
+const programColumns = `p.id, p.author_id, up.name, p.name, p.description, p.weeks, p.is_published, p.created_at, p.updated_at,
+	(SELECT COUNT(*) FROM program_days pd JOIN user_routines ur ON ur.id = pd.routine_id
+		WHERE pd.program_id = p.id AND ur.deleted_at IS NULL)`
+
+// programVisibleFilter limita los programas a los publicados, los propios y los que el usuario sigue o siguió
+const programVisibleFilter = `(p.is_published OR p.author_id = $1
+	OR EXISTS(SELECT 1 FROM program_enrollments pe WHERE pe.program_id = p.id AND pe.user_id = $1))`
+
+// scanProgram lee una fila seleccionada con programColumns
+func scanProgram(row interface{ Scan(...interface{}) error }, program *models.Program) error {
+	return row.Scan(
+		&program.ID,
+		&program.AuthorID,
+		&program.AuthorName,
+		&program.Name,
+		&program.Description,
+		&program.Weeks,
+		&program.IsPublished,
+		&program.CreatedAt,
+		&program.UpdatedAt,
+		&program.DaysPerWeek,
+	)
+}
+
+// loadProgram obtiene un programa visible para el usuario con su semana tipo y los ajustes de cada semana
+func loadProgram(q dbQuerier, userID string, programID int) (*models.Program, error) {
+	var program models.Program
+	err := scanProgram(q.QueryRow(`
+		SELECT `+programColumns+`
+		FROM programs p
+		LEFT JOIN user_profiles up ON up.user_id = p.author_id
+		WHERE p.id = $2 AND `+programVisibleFilter, userID, programID), &program)
+	if err == sql.ErrNoRows {
+		return nil, errProgramNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if program.Days, err = loadProgramDays(q, programID); err != nil {
+		return nil, err
+	}
+	weeks, err := loadProgramWeeks(q, programID)
+	if err != nil {
+		return nil, err
+	}
+	program.WeekAdjustments = make([]models.ProgramWeek, program.Weeks)
+	for week := 1; week <= program.Weeks; week++ {
+		program.WeekAdjustments[week-1] = programWeekFor(weeks, week)
+	}
+	return &program, nil
+}
+
+// lockOwnProgram bloquea un programa del autor para modificarlo
+func lockOwnProgram(q dbQuerier, userID string, programID int) (weeks int, err error) {
+	var authorID string
+	err = q.QueryRow("SELECT author_id, weeks FROM programs WHERE id = $1 FOR UPDATE", programID).Scan(&authorID, &weeks)
+	if err == sql.ErrNoRows {
+		return 0, errProgramNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	if authorID != userID {
+		return 0, errProgramForbidden
+	}
+	return weeks, nil
+}
+
+// writeProgramError responde con el código HTTP que corresponde a un error de programas
+func writeProgramError(w http.ResponseWriter, err error) {
+	switch err {
+	case errProgramNotFound:
+		http.Error(w, "Programa no encontrado", http.StatusNotFound)
+	case errProgramForbidden:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errProgramRoutines:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		fmt.Printf("Error en programa: %v\n", err)
+		http.Error(w, "Error procesando el programa", http.StatusInternalServerError)
+	}
+}
+
+// GetProgramsHandler lista los programas publicados y los creados por el usuario
+func GetProgramsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT `+programColumns+`
+		FROM programs p
+		LEFT JOIN user_profiles up ON up.user_id = p.author_id
+		WHERE `+programVisibleFilter+`
+		ORDER BY p.created_at DESC
+	`, userID)
+	if err != nil {
+		fmt.Printf("Error consultando programas: %v\n", err)
+		http.Error(w, "Error obteniendo programas", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	programs := []models.Program{}
+	for rows.Next() {
+		var program models.Program
+		if err := scanProgram(rows, &program); err != nil {
+			fmt.Printf("Error escaneando programa: %v\n", err)
+			http.Error(w, "Error procesando programa", http.StatusInternalServerError)
+			return
+		}
+		programs = append(programs, program)
+	}
+
+	json.NewEncoder(w).Encode(programs)
+}
+
+// GetProgramHandler obtiene un programa con su semana tipo, los ejercicios de cada rutina y los ajustes de cada semana
+func GetProgramHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	programID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID de programa inválido", http.StatusBadRequest)
+		return
+	}
+
+	program, err := loadProgram(database.DB, userID, programID)
+	if err != nil {
+		writeProgramError(w, err)
+		return
+	}
+
+	unit := getUserWeightUnit(userID)
+	for i := range program.Days {
+		exercises, err := loadRoutineExercises(database.DB, program.Days[i].RoutineID)
+		if err != nil {
+			fmt.Printf("Error consultando ejercicios de rutina: %v\n", err)
+			http.Error(w, "Error obteniendo ejercicios del programa", http.StatusInternalServerError)
+			return
+		}
+		convertRoutineExerciseWeights(exercises, unit)
+		program.Days[i].Exercises = exercises
+	}
+
+	json.NewEncoder(w).Encode(program)
+}
+
+// CreateProgramHandler crea un programa con rutinas del autor (profesor o administrador)
+func CreateProgramHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateProgramRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 255 {
+		http.Error(w, "el nombre debe tener entre 1 y 255 caracteres", http.StatusBadRequest)
+		return
+	}
+	if req.Weeks < 1 || req.Weeks > maxProgramWeeks {
+		http.Error(w, fmt.Sprintf("weeks debe estar entre 1 y %d", maxProgramWeeks), http.StatusBadRequest)
+		return
+	}
+	adjustments, err := validateProgramWeeks(req.Weeks, req.WeekAdjustments)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(req.RoutineIDs) == 0 || len(req.RoutineIDs) > maxProgramDays {
+		http.Error(w, errProgramDayCount.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	if err := validateProgramRoutines(tx, userID, req.RoutineIDs); err != nil {
+		writeProgramError(w, err)
+		return
+	}
+
+	var description *string
+	if strings.TrimSpace(req.Description) != "" {
+		description = &req.Description
+	}
+
+	var programID int
+	err = tx.QueryRow(`
+		INSERT INTO programs (author_id, name, description, weeks, is_published)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, userID, req.Name, description, req.Weeks, req.IsPublished).Scan(&programID)
+	if err != nil {
+		fmt.Printf("Error creando programa: %v\n", err)
+		http.Error(w, "Error creando programa", http.StatusInternalServerError)
+		return
+	}
+
+	if err := saveProgramDays(tx, programID, req.RoutineIDs); err != nil {
+		fmt.Printf("Error guardando días del programa: %v\n", err)
+		http.Error(w, "Error creando programa", http.StatusInternalServerError)
+		return
+	}
+	if err := saveProgramWeeks(tx, programID, adjustments); err != nil {
+		fmt.Printf("Error guardando semanas del programa: %v\n", err)
+		http.Error(w, "Error creando programa", http.StatusInternalServerError)
+		return
+	}
+
+	program, err := loadProgram(tx, userID, programID)
+	if err != nil {
+		writeProgramError(w, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, "Error confirmando transacción", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(program)
+}
+
+// UpdateProgramHandler actualiza un programa del autor. Los cambios alcanzan a quienes ya lo siguen:
+// si el programa se acorta, los seguimientos que quedan fuera se completan al consultarlos.
+func UpdateProgramHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	programID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID de programa inválido", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateProgramRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if req.Name == nil && req.Description == nil && req.Weeks == nil && req.IsPublished == nil &&
+		req.RoutineIDs == nil && req.WeekAdjustments == nil {
+		http.Error(w, "no hay campos para actualizar", http.StatusBadRequest)
+		return
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || len(name) > 255 {
+			http.Error(w, "el nombre debe tener entre 1 y 255 caracteres", http.StatusBadRequest)
+			return
+		}
+		req.Name = &name
+	}
+	if req.Weeks != nil && (*req.Weeks < 1 || *req.Weeks > maxProgramWeeks) {
+		http.Error(w, fmt.Sprintf("weeks debe estar entre 1 y %d", maxProgramWeeks), http.StatusBadRequest)
+		return
+	}
+	if req.RoutineIDs != nil && (len(req.RoutineIDs) == 0 || len(req.RoutineIDs) > maxProgramDays) {
+		http.Error(w, errProgramDayCount.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	weeks, err := lockOwnProgram(tx, userID, programID)
+	if err != nil {
+		writeProgramError(w, err)
+		return
+	}
+	if req.Weeks != nil {
+		weeks = *req.Weeks
+	}
+
+	if req.WeekAdjustments != nil {
+		adjustments, err := validateProgramWeeks(weeks, req.WeekAdjustments)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := saveProgramWeeks(tx, programID, adjustments); err != nil {
+			fmt.Printf("Error guardando semanas del programa: %v\n", err)
+			http.Error(w, "Error actualizando programa", http.StatusInternalServerError)
+			return
+		}
+	} else if _, err := tx.Exec("DELETE FROM program_weeks WHERE program_id = $1 AND week_number > $2", programID, weeks); err != nil {
+		fmt.Printf("Error recortando semanas del programa: %v\n", err)
+		http.Error(w, "Error actualizando programa", http.StatusInternalServerError)
+		return
+	}
+
+	if req.RoutineIDs != nil {
+		if err := validateProgramRoutines(tx, userID, req.RoutineIDs); err != nil {
+			writeProgramError(w, err)
+			return
+		}
+		if err := saveProgramDays(tx, programID, req.RoutineIDs); err != nil {
+			fmt.Printf("Error guardando días del programa: %v\n", err)
+			http.Error(w, "Error actualizando programa", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Descripción vacía borra la existente
+	updateDescription := req.Description != nil
+	var description *string
+	if updateDescription && strings.TrimSpace(*req.Description) != "" {
+		description = req.Description
+	}
+
+	_, err = tx.Exec(`
+		UPDATE programs
+		SET name = COALESCE($1, name),
+			description = CASE WHEN $2 THEN $3 ELSE description END,
+			weeks = $4,
+			is_published = COALESCE($5, is_published),
+			updated_at = NOW()
+		WHERE id = $6
+	`, req.Name, updateDescription, description, weeks, req.IsPublished, programID)
+	if err != nil {
+		fmt.Printf("Error actualizando programa: %v\n", err)
+		http.Error(w, "Error actualizando programa", http.StatusInternalServerError)
+		return
+	}
+
+	program, err := loadProgram(tx, userID, programID)
+	if err != nil {
+		writeProgramError(w, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, "Error confirmando transacción", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(program)
+}
+
+// DeleteProgramHandler elimina un programa del autor si nadie lo está siguiendo
+func DeleteProgramHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	programID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID de programa inválido", http.StatusBadRequest)
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := lockOwnProgram(tx, userID, programID); err != nil {
+		writeProgramError(w, err)
+		return
+	}
+
+	var activeEnrollments int
+	err = tx.QueryRow("SELECT COUNT(*) FROM program_enrollments WHERE program_id = $1 AND status = $2",
+		programID, models.ProgramEnrollmentActive).Scan(&activeEnrollments)
+	if err != nil {
+		http.Error(w, "Error verificando seguimientos del programa", http.StatusInternalServerError)
+		return
+	}
+	if activeEnrollments > 0 {
+		http.Error(w, fmt.Sprintf("El programa tiene %d alumnos siguiéndolo; despublicalo en lugar de eliminarlo", activeEnrollments), http.StatusConflict)
+		return
+	}
+
+	if _, err := tx.Exec("DELETE FROM programs WHERE id = $1", programID); err != nil {
+		fmt.Printf("Error eliminando programa: %v\n", err)
+		http.Error(w, "Error eliminando programa", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, "Error confirmando transacción", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+func TestApplyProgramWeek(t *testing.T) {
+	weight := 100.0
+	repMin, repMax := 6, 10
+	base := func() []models.RoutineExercise {
+		w := weight
+		min, max := repMin, repMax
+		exercises := []models.RoutineExercise{
+			{ExerciseID: 1, Sets: 3, Reps: 8, Weight: &w},
+			{ExerciseID: 2, Sets: 1, Reps: 1},
+		}
+		exercises[0].RepRangeMin = &min
+		exercises[0].RepRangeMax = &max
+		return exercises
+	}
+
+	exercises := base()
+	applyProgramWeek(exercises, models.ProgramWeek{Week: 2, SetsAdjustment: 1, RepsAdjustment: -2, IntensityPercent: 105})
+	if exercises[0].Sets != 4 || exercises[0].Reps != 6 || *exercises[0].Weight != 105 {
+		t.Errorf("semana de carga mal aplicada: %+v", exercises[0])
+	}
+	if *exercises[0].RepRangeMin != 4 || *exercises[0].RepRangeMax != 8 {
+		t.Errorf("rango de repeticiones mal ajustado: %d-%d", *exercises[0].RepRangeMin, *exercises[0].RepRangeMax)
+	}
+	if exercises[1].Sets != 2 || exercises[1].Reps != 1 || exercises[1].Weight != nil {
+		t.Errorf("las repeticiones no deberían bajar de 1: %+v", exercises[1])
+	}
+
+	exercises = base()
+	applyProgramWeek(exercises, models.ProgramWeek{Week: 4, IntensityPercent: deloadIntensityPercent, IsDeload: true})
+	if exercises[0].Sets != 2 || exercises[0].Reps != 8 || *exercises[0].Weight != 60 {
+		t.Errorf("semana de descarga mal aplicada: %+v", exercises[0])
+	}
+	if exercises[1].Sets != 1 {
+		t.Errorf("la descarga debería dejar al menos una serie: %+v", exercises[1])
+	}
+}
+
+func TestValidateProgramWeeks(t *testing.T) {
+	intensity := 110.0
+	adjustments, err := validateProgramWeeks(4, []models.ProgramWeekRequest{
+		{Week: 2, SetsAdjustment: 1, IntensityPercent: &intensity},
+		{Week: 4, IsDeload: true},
+	})
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if adjustments[0].IntensityPercent != 110 || adjustments[1].IntensityPercent != deloadIntensityPercent {
+		t.Errorf("intensidades incorrectas: %+v", adjustments)
+	}
+	if week := programWeekFor(adjustments, 3); week.IntensityPercent != 100 || week.IsDeload {
+		t.Errorf("una semana sin ajustes debería usar la rutina tal cual: %+v", week)
+	}
+
+	invalid := [][]models.ProgramWeekRequest{
+		{{Week: 5}},
+		{{Week: 1}, {Week: 1}},
+		{{Week: 1, SetsAdjustment: 11}},
+	}
+	for _, requests := range invalid {
+		if _, err := validateProgramWeeks(4, requests); err == nil {
+			t.Errorf("se esperaba error para %+v", requests)
+		}
+	}
+}
+
+func TestAdvanceEnrollment(t *testing.T) {
+	enrollment := &models.ProgramEnrollment{
+		Status:      models.ProgramEnrollmentActive,
+		Weeks:       2,
+		DaysPerWeek: 3,
+		CurrentWeek: 1,
+		CurrentDay:  3,
+	}
+	dayID := 10
+	enrollment.CurrentWorkoutDayID = &dayID
+
+	advanceEnrollment(enrollment)
+	if enrollment.CurrentWeek != 2 || enrollment.CurrentDay != 1 || enrollment.CurrentWorkoutDayID != nil {
+		t.Fatalf("debería pasar a la semana 2, día 1: %+v", enrollment)
+	}
+	programProgress(enrollment)
+	if enrollment.CompletedSessions != 3 || enrollment.TotalSessions != 6 || enrollment.Progress != 0.5 {
+		t.Errorf("progreso incorrecto: %d/%d (%v)", enrollment.CompletedSessions, enrollment.TotalSessions, enrollment.Progress)
+	}
+
+	enrollment.CurrentDay = 3
+	advanceEnrollment(enrollment)
+	if enrollment.Status != models.ProgramEnrollmentCompleted || enrollment.CompletedAt == nil {
+		t.Fatalf("el programa debería quedar completado: %+v", enrollment)
+	}
+	programProgress(enrollment)
+	if enrollment.Progress != 1 {
+		t.Errorf("progreso de un programa completado: %v", enrollment.Progress)
+	}
+}
+
+// execRecorder es un dbQuerier que solo registra las sentencias Exec; las consultas fallan
+type execRecorder struct {
+	execs []string
+}
+
+func (e *execRecorder) Exec(query string, args ...interface{}) (sql.Result, error) {
+	e.execs = append(e.execs, query)
+	return driver.RowsAffected(1), nil
+}
+
+func (e *execRecorder) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("consulta no esperada")
+}
+
+func (e *execRecorder) QueryRow(query string, args ...interface{}) *sql.Row {
+	panic("consulta no esperada: " + query)
+}
+
+func TestSyncEnrollment(t *testing.T) {
+	tests := []struct {
+		name       string
+		enrollment models.ProgramEnrollment
+		wantWeek   int
+		wantDay    int
+		wantStatus string
+		wantSaved  bool
+	}{
+		{
+			name:       "sin sesión iniciada no cambia",
+			enrollment: models.ProgramEnrollment{Status: models.ProgramEnrollmentActive, Weeks: 4, DaysPerWeek: 3, CurrentWeek: 2, CurrentDay: 2},
+			wantWeek:   2, wantDay: 2, wantStatus: models.ProgramEnrollmentActive,
+		},
+		{
+			name:       "día eliminado del programa pasa a la semana siguiente",
+			enrollment: models.ProgramEnrollment{Status: models.ProgramEnrollmentActive, Weeks: 4, DaysPerWeek: 2, CurrentWeek: 2, CurrentDay: 3},
+			wantWeek:   3, wantDay: 1, wantStatus: models.ProgramEnrollmentActive, wantSaved: true,
+		},
+		{
+			name:       "semana eliminada del programa lo completa",
+			enrollment: models.ProgramEnrollment{Status: models.ProgramEnrollmentActive, Weeks: 3, DaysPerWeek: 2, CurrentWeek: 4, CurrentDay: 1},
+			wantWeek:   3, wantDay: 2, wantStatus: models.ProgramEnrollmentCompleted, wantSaved: true,
+		},
+	}
+
+	for _, tt := range tests {
+		q := &execRecorder{}
+		enrollment := tt.enrollment
+		if err := syncEnrollment(q, "user", &enrollment, "2024-05-16", time.UTC); err != nil {
+			t.Fatalf("%s: error inesperado %v", tt.name, err)
+		}
+		if enrollment.CurrentWeek != tt.wantWeek || enrollment.CurrentDay != tt.wantDay || enrollment.Status != tt.wantStatus {
+			t.Errorf("%s: semana %d día %d (%s)", tt.name, enrollment.CurrentWeek, enrollment.CurrentDay, enrollment.Status)
+		}
+		if saved := len(q.execs) > 0; saved != tt.wantSaved {
+			t.Errorf("%s: guardado %v, se esperaba %v", tt.name, saved, tt.wantSaved)
+		}
+	}
+}
+
+func TestProgramSessionRoutine(t *testing.T) {
+	days := []models.ProgramDay{{DayNumber: 1, RoutineID: 10}, {DayNumber: 2, RoutineID: 20}}
+	routineA, routineB := 10, 20
+	started := 99
+
+	tests := []struct {
+		name       string
+		enrollment models.ProgramEnrollment
+		routineID  *int
+		want       bool
+	}{
+		{"rutina del día que toca", models.ProgramEnrollment{Status: models.ProgramEnrollmentActive, CurrentDay: 2}, &routineB, true},
+		{"rutina de otro día", models.ProgramEnrollment{Status: models.ProgramEnrollmentActive, CurrentDay: 2}, &routineA, false},
+		{"día sin rutina", models.ProgramEnrollment{Status: models.ProgramEnrollmentActive, CurrentDay: 1}, nil, false},
+		{"sesión ya iniciada", models.ProgramEnrollment{Status: models.ProgramEnrollmentActive, CurrentDay: 1, CurrentWorkoutDayID: &started}, &routineA, false},
+		{"programa completado", models.ProgramEnrollment{Status: models.ProgramEnrollmentCompleted, CurrentDay: 1}, &routineA, false},
+	}
+
+	for _, tt := range tests {
+		if got := programSessionRoutine(&tt.enrollment, days, tt.routineID); got != tt.want {
+			t.Errorf("%s: %v, se esperaba %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/gorilla/mux"
+)
+
+// maxProgressBuckets es la cantidad máxima de períodos que se devuelven: unos dos años en semanas
+// o diez en meses
+const maxProgressBuckets = 120
+
+// progressSample representa una serie usada para calcular el progreso
+type progressSample struct {
+	Date         time.Time
+	WorkoutDayID int
+	Weight       float64
+	Reps         int
+}
+
+// periodStart devuelve el inicio del período (lunes de la semana o primer día del mes) de una fecha
+func periodStart(date time.Time, bucket string) time.Time {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if bucket == "month" {
+		return date.AddDate(0, 0, -date.Day()+1)
+	}
+	// time.Weekday empieza en domingo; las semanas empiezan el lunes
+	offset := (int(date.Weekday()) + 6) % 7
+	return date.AddDate(0, 0, -offset)
+}
+
+// nextPeriod avanza al inicio del período siguiente
+func nextPeriod(start time.Time, bucket string) time.Time {
+	if bucket == "month" {
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 7)
+}
+
+// progressBucketCount devuelve cuántos períodos hay entre from y to, ambos incluidos
+func progressBucketCount(bucket string, from, to time.Time) int {
+	start, end := periodStart(from, bucket), periodStart(to, bucket)
+	if bucket == "month" {
+		return (end.Year()-start.Year())*12 + int(end.Month()-start.Month()) + 1
+	}
+	return int(end.Sub(start).Hours()/24)/7 + 1
+}
+
+// aggregateProgress agrupa las series en semanas o meses entre from y to, incluyendo los períodos sin actividad
+func aggregateProgress(samples []progressSample, bucket string, from, to time.Time) []models.ProgressBucket {
+	buckets := []models.ProgressBucket{}
+	index := make(map[string]int)
+	for start := periodStart(from, bucket); !start.After(to); start = nextPeriod(start, bucket) {
+		key := start.Format("2006-01-02")
+		index[key] = len(buckets)
+		buckets = append(buckets, models.ProgressBucket{PeriodStart: key})
+	}
+
+	sessions := make(map[string]map[int]bool)
+	for _, sample := range samples {
+		key := periodStart(sample.Date, bucket).Format("2006-01-02")
+		i, ok := index[key]
+		if !ok {
+			continue
+		}
+		b := &buckets[i]
+
+		if sample.Weight > b.TopSetWeight || (sample.Weight == b.TopSetWeight && sample.Reps > b.TopSetReps) {
+			b.TopSetWeight = sample.Weight
+			b.TopSetReps = sample.Reps
+		}
+		if oneRM := estimateOneRepMax(sample.Weight, sample.Reps); oneRM > b.Estimated1RM {
+			b.Estimated1RM = oneRM
+		}
+		b.TotalVolume += sample.Weight * float64(sample.Reps)
+		b.TotalReps += sample.Reps
+		b.TotalSets++
+
+		if sessions[key] == nil {
+			sessions[key] = make(map[int]bool)
+		}
+		sessions[key][sample.WorkoutDayID] = true
+		b.Sessions = len(sessions[key])
+	}
+
+	return buckets
+}
+
+// GetExerciseProgressHandler obtiene la evolución semanal o mensual de un ejercicio para el usuario actual.
+// Las series de calentamiento no se tienen en cuenta.
+func GetExerciseProgressHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	exerciseID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	bucket := r.URL.Query().Get("bucket")
+	if bucket == "" {
+		bucket = "week"
+	}
+	if bucket != "week" && bucket != "month" {
+		http.Error(w, "bucket debe ser week o month", http.StatusBadRequest)
+		return
+	}
+
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Por defecto: hasta hoy, 6 meses para semanas y 12 meses para meses
+	now := time.Now().In(getUserLocation(r, userID))
+	if to == "" {
+		to = now.Format("2006-01-02")
+	}
+	toDate, _ := time.Parse("2006-01-02", to)
+	if from == "" {
+		months := 6
+		if bucket == "month" {
+			months = 12
+		}
+		from = toDate.AddDate(0, -months, 0).Format("2006-01-02")
+	}
+	fromDate, _ := time.Parse("2006-01-02", from)
+	if fromDate.After(toDate) {
+		http.Error(w, errInvalidDateRange.Error(), http.StatusBadRequest)
+		return
+	}
+	if progressBucketCount(bucket, fromDate, toDate) > maxProgressBuckets {
+		http.Error(w, fmt.Sprintf("el rango no puede tener más de %d períodos; para rangos largos usar bucket=month", maxProgressBuckets), http.StatusBadRequest)
+		return
+	}
+
+	var exerciseName string
+	err = database.DB.QueryRow("SELECT name FROM exercises WHERE id = $1", exerciseID).Scan(&exerciseName)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Ejercicio no encontrado", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error consultando ejercicio", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// En ejercicios con peso corporal el progreso se mide con la carga total (peso corporal + lastre)
+	query := `
+		SELECT wd.date, w.workout_day_id, ` + workoutLoad + `, w.reps
+		FROM workouts w
+		JOIN workout_days wd ON w.workout_day_id = wd.id
+		WHERE w.user_id = $1 AND w.exercise_id = $2 AND wd.date BETWEEN $3 AND $4 AND w.deleted_at IS NULL
+			AND ` + workingSetFilter + `
+		ORDER BY wd.date ASC
+	`
+
+	rows, err := database.DB.Query(query, userID, exerciseID, from, to)
+	if err != nil {
+		fmt.Printf("Error consultando progreso: %v\n", err)
+		http.Error(w, "Error consultando progreso", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	var samples []progressSample
+	for rows.Next() {
+		var sample progressSample
+		if err := rows.Scan(&sample.Date, &sample.WorkoutDayID, &sample.Weight, &sample.Reps); err != nil {
+			fmt.Printf("Error escaneando serie: %v\n", err)
+			continue
+		}
+		samples = append(samples, sample)
+	}
+
+	// Peso, 1RM y volumen se expresan en la unidad del usuario
+	unit := getUserWeightUnit(userID)
+	buckets := aggregateProgress(samples, bucket, fromDate, toDate)
+	for i := range buckets {
+		buckets[i].TopSetWeight = fromKilograms(buckets[i].TopSetWeight, unit)
+		buckets[i].Estimated1RM = fromKilograms(buckets[i].Estimated1RM, unit)
+		buckets[i].TotalVolume = fromKilograms(buckets[i].TotalVolume, unit)
+	}
+
+	json.NewEncoder(w).Encode(models.ExerciseProgress{
+		ExerciseID:   exerciseID,
+		ExerciseName: exerciseName,
+		Bucket:       bucket,
+		WeightUnit:   unit,
+		From:         from,
+		To:           to,
+		Buckets:      buckets,
+	})
+}
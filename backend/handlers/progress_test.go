@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeriodStart(t *testing.T) {
+	date := time.Date(2024, 3, 14, 18, 30, 0, 0, time.UTC) // jueves
+
+	if got := periodStart(date, "week").Format("2006-01-02"); got != "2024-03-11" {
+		t.Errorf("la semana debería empezar el lunes 2024-03-11, empieza %s", got)
+	}
+	if got := periodStart(date, "month").Format("2006-01-02"); got != "2024-03-01" {
+		t.Errorf("el mes debería empezar el 2024-03-01, empieza %s", got)
+	}
+	sunday := time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)
+	if got := periodStart(sunday, "week").Format("2006-01-02"); got != "2024-03-11" {
+		t.Errorf("el domingo pertenece a la semana del lunes anterior, se obtuvo %s", got)
+	}
+}
+
+func TestAggregateProgress(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	samples := []progressSample{
+		{Date: date("2024-03-04"), WorkoutDayID: 1, Weight: 100, Reps: 5},
+		{Date: date("2024-03-04"), WorkoutDayID: 1, Weight: 100, Reps: 6},
+		{Date: date("2024-03-07"), WorkoutDayID: 2, Weight: 90, Reps: 10},
+		{Date: date("2024-03-20"), WorkoutDayID: 3, Weight: 105, Reps: 3},
+		{Date: date("2024-02-20"), WorkoutDayID: 4, Weight: 200, Reps: 1}, // fuera del rango
+	}
+
+	buckets := aggregateProgress(samples, "week", date("2024-03-06"), date("2024-03-24"))
+
+	if len(buckets) != 3 {
+		t.Fatalf("se esperaban 3 semanas (incluida la vacía), se obtuvieron %d: %+v", len(buckets), buckets)
+	}
+	first := buckets[0]
+	if first.PeriodStart != "2024-03-04" || first.Sessions != 2 || first.TotalSets != 3 || first.TotalReps != 21 {
+		t.Errorf("primera semana incorrecta: %+v", first)
+	}
+	if first.TopSetWeight != 100 || first.TopSetReps != 6 {
+		t.Errorf("a igual peso la mejor serie es la de más repeticiones: %+v", first)
+	}
+	if first.TotalVolume != 100*5+100*6+90*10 {
+		t.Errorf("volumen incorrecto: %v", first.TotalVolume)
+	}
+	if buckets[1].TotalSets != 0 || buckets[1].Sessions != 0 {
+		t.Errorf("la semana sin actividad debería estar vacía: %+v", buckets[1])
+	}
+	if buckets[2].TopSetWeight != 105 || buckets[2].Sessions != 1 {
+		t.Errorf("tercera semana incorrecta: %+v", buckets[2])
+	}
+
+	months := aggregateProgress(samples, "month", date("2024-02-01"), date("2024-03-31"))
+	if len(months) != 2 || months[0].TopSetWeight != 200 || months[1].Sessions != 3 {
+		t.Errorf("meses incorrectos: %+v", months)
+	}
+}
+
+func TestProgressBucketCount(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	tests := []struct {
+		bucket   string
+		from, to string
+		want     int
+	}{
+		{"week", "2024-03-06", "2024-03-10", 1},
+		{"week", "2024-03-06", "2024-03-24", 3},
+		{"month", "2024-01-31", "2024-03-01", 3},
+		{"month", "2023-12-15", "2024-01-02", 2},
+	}
+	for _, tt := range tests {
+		from, to := date(tt.from), date(tt.to)
+		got := progressBucketCount(tt.bucket, from, to)
+		if got != tt.want {
+			t.Errorf("%s %s..%s: %d períodos, se esperaban %d", tt.bucket, tt.from, tt.to, got, tt.want)
+		}
+		if n := len(aggregateProgress(nil, tt.bucket, from, to)); n != got {
+			t.Errorf("%s %s..%s: aggregateProgress arma %d períodos y progressBucketCount cuenta %d", tt.bucket, tt.from, tt.to, n, got)
+		}
+	}
+
+	if progressBucketCount("week", date("2020-01-01"), date("2024-01-01")) <= maxProgressBuckets {
+		t.Errorf("cuatro años en semanas deberían superar el máximo")
+	}
+	if progressBucketCount("month", date("2020-01-01"), date("2024-01-01")) > maxProgressBuckets {
+		t.Errorf("cuatro años en meses no deberían superar el máximo")
+	}
+}
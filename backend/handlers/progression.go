@@ -0,0 +1,454 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/gorilla/mux"
+)
+
+// Valores por defecto de la progresión cuando el ejercicio de rutina no los configura
+const (
+	defaultProgressionSets   = 3
+	defaultRepRangeMin       = 8
+	defaultRepRangeWidth     = 4   // rep_range_max = rep_range_min + 4
+	defaultWeightIncrement   = 2.5 // kg
+	defaultDeloadAfterMisses = 3
+	defaultDeloadPercent     = 10.0
+	// deloadWeightStep es el redondeo del peso de descarga (kg)
+	deloadWeightStep = 0.5
+	// progressionHistoryMonths es cuánto historial se mira para sugerir
+	progressionHistoryMonths = 6
+)
+
+var errRoutineExerciseNotFound = errors.New("ejercicio de rutina no encontrado")
+
+// progressionParams son los parámetros efectivos de la progresión, con los valores por defecto aplicados
+type progressionParams struct {
+	rule          string
+	sets          int
+	repMin        int
+	repMax        int
+	increment     float64
+	deloadAfter   int
+	deloadPercent float64
+}
+
+// validateProgressionConfig valida los parámetros de progresión de un ejercicio de rutina
+func validateProgressionConfig(config *models.ProgressionConfig) error {
+	if config.ProgressionRule != nil && *config.ProgressionRule != models.ProgressionDouble && *config.ProgressionRule != models.ProgressionLinear {
+		return errors.New("progression_rule inválida, usar double_progression o linear")
+	}
+	for _, reps := range []*int{config.RepRangeMin, config.RepRangeMax} {
+		if reps != nil && (*reps < 1 || *reps > 100) {
+			return errors.New("el rango de repeticiones debe estar entre 1 y 100")
+		}
+	}
+	if config.RepRangeMin != nil && config.RepRangeMax != nil && *config.RepRangeMin > *config.RepRangeMax {
+		return errors.New("rep_range_min no puede ser mayor que rep_range_max")
+	}
+	if config.WeightIncrement != nil && (*config.WeightIncrement <= 0 || *config.WeightIncrement > 50) {
+		return errors.New("weight_increment debe ser mayor a 0 y como máximo 50")
+	}
+	if config.DeloadAfterMisses != nil && (*config.DeloadAfterMisses < 0 || *config.DeloadAfterMisses > 10) {
+		return errors.New("deload_after_misses debe estar entre 0 y 10")
+	}
+	if config.DeloadPercent != nil && (*config.DeloadPercent <= 0 || *config.DeloadPercent > 50) {
+		return errors.New("deload_percent debe ser mayor a 0 y como máximo 50")
+	}
+	return nil
+}
+
+// validateRoutineExercisePrescription valida la prescripción de un ejercicio de rutina (los valores nulos
+// no se validan); el peso se valida en la unidad en que llega
+func validateRoutineExercisePrescription(orderIndex, sets, reps *int, weight *float64, restTimeSeconds *int) error {
+	if orderIndex != nil && *orderIndex < 0 {
+		return errors.New("order_index no puede ser negativo")
+	}
+	if sets != nil && (*sets < 1 || *sets > maxSetNumber) {
+		return fmt.Errorf("sets debe estar entre 1 y %d", maxSetNumber)
+	}
+	if reps != nil && (*reps < 1 || *reps > 100) {
+		return errors.New("reps debe estar entre 1 y 100")
+	}
+	if weight != nil && (*weight <= 0 || *weight > 1000) {
+		return errors.New("weight debe ser mayor a 0 y como máximo 1000")
+	}
+	if restTimeSeconds != nil && (*restTimeSeconds < 0 || *restTimeSeconds > 3600) {
+		return errors.New("rest_time_seconds debe estar entre 0 y 3600")
+	}
+	return nil
+}
+
+// summarizeProgressionSessions agrupa las series por sesión (en el orden recibido, de la más
+// reciente a la más antigua) y resume cada una con su peso más alto
+func summarizeProgressionSessions(samples []progressSample) []models.ProgressionSession {
+	var sessions []models.ProgressionSession
+	index := make(map[int]int)
+	for _, sample := range samples {
+		i, ok := index[sample.WorkoutDayID]
+		if !ok {
+			i = len(sessions)
+			index[sample.WorkoutDayID] = i
+			sessions = append(sessions, models.ProgressionSession{Date: sample.Date.Format("2006-01-02"), Weight: sample.Weight, MinReps: sample.Reps})
+		}
+		session := &sessions[i]
+		switch {
+		case sample.Weight > session.Weight:
+			session.Weight = sample.Weight
+			session.Sets = 1
+			session.MinReps = sample.Reps
+		case sample.Weight == session.Weight:
+			session.Sets++
+			if sample.Reps < session.MinReps {
+				session.MinReps = sample.Reps
+			}
+		}
+	}
+	return sessions
+}
+
+// withoutBodyweight pasa el peso de la sugerencia y de la última sesión de carga total a peso agregado,
+// restando el peso corporal. En ejercicios sin peso corporal bodyweight es 0 y no cambia nada.
+func withoutBodyweight(suggestion *models.ProgressionSuggestion, bodyweight float64) {
+	if bodyweight <= 0 {
+		return
+	}
+	if suggestion.Weight != nil {
+		weight := math.Max(math.Round((*suggestion.Weight-bodyweight)*100)/100, 0)
+		suggestion.Weight = &weight
+	}
+	if suggestion.LastSession != nil {
+		suggestion.LastSession.Weight = math.Max(math.Round((suggestion.LastSession.Weight-bodyweight)*100)/100, 0)
+	}
+}
+
+// resolveProgressionParams aplica los valores por defecto a la configuración del ejercicio de rutina.
+// Sin rutina se usa doble progresión anclada en las repeticiones de la primera sesión con el peso actual,
+// así el rango se reinicia cada vez que sube el peso.
+func resolveProgressionParams(prescription *models.RoutineExercise, sessions []models.ProgressionSession) progressionParams {
+	p := progressionParams{
+		rule:          models.ProgressionDouble,
+		sets:          defaultProgressionSets,
+		repMin:        defaultRepRangeMin,
+		increment:     defaultWeightIncrement,
+		deloadAfter:   defaultDeloadAfterMisses,
+		deloadPercent: defaultDeloadPercent,
+	}
+
+	if prescription == nil {
+		if len(sessions) > 0 {
+			p.sets = sessions[0].Sets
+			for _, session := range sessions {
+				if session.Weight != sessions[0].Weight {
+					break
+				}
+				p.repMin = session.MinReps
+			}
+			if p.repMin < 1 {
+				p.repMin = 1
+			}
+		}
+		p.repMax = p.repMin + defaultRepRangeWidth
+		return p
+	}
+
+	config := prescription.ProgressionConfig
+	p.sets = prescription.Sets
+	p.repMin = prescription.Reps
+	if config.ProgressionRule != nil {
+		p.rule = *config.ProgressionRule
+	}
+	if config.RepRangeMin != nil {
+		p.repMin = *config.RepRangeMin
+	}
+	p.repMax = p.repMin
+	if p.rule == models.ProgressionDouble {
+		p.repMax = p.repMin + defaultRepRangeWidth
+		if config.RepRangeMax != nil && *config.RepRangeMax >= p.repMin {
+			p.repMax = *config.RepRangeMax
+		}
+	}
+	if config.WeightIncrement != nil {
+		p.increment = *config.WeightIncrement
+	}
+	if config.DeloadAfterMisses != nil {
+		p.deloadAfter = *config.DeloadAfterMisses
+	}
+	if config.DeloadPercent != nil {
+		p.deloadPercent = *config.DeloadPercent
+	}
+	return p
+}
+
+// roundToStep redondea un peso al múltiplo de step más cercano
+func roundToStep(weight, step float64) float64 {
+	return math.Round(weight/step) * step
+}
+
+// suggestProgression sugiere el peso y las repeticiones de la próxima sesión a partir de las sesiones
+// anteriores (de la más reciente a la más antigua) y, si hay, lo planificado en la rutina.
+// Los pesos están en kg.
+func suggestProgression(sessions []models.ProgressionSession, prescription *models.RoutineExercise) models.ProgressionSuggestion {
+	p := resolveProgressionParams(prescription, sessions)
+	suggestion := models.ProgressionSuggestion{
+		Rule:        p.rule,
+		Sets:        p.sets,
+		Reps:        p.repMin,
+		RepRangeMin: p.repMin,
+		RepRangeMax: p.repMax,
+	}
+	if prescription != nil {
+		id := prescription.ID
+		suggestion.RoutineExerciseID = &id
+	}
+
+	if len(sessions) == 0 {
+		suggestion.Action = models.ProgressionActionStart
+		if prescription != nil && prescription.Weight != nil {
+			weight := *prescription.Weight
+			suggestion.Weight = &weight
+			suggestion.Reason = "Sin sesiones anteriores: se usa lo planificado en la rutina"
+		} else {
+			suggestion.Reason = fmt.Sprintf("Sin sesiones anteriores: elegí un peso con el que llegues a %d repeticiones", p.repMin)
+		}
+		return suggestion
+	}
+
+	for i := range sessions {
+		sessions[i].Met = sessions[i].Sets >= p.sets && sessions[i].MinReps >= p.repMin
+	}
+	last := sessions[0]
+	suggestion.LastSession = &last
+	for _, session := range sessions {
+		if session.Weight != last.Weight || session.Met {
+			break
+		}
+		suggestion.ConsecutiveMisses++
+	}
+
+	weight := last.Weight
+	switch {
+	case last.Weight > 0 && p.deloadAfter > 0 && suggestion.ConsecutiveMisses >= p.deloadAfter:
+		weight = roundToStep(last.Weight*(1-p.deloadPercent/100), deloadWeightStep)
+		suggestion.Action = models.ProgressionActionDeload
+		suggestion.Reason = fmt.Sprintf("%d sesiones seguidas sin completar %d×%d con este peso: bajá un %g%% y volvé a progresar",
+			suggestion.ConsecutiveMisses, p.sets, p.repMin, p.deloadPercent)
+	case !last.Met:
+		suggestion.Action = models.ProgressionActionRepeat
+		suggestion.Reason = fmt.Sprintf("No completaste %d×%d en la última sesión: repetí el peso", p.sets, p.repMin)
+	case last.Weight == 0:
+		suggestion.Action = models.ProgressionActionIncreaseReps
+		suggestion.Reps = last.MinReps + 1
+		suggestion.Reason = "Ejercicio sin peso: sumá una repetición por serie"
+	case p.rule == models.ProgressionDouble && last.MinReps < p.repMax:
+		suggestion.Action = models.ProgressionActionIncreaseReps
+		suggestion.Reps = last.MinReps + 1
+		suggestion.Reason = fmt.Sprintf("Completaste %d×%d: sumá una repetición hasta llegar a %d en todas las series", p.sets, last.MinReps, p.repMax)
+	default:
+		weight += p.increment
+		suggestion.Action = models.ProgressionActionIncreaseWeight
+		if p.rule == models.ProgressionDouble {
+			suggestion.Reason = fmt.Sprintf("Llegaste a %d repeticiones en todas las series: subí el peso y volvé a %d", p.repMax, p.repMin)
+		} else {
+			suggestion.Reason = fmt.Sprintf("Completaste %d×%d: subí el peso", p.sets, p.repMin)
+		}
+	}
+	suggestion.Weight = &weight
+
+	return suggestion
+}
+
+// loadRoutineExercisePrescription obtiene un ejercicio de una rutina del usuario
+func loadRoutineExercisePrescription(q dbQuerier, userID string, routineExerciseID int) (*models.RoutineExercise, error) {
+	var routineID int
+	err := q.QueryRow(`
+		SELECT re.routine_id
+		FROM routine_exercises re
+		JOIN user_routines ur ON ur.id = re.routine_id
+		WHERE re.id = $1 AND ur.user_id = $2 AND ur.deleted_at IS NULL
+	`, routineExerciseID, userID).Scan(&routineID)
+	if err == sql.ErrNoRows {
+		return nil, errRoutineExerciseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	exercises, err := loadRoutineExercises(q, routineID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range exercises {
+		if exercises[i].ID == routineExerciseID {
+			return &exercises[i], nil
+		}
+	}
+	return nil, errRoutineExerciseNotFound
+}
+
+// findRoutineExerciseID busca el ejercicio de rutina a usar como prescripción: el primero del
+// ejercicio en la rutina indicada o, sin rutina, en la rutina iniciada hoy
+func findRoutineExerciseID(q dbQuerier, userID string, exerciseID int, routineID *int, today string) (*int, error) {
+	var id int
+	var err error
+	if routineID != nil {
+		err = q.QueryRow(`
+			SELECT re.id
+			FROM routine_exercises re
+			JOIN user_routines ur ON ur.id = re.routine_id
+			WHERE re.routine_id = $1 AND ur.user_id = $2 AND re.exercise_id = $3 AND ur.deleted_at IS NULL
+			ORDER BY re.order_index ASC, re.id ASC
+			LIMIT 1
+		`, *routineID, userID, exerciseID).Scan(&id)
+		if err == sql.ErrNoRows {
+			return nil, errRoutineExerciseNotFound
+		}
+	} else {
+		err = q.QueryRow(`
+			SELECT re.id
+			FROM routine_exercises re
+			JOIN workout_days wd ON wd.routine_id = re.routine_id
+			JOIN user_routines ur ON ur.id = re.routine_id
+			WHERE wd.user_id = $1 AND wd.date = $2 AND re.exercise_id = $3
+				AND wd.deleted_at IS NULL AND ur.deleted_at IS NULL
+			ORDER BY re.order_index ASC, re.id ASC
+			LIMIT 1
+		`, userID, today, exerciseID).Scan(&id)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// GetProgressionSuggestionHandler sugiere el peso y las repeticiones de la próxima sesión de un ejercicio.
+// Usa las sesiones anteriores a hoy y, si se indica routine_exercise_id o routine_id (o hay una rutina
+// iniciada hoy con el ejercicio), lo planificado en la rutina y su configuración de progresión.
+func GetProgressionSuggestionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	exerciseID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	routineExerciseID, err := parseOptionalID(r, "routine_exercise_id")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	routineID, err := parseOptionalID(r, "routine_id")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var exerciseName string
+	err = database.DB.QueryRow("SELECT name FROM exercises WHERE id = $1", exerciseID).Scan(&exerciseName)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Ejercicio no encontrado", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error consultando ejercicio", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	today := time.Now().In(getUserLocation(r, userID))
+	todayDate := today.Format("2006-01-02")
+
+	if routineExerciseID == nil {
+		routineExerciseID, err = findRoutineExerciseID(database.DB, userID, exerciseID, routineID, todayDate)
+		if err != nil {
+			writeRoutineExerciseError(w, err)
+			return
+		}
+	}
+
+	var prescription *models.RoutineExercise
+	if routineExerciseID != nil {
+		prescription, err = loadRoutineExercisePrescription(database.DB, userID, *routineExerciseID)
+		if err == nil && prescription.ExerciseID != exerciseID {
+			err = errRoutineExerciseNotFound
+		}
+		if err != nil {
+			writeRoutineExerciseError(w, err)
+			return
+		}
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT wd.date, w.workout_day_id, `+workoutLoad+`, w.reps, COALESCE(w.bodyweight_kg, 0)
+		FROM workouts w
+		JOIN workout_days wd ON w.workout_day_id = wd.id
+		WHERE w.user_id = $1 AND w.exercise_id = $2 AND wd.date < $3 AND wd.date >= $4 AND w.deleted_at IS NULL
+			AND `+workingSetFilter+`
+		ORDER BY wd.date DESC, w.workout_day_id DESC, w.set ASC
+	`, userID, exerciseID, todayDate, today.AddDate(0, -progressionHistoryMonths, 0).Format("2006-01-02"))
+	if err != nil {
+		fmt.Printf("Error consultando historial del ejercicio: %v\n", err)
+		http.Error(w, "Error consultando historial del ejercicio", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	// La progresión se calcula sobre la carga (peso más peso corporal) y se expresa como el peso
+	// a agregar, descontando el peso corporal de la última sesión
+	var samples []progressSample
+	var lastBodyweight float64
+	for rows.Next() {
+		var sample progressSample
+		var bodyweight float64
+		if err := rows.Scan(&sample.Date, &sample.WorkoutDayID, &sample.Weight, &sample.Reps, &bodyweight); err != nil {
+			fmt.Printf("Error escaneando serie: %v\n", err)
+			continue
+		}
+		if len(samples) == 0 {
+			lastBodyweight = bodyweight
+		}
+		samples = append(samples, sample)
+	}
+
+	suggestion := suggestProgression(summarizeProgressionSessions(samples), prescription)
+	withoutBodyweight(&suggestion, lastBodyweight)
+	suggestion.ExerciseID = exerciseID
+	suggestion.ExerciseName = exerciseName
+
+	// Los pesos se expresan en la unidad del usuario
+	suggestion.WeightUnit = getUserWeightUnit(userID)
+	suggestion.Weight = weightPtrFromKilograms(suggestion.Weight, suggestion.WeightUnit)
+	if suggestion.LastSession != nil {
+		suggestion.LastSession.Weight = fromKilograms(suggestion.LastSession.Weight, suggestion.WeightUnit)
+	}
+
+	json.NewEncoder(w).Encode(suggestion)
+}
+
+// writeRoutineExerciseError responde con el status correspondiente a un error al buscar un ejercicio de rutina
+func writeRoutineExerciseError(w http.ResponseWriter, err error) {
+	if err == errRoutineExerciseNotFound {
+		http.Error(w, "Ejercicio de rutina no encontrado", http.StatusNotFound)
+		return
+	}
+	fmt.Printf("Error obteniendo ejercicio de rutina: %v\n", err)
+	http.Error(w, "Error obteniendo ejercicio de rutina", http.StatusInternalServerError)
+}
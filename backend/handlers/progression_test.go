@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+func TestSummarizeProgressionSessions(t *testing.T) {
+	day1 := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, -3)
+	samples := []progressSample{
+		{Date: day1, WorkoutDayID: 2, Weight: 60, Reps: 10},
+		{Date: day1, WorkoutDayID: 2, Weight: 62.5, Reps: 8},
+		{Date: day1, WorkoutDayID: 2, Weight: 62.5, Reps: 7},
+		{Date: day2, WorkoutDayID: 1, Weight: 60, Reps: 9},
+	}
+
+	sessions := summarizeProgressionSessions(samples)
+	if len(sessions) != 2 {
+		t.Fatalf("cantidad de sesiones incorrecta: %d", len(sessions))
+	}
+	if s := sessions[0]; s.Date != "2024-05-10" || s.Weight != 62.5 || s.Sets != 2 || s.MinReps != 7 {
+		t.Errorf("la sesión más reciente debería resumirse con su peso más alto: %+v", s)
+	}
+	if s := sessions[1]; s.Weight != 60 || s.Sets != 1 || s.MinReps != 9 {
+		t.Errorf("sesión anterior incorrecta: %+v", s)
+	}
+}
+
+func TestSuggestProgression(t *testing.T) {
+	weight := 60.0
+	linear := models.ProgressionLinear
+	repMax := 10
+	increment := 5.0
+	noDeload := 0
+
+	tests := []struct {
+		name         string
+		sessions     []models.ProgressionSession
+		prescription *models.RoutineExercise
+		action       string
+		weight       float64
+		reps         int
+	}{
+		{
+			name:         "sin historial usa lo planificado",
+			prescription: &models.RoutineExercise{ID: 1, Sets: 3, Reps: 8, Weight: &weight},
+			action:       models.ProgressionActionStart,
+			weight:       60,
+			reps:         8,
+		},
+		{
+			name:         "doble progresión suma repeticiones",
+			sessions:     []models.ProgressionSession{{Weight: 60, Sets: 3, MinReps: 8}},
+			prescription: &models.RoutineExercise{ID: 1, Sets: 3, Reps: 8, ProgressionConfig: models.ProgressionConfig{RepRangeMax: &repMax}},
+			action:       models.ProgressionActionIncreaseReps,
+			weight:       60,
+			reps:         9,
+		},
+		{
+			name:         "doble progresión sube el peso al tope del rango",
+			sessions:     []models.ProgressionSession{{Weight: 60, Sets: 3, MinReps: 10}},
+			prescription: &models.RoutineExercise{ID: 1, Sets: 3, Reps: 8, ProgressionConfig: models.ProgressionConfig{RepRangeMax: &repMax}},
+			action:       models.ProgressionActionIncreaseWeight,
+			weight:       62.5,
+			reps:         8,
+		},
+		{
+			name:         "lineal con incremento configurado",
+			sessions:     []models.ProgressionSession{{Weight: 100, Sets: 5, MinReps: 5}},
+			prescription: &models.RoutineExercise{ID: 1, Sets: 5, Reps: 5, ProgressionConfig: models.ProgressionConfig{ProgressionRule: &linear, WeightIncrement: &increment}},
+			action:       models.ProgressionActionIncreaseWeight,
+			weight:       105,
+			reps:         5,
+		},
+		{
+			name: "repite tras no cumplir",
+			sessions: []models.ProgressionSession{
+				{Weight: 100, Sets: 5, MinReps: 4},
+				{Weight: 100, Sets: 5, MinReps: 3},
+				{Weight: 97.5, Sets: 5, MinReps: 5},
+			},
+			prescription: &models.RoutineExercise{ID: 1, Sets: 5, Reps: 5, ProgressionConfig: models.ProgressionConfig{ProgressionRule: &linear}},
+			action:       models.ProgressionActionRepeat,
+			weight:       100,
+			reps:         5,
+		},
+		{
+			name: "descarga tras varias sesiones sin cumplir",
+			sessions: []models.ProgressionSession{
+				{Weight: 100, Sets: 5, MinReps: 4},
+				{Weight: 100, Sets: 5, MinReps: 4},
+				{Weight: 100, Sets: 4, MinReps: 5},
+			},
+			prescription: &models.RoutineExercise{ID: 1, Sets: 5, Reps: 5, ProgressionConfig: models.ProgressionConfig{ProgressionRule: &linear}},
+			action:       models.ProgressionActionDeload,
+			weight:       90,
+			reps:         5,
+		},
+		{
+			name: "descarga desactivada",
+			sessions: []models.ProgressionSession{
+				{Weight: 100, Sets: 5, MinReps: 4},
+				{Weight: 100, Sets: 5, MinReps: 4},
+				{Weight: 100, Sets: 5, MinReps: 4},
+			},
+			prescription: &models.RoutineExercise{ID: 1, Sets: 5, Reps: 5, ProgressionConfig: models.ProgressionConfig{DeloadAfterMisses: &noDeload}},
+			action:       models.ProgressionActionRepeat,
+			weight:       100,
+			reps:         5,
+		},
+		{
+			name: "sin rutina el rango arranca en la primera sesión con el peso actual",
+			sessions: []models.ProgressionSession{
+				{Weight: 40, Sets: 3, MinReps: 11},
+				{Weight: 40, Sets: 3, MinReps: 7},
+			},
+			action: models.ProgressionActionIncreaseWeight,
+			weight: 42.5,
+			reps:   7,
+		},
+		{
+			name:     "sin peso suma repeticiones",
+			sessions: []models.ProgressionSession{{Weight: 0, Sets: 3, MinReps: 12}},
+			action:   models.ProgressionActionIncreaseReps,
+			weight:   0,
+			reps:     13,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestion := suggestProgression(tt.sessions, tt.prescription)
+			if suggestion.Action != tt.action {
+				t.Errorf("acción %s, se esperaba %s (%s)", suggestion.Action, tt.action, suggestion.Reason)
+			}
+			if suggestion.Weight == nil || *suggestion.Weight != tt.weight {
+				t.Errorf("peso %v, se esperaba %v", suggestion.Weight, tt.weight)
+			}
+			if suggestion.Reps != tt.reps {
+				t.Errorf("repeticiones %d, se esperaban %d", suggestion.Reps, tt.reps)
+			}
+			if suggestion.Reason == "" {
+				t.Error("la sugerencia debería incluir un motivo")
+			}
+		})
+	}
+}
+
+func TestValidateProgressionConfig(t *testing.T) {
+	invalidRule := "wave"
+	min, max := 12, 8
+	zero := 0.0
+
+	tests := []struct {
+		name   string
+		config models.ProgressionConfig
+		valid  bool
+	}{
+		{"vacía", models.ProgressionConfig{}, true},
+		{"regla inválida", models.ProgressionConfig{ProgressionRule: &invalidRule}, false},
+		{"rango invertido", models.ProgressionConfig{RepRangeMin: &min, RepRangeMax: &max}, false},
+		{"incremento cero", models.ProgressionConfig{WeightIncrement: &zero}, false},
+	}
+
+	for _, tt := range tests {
+		if err := validateProgressionConfig(&tt.config); (err == nil) != tt.valid {
+			t.Errorf("%s: error inesperado %v", tt.name, err)
+		}
+	}
+}
+
+func TestValidateRoutineExercisePrescription(t *testing.T) {
+	tests := []struct {
+		name       string
+		orderIndex *int
+		sets       *int
+		reps       *int
+		weight     *float64
+		rest       *int
+		valid      bool
+	}{
+		{"sin cambios", nil, nil, nil, nil, nil, true},
+		{"valores límite", intPtr(0), intPtr(20), intPtr(100), floatPtr(1000), intPtr(3600), true},
+		{"orden negativo", intPtr(-1), nil, nil, nil, nil, false},
+		{"cero series", nil, intPtr(0), nil, nil, nil, false},
+		{"más de 20 series", nil, intPtr(21), nil, nil, nil, false},
+		{"cero repeticiones", nil, nil, intPtr(0), nil, nil, false},
+		{"más de 100 repeticiones", nil, nil, intPtr(101), nil, nil, false},
+		{"peso cero", nil, nil, nil, floatPtr(0), nil, false},
+		{"descanso negativo", nil, nil, nil, nil, intPtr(-30), false},
+		{"descanso de más de una hora", nil, nil, nil, nil, intPtr(3601), false},
+	}
+
+	for _, tt := range tests {
+		err := validateRoutineExercisePrescription(tt.orderIndex, tt.sets, tt.reps, tt.weight, tt.rest)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: error inesperado %v", tt.name, err)
+		}
+	}
+}
+
+func TestWithoutBodyweight(t *testing.T) {
+	// Dominadas con 80 kg de peso corporal: la carga sugerida de 92.5 son 12.5 kg de lastre
+	weight := 92.5
+	suggestion := models.ProgressionSuggestion{Weight: &weight, LastSession: &models.ProgressionSession{Weight: 90}}
+	withoutBodyweight(&suggestion, 80)
+	if *suggestion.Weight != 12.5 || suggestion.LastSession.Weight != 10 {
+		t.Errorf("peso agregado incorrecto: %v y %v", *suggestion.Weight, suggestion.LastSession.Weight)
+	}
+
+	// Una descarga por debajo del peso corporal no sugiere peso negativo
+	weight = 72
+	suggestion = models.ProgressionSuggestion{Weight: &weight}
+	withoutBodyweight(&suggestion, 80)
+	if *suggestion.Weight != 0 {
+		t.Errorf("el peso agregado no puede ser negativo: %v", *suggestion.Weight)
+	}
+
+	// Sin peso corporal la sugerencia no cambia
+	weight = 100
+	suggestion = models.ProgressionSuggestion{Weight: &weight}
+	withoutBodyweight(&suggestion, 0)
+	if *suggestion.Weight != 100 {
+		t.Errorf("sin peso corporal no debería cambiar: %v", *suggestion.Weight)
+	}
+}
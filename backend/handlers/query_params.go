@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errInvalidDateRange = errors.New("rango de fechas inválido: usar from y to con formato YYYY-MM-DD y from <= to")
+
+// parseDateRange lee los parámetros from y to (YYYY-MM-DD) de la query.
+// Los que no se envían se devuelven como cadena vacía.
+func parseDateRange(r *http.Request) (string, string, error) {
+	from := r.URL.Query().Get("from")
+	to := r.URL.Query().Get("to")
+
+	var fromDate, toDate time.Time
+	var err error
+	if from != "" {
+		if fromDate, err = time.Parse("2006-01-02", from); err != nil {
+			return "", "", errInvalidDateRange
+		}
+	}
+	if to != "" {
+		if toDate, err = time.Parse("2006-01-02", to); err != nil {
+			return "", "", errInvalidDateRange
+		}
+	}
+	if from != "" && to != "" && fromDate.After(toDate) {
+		return "", "", errInvalidDateRange
+	}
+
+	return from, to, nil
+}
+
+// appendDateRangeFilter agrega a la query las condiciones column >= from y column <= to
+// para los extremos que no estén vacíos, numerando los parámetros a continuación de args
+func appendDateRangeFilter(query, column, from, to string, args []interface{}) (string, []interface{}) {
+	if from != "" {
+		args = append(args, from)
+		query += fmt.Sprintf(" AND %s >= $%d", column, len(args))
+	}
+	if to != "" {
+		args = append(args, to)
+		query += fmt.Sprintf(" AND %s <= $%d", column, len(args))
+	}
+	return query, args
+}
+
+// parseIDList lee un parámetro de la query con IDs separados por coma (por ejemplo exercise_ids=1,2,3)
+func parseIDList(r *http.Request, name string) ([]int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	var ids []int
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("%s inválido: usar IDs numéricos separados por coma", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseOptionalBool lee un parámetro booleano opcional de la query (true/false)
+func parseOptionalBool(r *http.Request, name string) (*bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s inválido: usar true o false", name)
+	}
+	return &parsed, nil
+}
+
+// parseOptionalID lee un ID opcional de la query
+func parseOptionalID(r *http.Request, name string) (*int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		return nil, fmt.Errorf("%s inválido", name)
+	}
+	return &id, nil
+}
+
+// parseLimit lee el parámetro limit de la query; si no se envía devuelve defaultLimit
+func parseLimit(r *http.Request, defaultLimit, maxLimit int) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return defaultLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 || limit > maxLimit {
+		return 0, fmt.Errorf("limit debe estar entre 1 y %d", maxLimit)
+	}
+	return limit, nil
+}
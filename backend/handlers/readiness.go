@@ -0,0 +1,425 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+)
+
+// deloadNotificationType es el tipo de notificación que recomienda una semana de descarga
+const deloadNotificationType = "deload_recommendation"
+
+// Umbrales del análisis de recuperación. La semana reciente son los últimos 7 días y la
+// referencia son los 21 días anteriores. El esfuerzo y el ánimo se cargan de 1 a 3 estrellas
+// (0 es sin cargar), así que sus umbrales están en estrellas.
+const (
+	readinessRecentDays      = 7
+	readinessBaselineDays    = 21
+	readinessMinEntries      = 2    // Días con esfuerzo o ánimo cargado que necesita cada ventana
+	readinessEffortRise      = 0.75 // Estrellas de esfuerzo por encima de la referencia (un cuarto de la escala)
+	readinessMoodDrop        = 0.75 // Estrellas de ánimo por debajo de la referencia (un cuarto de la escala)
+	readinessSimilarEffort   = 0    // Diferencia máxima de estrellas de esfuerzo para comparar dos sesiones
+	readinessMinSessions     = 3    // Sesiones de un ejercicio necesarias para evaluar su rendimiento
+	readinessPerformanceDrop = 5.0  // Caída porcentual del 1RM estimado
+	readinessMinTargetSets   = 6    // Series de rutina necesarias para evaluar las repeticiones
+	readinessMissedRepRate   = 0.3  // Proporción de series por debajo de lo planificado
+	readinessVolumeSpike     = 1.3  // Volumen semanal sobre el promedio de las semanas anteriores
+	readinessSignalPenalty   = 25   // Puntos que resta cada señal
+	readinessFatiguedSignals = 2    // Señales a partir de las cuales se recomienda descargar
+)
+
+// exerciseSession representa el mejor 1RM estimado de un ejercicio en un día, con el esfuerzo del día
+type exerciseSession struct {
+	ExerciseID   int
+	ExerciseName string
+	Date         time.Time
+	Estimated1RM float64
+	Effort       int // 0 si no se cargó
+}
+
+// readinessInput reúne los datos de las últimas 4 semanas que usa el análisis
+type readinessInput struct {
+	Days       []daySummary
+	Sessions   []exerciseSession
+	TargetSets int // Series de rutina con repeticiones planificadas en la semana reciente
+	MissedSets int // De ellas, las que quedaron por debajo de lo planificado
+}
+
+// averageRating promedia los valores cargados (mayores a 0); devuelve nil si hay menos de readinessMinEntries
+func averageRating(values []int) *float64 {
+	sum, count := 0, 0
+	for _, value := range values {
+		if value > 0 {
+			sum += value
+			count++
+		}
+	}
+	if count < readinessMinEntries {
+		return nil
+	}
+	average := math.Round(float64(sum)/float64(count)*100) / 100
+	return &average
+}
+
+// similarEffort indica si dos sesiones se hicieron con un esfuerzo comparable; si alguna no tiene
+// esfuerzo cargado se comparan igual
+func similarEffort(a, b int) bool {
+	if a <= 0 || b <= 0 {
+		return true
+	}
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= readinessSimilarEffort
+}
+
+// performanceDrops compara la última sesión de cada ejercicio en la semana reciente con su mejor
+// sesión anterior a esfuerzo similar, y devuelve las caídas de 1RM estimado de al menos readinessPerformanceDrop
+func performanceDrops(sessions []exerciseSession, recentFrom time.Time) []models.ExercisePerformanceDrop {
+	byExercise := make(map[int][]exerciseSession)
+	var order []int
+	for _, session := range sessions {
+		if _, ok := byExercise[session.ExerciseID]; !ok {
+			order = append(order, session.ExerciseID)
+		}
+		byExercise[session.ExerciseID] = append(byExercise[session.ExerciseID], session)
+	}
+
+	drops := []models.ExercisePerformanceDrop{}
+	for _, exerciseID := range order {
+		history := byExercise[exerciseID]
+		if len(history) < readinessMinSessions {
+			continue
+		}
+		sort.Slice(history, func(i, j int) bool { return history[i].Date.Before(history[j].Date) })
+		latest := history[len(history)-1]
+		if latest.Date.Before(recentFrom) || latest.Estimated1RM <= 0 {
+			continue
+		}
+
+		var best *exerciseSession
+		for i := range history[:len(history)-1] {
+			previous := &history[i]
+			if !similarEffort(previous.Effort, latest.Effort) {
+				continue
+			}
+			if best == nil || previous.Estimated1RM > best.Estimated1RM {
+				best = previous
+			}
+		}
+		if best == nil || best.Estimated1RM <= 0 {
+			continue
+		}
+
+		drop := (best.Estimated1RM - latest.Estimated1RM) / best.Estimated1RM * 100
+		if drop < readinessPerformanceDrop {
+			continue
+		}
+		drops = append(drops, models.ExercisePerformanceDrop{
+			ExerciseID:     exerciseID,
+			ExerciseName:   latest.ExerciseName,
+			Date:           latest.Date.Format("2006-01-02"),
+			Estimated1RM:   math.Round(latest.Estimated1RM*100) / 100,
+			PreviousDate:   best.Date.Format("2006-01-02"),
+			Previous1RM:    math.Round(best.Estimated1RM*100) / 100,
+			DropPercentage: math.Round(drop*10) / 10,
+		})
+	}
+	return drops
+}
+
+// analyzeReadiness detecta señales de fatiga acumulada comparando la semana que termina en today
+// con las 3 semanas anteriores: esfuerzo en alza, ánimo en baja, caídas de rendimiento a esfuerzo
+// similar, repeticiones planificadas no logradas y picos de volumen. Con dos o más señales
+// recomienda una semana de descarga. Los volúmenes quedan en kilogramos.
+func analyzeReadiness(input readinessInput, today time.Time) models.ReadinessReport {
+	recentFrom := today.AddDate(0, 0, -(readinessRecentDays - 1))
+	baselineFrom := recentFrom.AddDate(0, 0, -readinessBaselineDays)
+
+	report := models.ReadinessReport{
+		Date:             today.Format("2006-01-02"),
+		PerformanceDrops: []models.ExercisePerformanceDrop{},
+		Signals:          []models.FatigueSignal{},
+	}
+
+	var recentEffort, baselineEffort, recentMood, baselineMood []int
+	for _, day := range input.Days {
+		switch {
+		case day.Date.After(today) || day.Date.Before(baselineFrom):
+			continue
+		case day.Date.Before(recentFrom):
+			baselineEffort = append(baselineEffort, day.Effort)
+			baselineMood = append(baselineMood, day.Mood)
+			report.BaselineWeeklyVolume += day.Volume
+		default:
+			recentEffort = append(recentEffort, day.Effort)
+			recentMood = append(recentMood, day.Mood)
+			report.WeeklyVolume += day.Volume
+		}
+	}
+	report.RecentEffort = averageRating(recentEffort)
+	report.BaselineEffort = averageRating(baselineEffort)
+	report.RecentMood = averageRating(recentMood)
+	report.BaselineMood = averageRating(baselineMood)
+	report.WeeklyVolume = math.Round(report.WeeklyVolume*100) / 100
+	report.BaselineWeeklyVolume = math.Round(report.BaselineWeeklyVolume/(readinessBaselineDays/readinessRecentDays)*100) / 100
+
+	if report.RecentEffort != nil && report.BaselineEffort != nil && *report.RecentEffort >= *report.BaselineEffort+readinessEffortRise {
+		report.Signals = append(report.Signals, models.FatigueSignal{
+			Type:     models.FatigueSignalEffortRising,
+			Message:  fmt.Sprintf("Tu esfuerzo promedio subió de %.1f a %.1f esta semana", *report.BaselineEffort, *report.RecentEffort),
+			Value:    *report.RecentEffort,
+			Baseline: *report.BaselineEffort,
+		})
+	}
+
+	if report.RecentMood != nil && report.BaselineMood != nil && *report.RecentMood <= *report.BaselineMood-readinessMoodDrop {
+		report.Signals = append(report.Signals, models.FatigueSignal{
+			Type:     models.FatigueSignalMoodFalling,
+			Message:  fmt.Sprintf("Tu ánimo promedio bajó de %.1f a %.1f esta semana", *report.BaselineMood, *report.RecentMood),
+			Value:    *report.RecentMood,
+			Baseline: *report.BaselineMood,
+		})
+	}
+
+	report.PerformanceDrops = performanceDrops(input.Sessions, recentFrom)
+	if len(report.PerformanceDrops) > 0 {
+		names := make([]string, len(report.PerformanceDrops))
+		worst := 0.0
+		for i, drop := range report.PerformanceDrops {
+			names[i] = drop.ExerciseName
+			worst = math.Max(worst, drop.DropPercentage)
+		}
+		report.Signals = append(report.Signals, models.FatigueSignal{
+			Type:     models.FatigueSignalPerformanceDrop,
+			Message:  fmt.Sprintf("Tu 1RM estimado bajó con un esfuerzo similar en %s", formatList(names)),
+			Value:    worst,
+			Baseline: readinessPerformanceDrop,
+		})
+	}
+
+	if input.TargetSets >= readinessMinTargetSets {
+		rate := math.Round(float64(input.MissedSets)/float64(input.TargetSets)*100) / 100
+		report.MissedRepRate = &rate
+		if rate >= readinessMissedRepRate {
+			report.Signals = append(report.Signals, models.FatigueSignal{
+				Type:     models.FatigueSignalMissedReps,
+				Message:  fmt.Sprintf("No llegaste a las repeticiones planificadas en %d de %d series", input.MissedSets, input.TargetSets),
+				Value:    rate,
+				Baseline: readinessMissedRepRate,
+			})
+		}
+	}
+
+	if report.BaselineWeeklyVolume > 0 && report.WeeklyVolume >= report.BaselineWeeklyVolume*readinessVolumeSpike {
+		report.Signals = append(report.Signals, models.FatigueSignal{
+			Type:     models.FatigueSignalVolumeSpike,
+			Message:  fmt.Sprintf("Tu volumen de esta semana es %.0f%% mayor que el promedio de las anteriores", (report.WeeklyVolume/report.BaselineWeeklyVolume-1)*100),
+			Value:    report.WeeklyVolume,
+			Baseline: report.BaselineWeeklyVolume,
+		})
+	}
+
+	report.Score = 100 - readinessSignalPenalty*len(report.Signals)
+	if report.Score < 0 {
+		report.Score = 0
+	}
+	switch {
+	case len(report.Signals) >= readinessFatiguedSignals:
+		report.Status = models.ReadinessFatigued
+		report.DeloadRecommended = true
+	case len(report.Signals) > 0:
+		report.Status = models.ReadinessCaution
+	default:
+		report.Status = models.ReadinessReady
+	}
+
+	return report
+}
+
+// loadExerciseSessions obtiene el mejor 1RM estimado de cada ejercicio de fuerza por día desde from
+func loadExerciseSessions(userID, from string) ([]exerciseSession, error) {
+	rows, err := database.DB.Query(`
+		SELECT w.exercise_id, e.name, wd.date::text, COALESCE(wd.effort, 0), `+workoutLoad+`, w.reps
+		FROM workouts w
+		JOIN workout_days wd ON w.workout_day_id = wd.id
+		JOIN exercises e ON w.exercise_id = e.id
+		WHERE w.user_id = $1 AND wd.date >= $2 AND w.deleted_at IS NULL AND wd.deleted_at IS NULL
+			AND NOT e.is_sport AND w.reps > 0 AND `+workingSetFilter+`
+		ORDER BY wd.date ASC, w.exercise_id ASC
+	`, userID, from)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Se queda con la mejor serie de cada ejercicio en cada día
+	index := make(map[string]int)
+	var sessions []exerciseSession
+	for rows.Next() {
+		var session exerciseSession
+		var date string
+		var weight float64
+		var reps int
+		if err := rows.Scan(&session.ExerciseID, &session.ExerciseName, &date, &session.Effort, &weight, &reps); err != nil {
+			return nil, err
+		}
+		if session.Date, err = time.Parse("2006-01-02", date); err != nil {
+			return nil, err
+		}
+		session.Estimated1RM = estimateOneRepMax(weight, reps)
+
+		key := fmt.Sprintf("%d:%s", session.ExerciseID, date)
+		if i, ok := index[key]; ok {
+			if session.Estimated1RM > sessions[i].Estimated1RM {
+				sessions[i].Estimated1RM = session.Estimated1RM
+			}
+			continue
+		}
+		index[key] = len(sessions)
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// loadMissedRepSets cuenta las series hechas desde from en días de rutina y cuántas quedaron por
+// debajo de las repeticiones planificadas (el mínimo del rango o, si no hay, las repeticiones del ejercicio)
+func loadMissedRepSets(userID, from string) (targetSets, missedSets int, err error) {
+	err = database.DB.QueryRow(`
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE w.reps < target.reps)
+		FROM workouts w
+		JOIN workout_days wd ON w.workout_day_id = wd.id
+		JOIN exercises e ON w.exercise_id = e.id
+		JOIN LATERAL (
+			SELECT MIN(COALESCE(re.rep_range_min, re.reps)) AS reps
+			FROM routine_exercises re
+			WHERE re.routine_id = wd.routine_id AND re.exercise_id = w.exercise_id
+		) target ON target.reps IS NOT NULL
+		WHERE w.user_id = $1 AND wd.date >= $2 AND w.deleted_at IS NULL AND wd.deleted_at IS NULL
+			AND NOT e.is_sport AND `+workingSetFilter+`
+	`, userID, from).Scan(&targetSets, &missedSets)
+	return targetSets, missedSets, err
+}
+
+// buildReadinessReport analiza la recuperación del usuario al día de hoy en su zona horaria
+func buildReadinessReport(userID string, loc *time.Location) (models.ReadinessReport, error) {
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	recentFrom := today.AddDate(0, 0, -(readinessRecentDays - 1))
+	from := recentFrom.AddDate(0, 0, -readinessBaselineDays).Format("2006-01-02")
+
+	var input readinessInput
+	var err error
+	if input.Days, err = loadDaySummaries(userID, from, today.Format("2006-01-02")); err != nil {
+		return models.ReadinessReport{}, err
+	}
+	if input.Sessions, err = loadExerciseSessions(userID, from); err != nil {
+		return models.ReadinessReport{}, err
+	}
+	if input.TargetSets, input.MissedSets, err = loadMissedRepSets(userID, recentFrom.Format("2006-01-02")); err != nil {
+		return models.ReadinessReport{}, err
+	}
+
+	report := analyzeReadiness(input, today)
+	report.WeightUnit = getUserWeightUnit(userID)
+	report.WeeklyVolume = fromKilograms(report.WeeklyVolume, report.WeightUnit)
+	report.BaselineWeeklyVolume = fromKilograms(report.BaselineWeeklyVolume, report.WeightUnit)
+	for i := range report.PerformanceDrops {
+		report.PerformanceDrops[i].Estimated1RM = fromKilograms(report.PerformanceDrops[i].Estimated1RM, report.WeightUnit)
+		report.PerformanceDrops[i].Previous1RM = fromKilograms(report.PerformanceDrops[i].Previous1RM, report.WeightUnit)
+	}
+	for i := range report.Signals {
+		if report.Signals[i].Type == models.FatigueSignalVolumeSpike {
+			report.Signals[i].Value = report.WeeklyVolume
+			report.Signals[i].Baseline = report.BaselineWeeklyVolume
+		}
+	}
+	return report, nil
+}
+
+// notifyDeloadRecommendation crea una notificación recomendando una semana de descarga,
+// como mucho una por semana. Los errores solo se registran.
+func notifyDeloadRecommendation(userID string, report models.ReadinessReport, loc *time.Location) {
+	if !report.DeloadRecommended {
+		return
+	}
+
+	today, _ := time.Parse("2006-01-02", report.Date)
+	week := periodStart(today, "week")
+	weekStart := time.Date(week.Year(), week.Month(), week.Day(), 0, 0, 0, 0, loc)
+
+	var alreadyNotified bool
+	err := database.DB.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM notifications WHERE user_id = $1 AND type = $2 AND created_at >= $3)
+	`, userID, deloadNotificationType, weekStart).Scan(&alreadyNotified)
+	if err != nil {
+		fmt.Printf("Error verificando notificación de descarga: %v\n", err)
+		return
+	}
+	if alreadyNotified {
+		return
+	}
+
+	messages := make([]string, len(report.Signals))
+	for i, signal := range report.Signals {
+		messages[i] = signal.Message
+	}
+	message := "Detectamos señales de fatiga acumulada: " + strings.Join(messages, "; ") +
+		". Te recomendamos una semana de descarga con menos series y peso."
+
+	dataJSON, _ := json.Marshal(map[string]interface{}{
+		"date":    report.Date,
+		"score":   report.Score,
+		"signals": report.Signals,
+	})
+
+	_, err = database.DB.Exec(`
+		INSERT INTO notifications (user_id, type, title, message, data, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, userID, deloadNotificationType, "Es momento de descargar 😮‍💨", message, string(dataJSON), time.Now())
+	if err != nil {
+		fmt.Printf("Error creando notificación de descarga: %v\n", err)
+	}
+}
+
+// checkDeloadRecommendation analiza la recuperación y notifica si corresponde descargar.
+// Se usa al cargar esfuerzo o ánimo; los errores solo se registran.
+func checkDeloadRecommendation(userID string, loc *time.Location) {
+	report, err := buildReadinessReport(userID, loc)
+	if err != nil {
+		fmt.Printf("Error analizando recuperación: %v\n", err)
+		return
+	}
+	notifyDeloadRecommendation(userID, report, loc)
+}
+
+// GetReadinessHandler devuelve el análisis de recuperación del usuario: esfuerzo y ánimo de la
+// última semana frente a las 3 anteriores, caídas de rendimiento, repeticiones no logradas y picos
+// de volumen. Solo lee: la notificación de descarga se crea al cargar esfuerzo o ánimo.
+func GetReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	loc := getUserLocation(r, userID)
+	report, err := buildReadinessReport(userID, loc)
+	if err != nil {
+		fmt.Printf("Error analizando recuperación: %v\n", err)
+		http.Error(w, "Error obteniendo el análisis de recuperación", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+func readinessDay(date string, effort, mood int, volume float64) daySummary {
+	d, _ := time.Parse("2006-01-02", date)
+	return daySummary{Date: d, Effort: effort, Mood: mood, Sets: 10, Volume: volume}
+}
+
+func readinessSession(exerciseID int, date string, e1rm float64, effort int) exerciseSession {
+	d, _ := time.Parse("2006-01-02", date)
+	return exerciseSession{ExerciseID: exerciseID, ExerciseName: "Sentadilla", Date: d, Estimated1RM: e1rm, Effort: effort}
+}
+
+func signalTypes(report models.ReadinessReport) map[string]bool {
+	types := make(map[string]bool)
+	for _, signal := range report.Signals {
+		types[signal.Type] = true
+	}
+	return types
+}
+
+func TestAnalyzeReadinessReady(t *testing.T) {
+	today, _ := time.Parse("2006-01-02", "2024-06-28")
+	input := readinessInput{
+		Days: []daySummary{
+			readinessDay("2024-06-03", 2, 2, 3000),
+			readinessDay("2024-06-10", 2, 3, 3000),
+			readinessDay("2024-06-17", 2, 3, 3000),
+			readinessDay("2024-06-24", 2, 2, 3000),
+			readinessDay("2024-06-27", 3, 3, 0),
+		},
+	}
+
+	report := analyzeReadiness(input, today)
+	if report.Status != models.ReadinessReady || report.Score != 100 || report.DeloadRecommended {
+		t.Fatalf("se esperaba ready con 100 puntos, se obtuvo %s con %d: %+v", report.Status, report.Score, report.Signals)
+	}
+	if report.RecentEffort == nil || *report.RecentEffort != 2.5 {
+		t.Errorf("esfuerzo reciente %v, se esperaba 2.5", report.RecentEffort)
+	}
+	if report.WeeklyVolume != 3000 || report.BaselineWeeklyVolume != 3000 {
+		t.Errorf("volumen %v frente a %v, se esperaba 3000 frente a 3000", report.WeeklyVolume, report.BaselineWeeklyVolume)
+	}
+	if report.MissedRepRate != nil {
+		t.Errorf("sin series de rutina no debería calcularse la tasa de repeticiones no logradas")
+	}
+}
+
+func TestAnalyzeReadinessFatigued(t *testing.T) {
+	today, _ := time.Parse("2006-01-02", "2024-06-28")
+	input := readinessInput{
+		Days: []daySummary{
+			readinessDay("2024-06-03", 2, 3, 2000),
+			readinessDay("2024-06-10", 2, 3, 2000),
+			readinessDay("2024-06-17", 2, 3, 2000),
+			readinessDay("2024-06-24", 3, 2, 2000),
+			readinessDay("2024-06-27", 3, 1, 1000),
+		},
+		Sessions: []exerciseSession{
+			readinessSession(1, "2024-06-03", 130, 2),
+			readinessSession(1, "2024-06-10", 125, 3),
+			readinessSession(1, "2024-06-17", 110, 3),
+			readinessSession(1, "2024-06-27", 112, 3),
+		},
+		TargetSets: 10,
+		MissedSets: 4,
+	}
+
+	report := analyzeReadiness(input, today)
+	types := signalTypes(report)
+	for _, want := range []string{
+		models.FatigueSignalEffortRising,
+		models.FatigueSignalMoodFalling,
+		models.FatigueSignalPerformanceDrop,
+		models.FatigueSignalMissedReps,
+		models.FatigueSignalVolumeSpike,
+	} {
+		if !types[want] {
+			t.Errorf("falta la señal %s: %+v", want, report.Signals)
+		}
+	}
+	if report.Status != models.ReadinessFatigued || !report.DeloadRecommended || report.Score != 0 {
+		t.Errorf("se esperaba fatigued con descarga y 0 puntos, se obtuvo %s con %d", report.Status, report.Score)
+	}
+	if len(report.PerformanceDrops) != 1 || report.PerformanceDrops[0].Previous1RM != 125 || report.PerformanceDrops[0].DropPercentage != 10.4 {
+		t.Errorf("caída de rendimiento inesperada: %+v", report.PerformanceDrops)
+	}
+}
+
+func TestPerformanceDropsSimilarEffort(t *testing.T) {
+	recentFrom, _ := time.Parse("2006-01-02", "2024-06-22")
+
+	// La mejor sesión fue con una estrella menos de esfuerzo: no es comparable
+	sessions := []exerciseSession{
+		readinessSession(1, "2024-06-03", 130, 2),
+		readinessSession(1, "2024-06-10", 120, 3),
+		readinessSession(1, "2024-06-27", 118, 3),
+	}
+	if drops := performanceDrops(sessions, recentFrom); len(drops) != 0 {
+		t.Errorf("no se esperaban caídas, se obtuvo %+v", drops)
+	}
+
+	// Sin sesión en la semana reciente no se evalúa
+	sessions = []exerciseSession{
+		readinessSession(1, "2024-06-03", 130, 2),
+		readinessSession(1, "2024-06-10", 120, 2),
+		readinessSession(1, "2024-06-17", 100, 2),
+	}
+	if drops := performanceDrops(sessions, recentFrom); len(drops) != 0 {
+		t.Errorf("no se esperaban caídas sin sesión reciente, se obtuvo %+v", drops)
+	}
+}
+
+func TestReadinessRatingThresholds(t *testing.T) {
+	// Una estrella más de esfuerzo en la semana alcanza para la señal; media estrella no
+	today, _ := time.Parse("2006-01-02", "2024-06-28")
+	days := func(recentEffort int) []daySummary {
+		return []daySummary{
+			readinessDay("2024-06-03", 2, 2, 2000),
+			readinessDay("2024-06-10", 2, 2, 2000),
+			readinessDay("2024-06-17", 2, 2, 2000),
+			readinessDay("2024-06-24", recentEffort, 2, 2000),
+			readinessDay("2024-06-27", 3, 2, 2000),
+		}
+	}
+
+	if types := signalTypes(analyzeReadiness(readinessInput{Days: days(3)}, today)); !types[models.FatigueSignalEffortRising] {
+		t.Errorf("pasar de 2 a 3 estrellas de esfuerzo debería ser una señal")
+	}
+	if types := signalTypes(analyzeReadiness(readinessInput{Days: days(2)}, today)); types[models.FatigueSignalEffortRising] {
+		t.Errorf("media estrella más de esfuerzo no debería ser una señal")
+	}
+	if similarEffort(2, 3) || !similarEffort(3, 3) || !similarEffort(0, 3) {
+		t.Errorf("solo son comparables las sesiones con el mismo esfuerzo o sin esfuerzo cargado")
+	}
+}
@@ -0,0 +1,484 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/gorilla/mux"
+)
+
+// personalRecordNotificationType es el tipo de notificación que se crea al batir un récord
+const personalRecordNotificationType = "personal_record"
+
+// setSample representa el peso y las repeticiones de una serie, usado para comparar récords
+type setSample struct {
+	Weight float64
+	Reps   int
+}
+
+// estimateOneRepMax estima el 1RM promediando las fórmulas de Epley y Brzycki.
+// Para series de más de 12 repeticiones Brzycki pierde precisión y se usa solo Epley.
+func estimateOneRepMax(weight float64, reps int) float64 {
+	if weight <= 0 || reps <= 0 {
+		return 0
+	}
+	if reps == 1 {
+		return weight
+	}
+
+	epley := weight * (1 + float64(reps)/30)
+	if reps > 12 {
+		return epley
+	}
+	brzycki := weight * 36 / (37 - float64(reps))
+	return (epley + brzycki) / 2
+}
+
+// evaluatePersonalRecords compara una serie contra el historial del ejercicio y devuelve los récords que supera.
+// Un récord tiene que superar un valor anterior: sin historial (la primera serie del ejercicio), con un peso
+// nunca usado o en la primera sesión no hay nada que batir y no se genera ese récord.
+func evaluatePersonalRecords(current setSample, history []setSample, sessionVolume, bestSessionVolume float64) []models.PersonalRecord {
+	if len(history) == 0 {
+		return nil
+	}
+
+	var bestWeight, bestOneRM, bestSetVolume float64
+	bestRepsAtWeight := 0
+	for _, h := range history {
+		if h.Weight > bestWeight {
+			bestWeight = h.Weight
+		}
+		if oneRM := estimateOneRepMax(h.Weight, h.Reps); oneRM > bestOneRM {
+			bestOneRM = oneRM
+		}
+		if volume := h.Weight * float64(h.Reps); volume > bestSetVolume {
+			bestSetVolume = volume
+		}
+		if h.Weight == current.Weight && h.Reps > bestRepsAtWeight {
+			bestRepsAtWeight = h.Reps
+		}
+	}
+
+	weight := current.Weight
+	reps := current.Reps
+	var records []models.PersonalRecord
+	add := func(recordType string, value, previous float64) {
+		if previous <= 0 || value <= previous {
+			return
+		}
+		prev := previous
+		records = append(records, models.PersonalRecord{
+			RecordType:    recordType,
+			Value:         value,
+			Weight:        &weight,
+			Reps:          &reps,
+			PreviousValue: &prev,
+		})
+	}
+
+	add(models.RecordTypeMaxWeight, current.Weight, bestWeight)
+	add(models.RecordTypeMaxRepsAtWeight, float64(current.Reps), float64(bestRepsAtWeight))
+	add(models.RecordTypeEstimated1RM, estimateOneRepMax(current.Weight, current.Reps), bestOneRM)
+	add(models.RecordTypeBestSetVolume, current.Weight*float64(current.Reps), bestSetVolume)
+	add(models.RecordTypeBestSessionVolume, sessionVolume, bestSessionVolume)
+
+	return records
+}
+
+// detectPersonalRecords revisa si una serie recién creada o actualizada bate algún récord del usuario
+// y guarda los récords nuevos. Los récords previos de la misma serie se recalculan.
+func detectPersonalRecords(q dbQuerier, userID string, workoutID int) ([]models.PersonalRecord, error) {
+	var exerciseID, workoutDayID int
+	var exerciseName, setType, achievedOn string
+	var isSport bool
+	var current setSample
+	err := q.QueryRow(`
+		SELECT w.exercise_id, e.name, e.is_sport, w.set_type, w.workout_day_id, wd.date::text, `+workoutLoad+`, w.reps
+		FROM workouts w
+		JOIN exercises e ON w.exercise_id = e.id
+		JOIN workout_days wd ON w.workout_day_id = wd.id
+		WHERE w.id = $1 AND w.user_id = $2 AND w.deleted_at IS NULL
+	`, workoutID, userID).Scan(&exerciseID, &exerciseName, &isSport, &setType, &workoutDayID, &achievedOn, &current.Weight, &current.Reps)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo serie %d: %v", workoutID, err)
+	}
+
+	// Las series de calentamiento no cuentan para récords; si antes era efectiva se quitan sus récords
+	if setType == models.SetTypeWarmup {
+		if _, err := q.Exec("DELETE FROM personal_records WHERE user_id = $1 AND workout_id = $2", userID, workoutID); err != nil {
+			return nil, fmt.Errorf("error limpiando récords anteriores: %v", err)
+		}
+		return nil, nil
+	}
+
+	// Si la serie se editó, sus récords anteriores dejan de ser válidos
+	_, err = q.Exec(`
+		DELETE FROM personal_records
+		WHERE user_id = $1 AND (
+			workout_id = $2 OR
+			(record_type = $3 AND exercise_id = $4 AND workout_day_id = $5)
+		)
+	`, userID, workoutID, models.RecordTypeBestSessionVolume, exerciseID, workoutDayID)
+	if err != nil {
+		return nil, fmt.Errorf("error limpiando récords anteriores: %v", err)
+	}
+
+	// Los deportes no tienen peso ni repeticiones comparables
+	if isSport {
+		return nil, nil
+	}
+
+	// Solo cuentan las series anteriores: de una fecha previa o de la misma fecha y registradas antes
+	rows, err := q.Query(`
+		SELECT DISTINCT `+workoutLoad+`, w.reps
+		FROM workouts w
+		JOIN workout_days wd ON w.workout_day_id = wd.id
+		WHERE w.user_id = $1 AND w.exercise_id = $2 AND w.deleted_at IS NULL AND `+workingSetFilter+`
+			AND (wd.date < $4::date OR (wd.date = $4::date AND w.id < $3))
+	`, userID, exerciseID, workoutID, achievedOn)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando historial: %v", err)
+	}
+	var history []setSample
+	for rows.Next() {
+		var sample setSample
+		if err := rows.Scan(&sample.Weight, &sample.Reps); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error escaneando historial: %v", err)
+		}
+		history = append(history, sample)
+	}
+	rows.Close()
+
+	// El volumen de la sesión se compara con el de los días de fechas previas
+	var sessionVolume, bestSessionVolume float64
+	err = q.QueryRow(`
+		SELECT
+			COALESCE(SUM(`+workoutLoad+` * w.reps) FILTER (WHERE w.workout_day_id = $3), 0),
+			COALESCE((
+				SELECT MAX(day_volume) FROM (
+					SELECT SUM(`+workoutLoad+` * w.reps) AS day_volume
+					FROM workouts w
+					JOIN workout_days wd ON w.workout_day_id = wd.id
+					WHERE w.user_id = $1 AND w.exercise_id = $2 AND w.workout_day_id <> $3 AND wd.date < $4::date
+						AND w.deleted_at IS NULL AND `+workingSetFilter+`
+					GROUP BY w.workout_day_id
+				) days
+			), 0)
+		FROM workouts w
+		WHERE w.user_id = $1 AND w.exercise_id = $2 AND w.deleted_at IS NULL AND `+workingSetFilter, userID, exerciseID, workoutDayID, achievedOn).Scan(&sessionVolume, &bestSessionVolume)
+	if err != nil {
+		return nil, fmt.Errorf("error calculando volumen de sesión: %v", err)
+	}
+
+	records := evaluatePersonalRecords(current, history, sessionVolume, bestSessionVolume)
+
+	insertQuery := `
+		INSERT INTO personal_records (user_id, exercise_id, record_type, value, weight, reps, previous_value, workout_id, workout_day_id, achieved_on)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at
+	`
+	for i := range records {
+		record := &records[i]
+		record.UserID = userID
+		record.ExerciseID = exerciseID
+		record.ExerciseName = exerciseName
+		record.WorkoutID = &workoutID
+		record.WorkoutDayID = &workoutDayID
+		record.AchievedOn = achievedOn
+
+		err := q.QueryRow(
+			insertQuery,
+			userID, exerciseID, record.RecordType, record.Value, record.Weight, record.Reps,
+			record.PreviousValue, workoutID, workoutDayID, achievedOn,
+		).Scan(&record.ID, &record.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error guardando récord: %v", err)
+		}
+	}
+
+	return records, nil
+}
+
+// recordTypeLabel devuelve el nombre en español de un tipo de récord
+func recordTypeLabel(recordType string) string {
+	switch recordType {
+	case models.RecordTypeMaxWeight:
+		return "peso máximo"
+	case models.RecordTypeMaxRepsAtWeight:
+		return "repeticiones con ese peso"
+	case models.RecordTypeEstimated1RM:
+		return "1RM estimado"
+	case models.RecordTypeBestSetVolume:
+		return "volumen por serie"
+	case models.RecordTypeBestSessionVolume:
+		return "volumen por sesión"
+	}
+	return recordType
+}
+
+// notifyPersonalRecords crea una notificación con los récords batidos
+func notifyPersonalRecords(userID string, records []models.PersonalRecord) {
+	if len(records) == 0 {
+		return
+	}
+
+	// Una línea por ejercicio con todos los tipos de récord batidos
+	labelsByExercise := make(map[string][]string)
+	var exerciseOrder []string
+	recordsData := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		if _, ok := labelsByExercise[record.ExerciseName]; !ok {
+			exerciseOrder = append(exerciseOrder, record.ExerciseName)
+		}
+		labelsByExercise[record.ExerciseName] = append(labelsByExercise[record.ExerciseName], recordTypeLabel(record.RecordType))
+		recordsData = append(recordsData, map[string]interface{}{
+			"id":             record.ID,
+			"exercise_id":    record.ExerciseID,
+			"record_type":    record.RecordType,
+			"value":          record.Value,
+			"previous_value": record.PreviousValue,
+			"workout_id":     record.WorkoutID,
+		})
+	}
+
+	parts := make([]string, 0, len(exerciseOrder))
+	for _, name := range exerciseOrder {
+		parts = append(parts, fmt.Sprintf("%s (%s)", name, formatList(labelsByExercise[name])))
+	}
+	message := fmt.Sprintf("Batiste tu récord personal en %s", formatUserList(parts))
+
+	dataJSON, _ := json.Marshal(map[string]interface{}{
+		"records": recordsData,
+	})
+
+	_, err := database.DB.Exec(`
+		INSERT INTO notifications (user_id, type, title, message, data, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, userID, personalRecordNotificationType, "¡Nuevo récord personal! 🏆", message, string(dataJSON), time.Now())
+	if err != nil {
+		fmt.Printf("Error creando notificación de récord: %v\n", err)
+	}
+}
+
+// detectPersonalRecordsInTx detecta los récords de varias series dentro de la transacción que las guarda.
+// Cada serie corre en un savepoint: si falla se descartan solo sus récords y el error solo se registra,
+// porque no debe hacer fallar el guardado. Devuelve los récords por serie y todos juntos para notificarlos
+// después de confirmar la transacción.
+func detectPersonalRecordsInTx(tx *sql.Tx, userID string, workoutIDs ...int) (map[int][]models.PersonalRecord, []models.PersonalRecord) {
+	byWorkout := make(map[int][]models.PersonalRecord)
+	var all []models.PersonalRecord
+	for _, workoutID := range workoutIDs {
+		if _, err := tx.Exec("SAVEPOINT personal_records"); err != nil {
+			fmt.Printf("Error detectando récords personales: %v\n", err)
+			return byWorkout, all
+		}
+		records, err := detectPersonalRecords(tx, userID, workoutID)
+		if err != nil {
+			fmt.Printf("Error detectando récords personales: %v\n", err)
+			if _, err := tx.Exec("ROLLBACK TO SAVEPOINT personal_records"); err != nil {
+				fmt.Printf("Error descartando récords personales: %v\n", err)
+				return byWorkout, all
+			}
+			continue
+		}
+		if _, err := tx.Exec("RELEASE SAVEPOINT personal_records"); err != nil {
+			fmt.Printf("Error detectando récords personales: %v\n", err)
+			return byWorkout, all
+		}
+		if len(records) > 0 {
+			byWorkout[workoutID] = records
+			all = append(all, records...)
+		}
+	}
+	return byWorkout, all
+}
+
+// scanPersonalRecords lee filas de personal_records unidas con exercises
+func scanPersonalRecords(rows *sql.Rows, loc *time.Location) ([]models.PersonalRecord, error) {
+	records := []models.PersonalRecord{}
+	for rows.Next() {
+		var record models.PersonalRecord
+		err := rows.Scan(
+			&record.ID,
+			&record.UserID,
+			&record.ExerciseID,
+			&record.ExerciseName,
+			&record.RecordType,
+			&record.Value,
+			&record.Weight,
+			&record.Reps,
+			&record.PreviousValue,
+			&record.WorkoutID,
+			&record.WorkoutDayID,
+			&record.AchievedOn,
+			&record.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		record.CreatedAt = convertToUserTime(record.CreatedAt, loc)
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+const personalRecordColumns = `
+	pr.id, pr.user_id, pr.exercise_id, e.name, pr.record_type, pr.value, pr.weight, pr.reps,
+	pr.previous_value, pr.workout_id, pr.workout_day_id, pr.achieved_on::text, pr.created_at
+`
+
+// liveRecordFilter deja afuera los récords de series que están en la papelera
+const liveRecordFilter = `NOT EXISTS (SELECT 1 FROM workouts tw WHERE tw.id = pr.workout_id AND tw.deleted_at IS NOT NULL)`
+
+// queryCurrentRecords obtiene el mejor valor vigente por ejercicio y tipo de récord.
+// Para max_reps_at_weight se devuelve el mejor valor de cada peso.
+func queryCurrentRecords(userID string, exerciseID int, loc *time.Location) ([]models.PersonalRecord, error) {
+	query := `
+		SELECT DISTINCT ON (pr.exercise_id, pr.record_type, CASE WHEN pr.record_type = 'max_reps_at_weight' THEN pr.weight END)
+	` + personalRecordColumns + `
+		FROM personal_records pr
+		JOIN exercises e ON pr.exercise_id = e.id
+		WHERE pr.user_id = $1 AND ($2 = 0 OR pr.exercise_id = $2) AND `+liveRecordFilter+`
+		ORDER BY pr.exercise_id, pr.record_type, CASE WHEN pr.record_type = 'max_reps_at_weight' THEN pr.weight END,
+			pr.value DESC, pr.created_at DESC
+	`
+	rows, err := database.DB.Query(query, userID, exerciseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanPersonalRecords(rows, loc)
+}
+
+// queryRecordHistory obtiene todos los récords batidos, del más reciente al más antiguo
+func queryRecordHistory(userID string, exerciseID int, recordType string, loc *time.Location) ([]models.PersonalRecord, error) {
+	query := `
+		SELECT ` + personalRecordColumns + `
+		FROM personal_records pr
+		JOIN exercises e ON pr.exercise_id = e.id
+		WHERE pr.user_id = $1 AND ($2 = 0 OR pr.exercise_id = $2) AND ($3 = '' OR pr.record_type = $3) AND `+liveRecordFilter+`
+		ORDER BY pr.achieved_on DESC, pr.created_at DESC
+	`
+	rows, err := database.DB.Query(query, userID, exerciseID, recordType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanPersonalRecords(rows, loc)
+}
+
+// GetPersonalRecordsHandler obtiene los récords personales vigentes del usuario
+func GetPersonalRecordsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	exerciseID := 0
+	if exerciseIDStr := r.URL.Query().Get("exercise_id"); exerciseIDStr != "" {
+		id, err := strconv.Atoi(exerciseIDStr)
+		if err != nil || id <= 0 {
+			http.Error(w, "exercise_id inválido", http.StatusBadRequest)
+			return
+		}
+		exerciseID = id
+	}
+
+	records, err := queryCurrentRecords(userID, exerciseID, getUserLocation(r, userID))
+	if err != nil {
+		fmt.Printf("Error consultando récords personales: %v\n", err)
+		http.Error(w, "Error consultando récords personales", http.StatusInternalServerError)
+		return
+	}
+	convertRecordWeights(records, getUserWeightUnit(userID))
+
+	json.NewEncoder(w).Encode(records)
+}
+
+// GetPersonalRecordsHistoryHandler obtiene el historial de récords personales del usuario
+func GetPersonalRecordsHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	exerciseID := 0
+	if exerciseIDStr := r.URL.Query().Get("exercise_id"); exerciseIDStr != "" {
+		id, err := strconv.Atoi(exerciseIDStr)
+		if err != nil || id <= 0 {
+			http.Error(w, "exercise_id inválido", http.StatusBadRequest)
+			return
+		}
+		exerciseID = id
+	}
+
+	recordType := strings.TrimSpace(r.URL.Query().Get("record_type"))
+
+	records, err := queryRecordHistory(userID, exerciseID, recordType, getUserLocation(r, userID))
+	if err != nil {
+		fmt.Printf("Error consultando historial de récords: %v\n", err)
+		http.Error(w, "Error consultando historial de récords", http.StatusInternalServerError)
+		return
+	}
+	convertRecordWeights(records, getUserWeightUnit(userID))
+
+	json.NewEncoder(w).Encode(records)
+}
+
+// GetExerciseRecordsHandler obtiene los récords vigentes y el historial del usuario en un ejercicio
+func GetExerciseRecordsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	exerciseID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	loc := getUserLocation(r, userID)
+
+	current, err := queryCurrentRecords(userID, exerciseID, loc)
+	if err != nil {
+		fmt.Printf("Error consultando récords del ejercicio: %v\n", err)
+		http.Error(w, "Error consultando récords", http.StatusInternalServerError)
+		return
+	}
+
+	history, err := queryRecordHistory(userID, exerciseID, "", loc)
+	if err != nil {
+		fmt.Printf("Error consultando historial del ejercicio: %v\n", err)
+		http.Error(w, "Error consultando récords", http.StatusInternalServerError)
+		return
+	}
+
+	unit := getUserWeightUnit(userID)
+	convertRecordWeights(current, unit)
+	convertRecordWeights(history, unit)
+
+	json.NewEncoder(w).Encode(models.ExerciseRecords{
+		ExerciseID: exerciseID,
+		Current:    current,
+		History:    history,
+	})
+}
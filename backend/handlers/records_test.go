@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"math"
+	"testing"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+func TestEstimateOneRepMax(t *testing.T) {
+	tests := []struct {
+		name   string
+		weight float64
+		reps   int
+		want   float64
+	}{
+		{"una repetición", 100, 1, 100},
+		{"sin peso", 0, 10, 0},
+		{"cinco repeticiones", 100, 5, (100*(1+5.0/30) + 100*36/32.0) / 2},
+		{"muchas repeticiones usa Epley", 50, 20, 50 * (1 + 20.0/30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := estimateOneRepMax(tt.weight, tt.reps); math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("estimateOneRepMax(%v, %d) = %v, want %v", tt.weight, tt.reps, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluatePersonalRecords(t *testing.T) {
+	history := []setSample{{Weight: 80, Reps: 8}, {Weight: 90, Reps: 5}}
+
+	// Más repeticiones con 80 kg, sin superar el peso máximo
+	records := evaluatePersonalRecords(setSample{Weight: 80, Reps: 10}, history, 800, 1200)
+	types := make(map[string]models.PersonalRecord)
+	for _, record := range records {
+		types[record.RecordType] = record
+	}
+
+	if _, ok := types[models.RecordTypeMaxWeight]; ok {
+		t.Error("80 kg no supera el peso máximo de 90 kg")
+	}
+	if record, ok := types[models.RecordTypeMaxRepsAtWeight]; !ok || record.Value != 10 || *record.PreviousValue != 8 {
+		t.Errorf("Se esperaba récord de 10 reps con 80 kg (antes 8): %+v", record)
+	}
+	if _, ok := types[models.RecordTypeBestSetVolume]; !ok {
+		t.Error("800 kg de volumen supera los 720 anteriores")
+	}
+	if _, ok := types[models.RecordTypeBestSessionVolume]; ok {
+		t.Error("El volumen de sesión no supera el mejor anterior")
+	}
+
+	// Primera serie del ejercicio: no hay nada que batir
+	if first := evaluatePersonalRecords(setSample{Weight: 60, Reps: 10}, nil, 600, 0); len(first) != 0 {
+		t.Errorf("La primera serie no debería generar récords: %+v", first)
+	}
+
+	// Primera sesión y peso nunca usado: solo los récords con valor anterior
+	heavier := evaluatePersonalRecords(setSample{Weight: 100, Reps: 3}, history, 300, 0)
+	for _, record := range heavier {
+		if record.PreviousValue == nil {
+			t.Errorf("Récord %s sin valor anterior", record.RecordType)
+		}
+		if record.RecordType == models.RecordTypeMaxRepsAtWeight || record.RecordType == models.RecordTypeBestSessionVolume {
+			t.Errorf("Sin valor anterior no debería haber récord %s", record.RecordType)
+		}
+	}
+	if len(heavier) == 0 || heavier[0].RecordType != models.RecordTypeMaxWeight || *heavier[0].PreviousValue != 90 {
+		t.Errorf("100 kg debería batir el peso máximo de 90 kg: %+v", heavier)
+	}
+}
@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/gorilla/mux"
+)
+
+// loadRoutineExercises obtiene los ejercicios de una rutina ordenados por order_index
+func loadRoutineExercises(q dbQuerier, routineID int) ([]models.RoutineExercise, error) {
+	rows, err := q.Query(`
+		SELECT
+			re.id, re.routine_id, re.exercise_id, e.name as exercise_name,
+			re.order_index, re.sets, re.reps, re.weight, re.rest_time_seconds, re.notes,
+			re.block_index, re.block_type, re.block_rest_time_seconds,
+			re.progression_rule, re.rep_range_min, re.rep_range_max, re.weight_increment,
+			re.deload_after_misses, re.deload_percent,
+			re.created_at, re.updated_at
+		FROM routine_exercises re
+		JOIN exercises e ON re.exercise_id = e.id
+		WHERE re.routine_id = $1
+		ORDER BY re.order_index ASC, re.id ASC
+	`, routineID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exercises []models.RoutineExercise
+	for rows.Next() {
+		var exercise models.RoutineExercise
+		err := rows.Scan(
+			&exercise.ID,
+			&exercise.RoutineID,
+			&exercise.ExerciseID,
+			&exercise.ExerciseName,
+			&exercise.OrderIndex,
+			&exercise.Sets,
+			&exercise.Reps,
+			&exercise.Weight,
+			&exercise.RestTimeSeconds,
+			&exercise.Notes,
+			&exercise.BlockIndex,
+			&exercise.BlockType,
+			&exercise.BlockRestTimeSeconds,
+			&exercise.ProgressionRule,
+			&exercise.RepRangeMin,
+			&exercise.RepRangeMax,
+			&exercise.WeightIncrement,
+			&exercise.DeloadAfterMisses,
+			&exercise.DeloadPercent,
+			&exercise.CreatedAt,
+			&exercise.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		exercises = append(exercises, exercise)
+	}
+	return exercises, rows.Err()
+}
+
+// matchRoutinePlan compara los ejercicios planificados con las series registradas.
+// Si un ejercicio aparece más de una vez en la rutina, sus series se numeran de forma continua
+// (por ejemplo 1-3 en la primera aparición y 4-5 en la segunda). Las series con número mayor
+// al planificado quedan como extra del último bloque del ejercicio.
+func matchRoutinePlan(planned []models.RoutineExercise, workouts []models.Workout) ([]models.ExercisePlan, []models.Workout) {
+	plans := make([]models.ExercisePlan, 0, len(planned))
+	// exercise_id -> índices en plans, en orden
+	byExercise := make(map[int][]int)
+	// índice en plans -> primer número de serie que le corresponde
+	firstSet := make(map[int]int)
+
+	for _, exercise := range planned {
+		i := len(plans)
+		start := 1
+		if previous := byExercise[exercise.ExerciseID]; len(previous) > 0 {
+			last := previous[len(previous)-1]
+			start = firstSet[last] + plans[last].PlannedSets
+		}
+		firstSet[i] = start
+		byExercise[exercise.ExerciseID] = append(byExercise[exercise.ExerciseID], i)
+
+		plan := models.ExercisePlan{
+			RoutineExerciseID: exercise.ID,
+			ExerciseID:        exercise.ExerciseID,
+			ExerciseName:      exercise.ExerciseName,
+			OrderIndex:        exercise.OrderIndex,
+			RestTimeSeconds:   exercise.RestTimeSeconds,
+			Notes:             exercise.Notes,
+			BlockIndex:        exercise.BlockIndex,
+			BlockType:         exercise.BlockType,
+			BlockRestSeconds:  exercise.BlockRestTimeSeconds,
+			PlannedSets:       exercise.Sets,
+			Sets:              make([]models.PlannedSet, exercise.Sets),
+			ExtraSets:         []models.Workout{},
+		}
+		for s := range plan.Sets {
+			plan.Sets[s] = models.PlannedSet{
+				Set:           start + s,
+				PlannedReps:   exercise.Reps,
+				PlannedWeight: exercise.Weight,
+			}
+		}
+		plans = append(plans, plan)
+	}
+
+	unplanned := []models.Workout{}
+	for _, workout := range workouts {
+		indexes := byExercise[workout.ExerciseID]
+		if len(indexes) == 0 {
+			unplanned = append(unplanned, workout)
+			continue
+		}
+
+		matched := false
+		for _, i := range indexes {
+			offset := workout.Set - firstSet[i]
+			if offset < 0 || offset >= plans[i].PlannedSets || plans[i].Sets[offset].Done {
+				continue
+			}
+			workout := workout
+			set := &plans[i].Sets[offset]
+			set.Done = true
+			set.WorkoutID = &workout.ID
+			set.PerformedReps = &workout.Reps
+			set.PerformedWeight = &workout.Weight
+			set.PerformedSeconds = workout.Seconds
+			set.Met = workout.Reps >= set.PlannedReps && (set.PlannedWeight == nil || workout.Weight >= *set.PlannedWeight)
+			plans[i].PerformedSets++
+			matched = true
+			break
+		}
+		if !matched {
+			last := indexes[len(indexes)-1]
+			plans[last].ExtraSets = append(plans[last].ExtraSets, workout)
+			plans[last].PerformedSets++
+		}
+	}
+
+	return plans, unplanned
+}
+
+// buildWorkoutDayPlan arma el checklist de la rutina del día con las series ya registradas
+func buildWorkoutDayPlan(q dbQuerier, day *models.WorkoutDayWithExercises) (*models.WorkoutDayPlan, error) {
+	if day.WorkoutDay.RoutineID == nil {
+		return nil, nil
+	}
+
+	plan := &models.WorkoutDayPlan{
+		WorkoutDayID: day.WorkoutDay.ID,
+		RoutineID:    *day.WorkoutDay.RoutineID,
+	}
+	// Si la rutina está en la papelera el día se muestra sin plan
+	err := q.QueryRow("SELECT name FROM user_routines WHERE id = $1 AND deleted_at IS NULL", plan.RoutineID).Scan(&plan.RoutineName)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	planned, err := loadRoutineExercises(q, plan.RoutineID)
+	if err != nil {
+		return nil, err
+	}
+
+	// En las sesiones de un programa se planifica con los ajustes de la semana
+	plan.ProgramWeek, err = loadWorkoutDayProgramWeek(q, plan.WorkoutDayID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.ProgramWeek != nil {
+		applyProgramWeek(planned, *plan.ProgramWeek)
+	}
+
+	var workouts []models.Workout
+	for _, group := range day.ExerciseGroups {
+		workouts = append(workouts, group.Workouts...)
+	}
+
+	plan.Exercises, plan.UnplannedWorkouts = matchRoutinePlan(planned, workouts)
+	for _, exercise := range plan.Exercises {
+		plan.PlannedSets += exercise.PlannedSets
+		for _, set := range exercise.Sets {
+			if set.Done {
+				plan.CompletedSets++
+			}
+		}
+	}
+	if plan.PlannedSets > 0 {
+		plan.Progress = float64(plan.CompletedSets) / float64(plan.PlannedSets)
+	}
+
+	return plan, nil
+}
+
+var errDayHasOtherRoutine = errors.New("el día de entrenamiento ya tiene otra rutina iniciada")
+
+// attachRoutineToDay asocia la rutina al día de entrenamiento. Un día solo puede seguir una rutina;
+// si el día tiene el nombre por defecto, pasa a llamarse como la rutina.
+func attachRoutineToDay(q dbQuerier, workoutDayID, routineID int, routineName string) error {
+	var currentRoutineID *int
+	err := q.QueryRow("SELECT routine_id FROM workout_days WHERE id = $1 FOR UPDATE", workoutDayID).Scan(&currentRoutineID)
+	if err != nil {
+		return err
+	}
+	if currentRoutineID != nil && *currentRoutineID != routineID {
+		return errDayHasOtherRoutine
+	}
+
+	_, err = q.Exec(`
+		UPDATE workout_days
+		SET routine_id = $1,
+			name = CASE WHEN name = $2 THEN $3 ELSE name END,
+			updated_at = NOW()
+		WHERE id = $4
+	`, routineID, defaultWorkoutDayName, routineName, workoutDayID)
+	return err
+}
+
+// StartRoutineHandler inicia una rutina: crea o reutiliza el día de entrenamiento (hoy por defecto),
+// lo asocia a la rutina y devuelve las series planificadas como checklist
+func StartRoutineHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	routineID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID de rutina inválido", http.StatusBadRequest)
+		return
+	}
+
+	// El body es opcional
+	var req models.StartRoutineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	loc := getUserLocation(r, userID)
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	var routineName string
+	err = tx.QueryRow("SELECT name FROM user_routines WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL", routineID, userID).Scan(&routineName)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Rutina no encontrada", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error obteniendo rutina", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	var exerciseCount int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM routine_exercises WHERE routine_id = $1", routineID).Scan(&exerciseCount); err != nil {
+		http.Error(w, "Error obteniendo ejercicios de la rutina", http.StatusInternalServerError)
+		return
+	}
+	if exerciseCount == 0 {
+		http.Error(w, "La rutina no tiene ejercicios", http.StatusBadRequest)
+		return
+	}
+
+	workoutDayID, err := resolveWorkoutDayID(tx, userID, loc, req.Date, req.WorkoutDayID)
+	if err != nil {
+		writeWorkoutDayError(w, err)
+		return
+	}
+
+	if err := attachRoutineToDay(tx, workoutDayID, routineID, routineName); err != nil {
+		if err == errDayHasOtherRoutine {
+			http.Error(w, err.Error(), http.StatusConflict)
+		} else {
+			fmt.Printf("Error asociando rutina al día: %v\n", err)
+			http.Error(w, "Error iniciando rutina", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	day, err := loadWorkoutDayWithExercises(tx, userID, workoutDayID, loc)
+	if err != nil {
+		writeWorkoutDayError(w, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, "Error confirmando transacción", http.StatusInternalServerError)
+		return
+	}
+
+	convertWorkoutDayPlanWeights(day.Plan, getUserWeightUnit(userID))
+	json.NewEncoder(w).Encode(day.Plan)
+}
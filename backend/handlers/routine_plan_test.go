@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+func TestMatchRoutinePlan(t *testing.T) {
+	weight := 60.0
+	planned := []models.RoutineExercise{
+		{ID: 1, ExerciseID: 10, ExerciseName: "Sentadilla", Sets: 3, Reps: 8, Weight: &weight},
+		{ID: 2, ExerciseID: 20, ExerciseName: "Dominadas", Sets: 2, Reps: 6},
+		{ID: 3, ExerciseID: 10, ExerciseName: "Sentadilla", Sets: 1, Reps: 15},
+	}
+	workouts := []models.Workout{
+		{ID: 100, ExerciseID: 10, Set: 1, Weight: 60, Reps: 8},
+		{ID: 101, ExerciseID: 10, Set: 2, Weight: 57.5, Reps: 8},
+		{ID: 102, ExerciseID: 10, Set: 4, Weight: 40, Reps: 15},
+		{ID: 103, ExerciseID: 10, Set: 5, Weight: 40, Reps: 12},
+		{ID: 104, ExerciseID: 30, Set: 1, Weight: 10, Reps: 10},
+	}
+
+	plans, unplanned := matchRoutinePlan(planned, workouts)
+
+	if len(plans) != 3 {
+		t.Fatalf("cantidad de ejercicios incorrecta: %d", len(plans))
+	}
+	squat := plans[0]
+	if !squat.Sets[0].Done || !squat.Sets[0].Met {
+		t.Errorf("la serie 1 debería estar hecha y cumplida: %+v", squat.Sets[0])
+	}
+	if !squat.Sets[1].Done || squat.Sets[1].Met {
+		t.Errorf("la serie 2 debería estar hecha con menos peso: %+v", squat.Sets[1])
+	}
+	if squat.Sets[2].Done || squat.PerformedSets != 2 {
+		t.Errorf("la serie 3 no debería estar hecha: %+v", squat)
+	}
+	if plans[1].PerformedSets != 0 {
+		t.Errorf("dominadas no deberían tener series: %+v", plans[1])
+	}
+	finisher := plans[2]
+	if finisher.Sets[0].Set != 4 || !finisher.Sets[0].Done || len(finisher.ExtraSets) != 1 {
+		t.Errorf("segunda aparición mal numerada: %+v", finisher)
+	}
+	if len(unplanned) != 1 || unplanned[0].ID != 104 {
+		t.Errorf("series fuera del plan incorrectas: %+v", unplanned)
+	}
+}
@@ -27,7 +27,7 @@ func GetUserRoutinesHandler(w http.ResponseWriter, r *http.Request) {
 			COUNT(re.id) as total_exercises
 		FROM user_routines ur
 		LEFT JOIN routine_exercises re ON ur.id = re.routine_id
-		WHERE ur.user_id = $1
+		WHERE ur.user_id = $1 AND ur.deleted_at IS NULL
 		GROUP BY ur.id, ur.user_id, ur.name, ur.description, ur.is_active, ur.created_at, ur.updated_at
 		ORDER BY ur.created_at DESC
 	`
@@ -89,7 +89,7 @@ func GetUserRoutineHandler(w http.ResponseWriter, r *http.Request) {
 	routineQuery := `
 		SELECT id, user_id, name, description, is_active, created_at, updated_at
 		FROM user_routines 
-		WHERE id = $1 AND user_id = $2
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
 	`
 
 	var routine models.UserRoutine
@@ -111,54 +111,14 @@ func GetUserRoutineHandler(w http.ResponseWriter, r *http.Request) {
 	routine.Description = description
 
 	// Obtener los ejercicios de la rutina
-	exercisesQuery := `
-		SELECT 
-			re.id, re.routine_id, re.exercise_id, e.name as exercise_name,
-			re.order_index, re.sets, re.reps, re.weight, re.rest_time_seconds, re.notes,
-			re.created_at, re.updated_at
-		FROM routine_exercises re
-		JOIN exercises e ON re.exercise_id = e.id
-		WHERE re.routine_id = $1
-		ORDER BY re.order_index ASC
-	`
-
-	rows, err := database.DB.Query(exercisesQuery, routineID)
+	exercises, err := loadRoutineExercises(database.DB, routineID)
 	if err != nil {
 		fmt.Printf("Error consultando ejercicios de rutina: %v\n", err)
 		http.Error(w, "Error obteniendo ejercicios de la rutina", http.StatusInternalServerError)
 		return
 	}
-	defer rows.Close()
-
-	var exercises []models.RoutineExercise
-	for rows.Next() {
-		var exercise models.RoutineExercise
-		var notes *string
-		
-		err := rows.Scan(
-			&exercise.ID,
-			&exercise.RoutineID,
-			&exercise.ExerciseID,
-			&exercise.ExerciseName,
-			&exercise.OrderIndex,
-			&exercise.Sets,
-			&exercise.Reps,
-			&exercise.Weight,
-			&exercise.RestTimeSeconds,
-			&notes,
-			&exercise.CreatedAt,
-			&exercise.UpdatedAt,
-		)
-		if err != nil {
-			fmt.Printf("Error escaneando ejercicio: %v\n", err)
-			http.Error(w, "Error procesando ejercicio", http.StatusInternalServerError)
-			return
-		}
-
-		exercise.Notes = notes
-		exercises = append(exercises, exercise)
-	}
 
+	convertRoutineExerciseWeights(exercises, getUserWeightUnit(userID))
 	routine.Exercises = exercises
 
 	json.NewEncoder(w).Encode(routine)
@@ -180,6 +140,32 @@ func CreateUserRoutineHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Superseries y circuitos: los ejercicios de un mismo bloque comparten tipo y descanso
+	if err := validateRoutineBlocks(req.Exercises); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Los pesos planificados se guardan en kg
+	unit, err := requestWeightUnit(req.WeightUnit, getUserWeightUnit(userID))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for i := range req.Exercises {
+		exercise := &req.Exercises[i]
+		if err := validateRoutineExercisePrescription(&exercise.OrderIndex, &exercise.Sets, &exercise.Reps, exercise.Weight, &exercise.RestTimeSeconds); err != nil {
+			http.Error(w, fmt.Sprintf("ejercicio %d: %v", i+1, err), http.StatusBadRequest)
+			return
+		}
+		if err := validateProgressionConfig(&req.Exercises[i].ProgressionConfig); err != nil {
+			http.Error(w, fmt.Sprintf("ejercicio %d: %v", i+1, err), http.StatusBadRequest)
+			return
+		}
+		req.Exercises[i].Weight = weightPtrToKilograms(req.Exercises[i].Weight, unit)
+		req.Exercises[i].WeightIncrement = weightPtrToKilograms(req.Exercises[i].WeightIncrement, unit)
+	}
+
 	// Iniciar transacción
 	tx, err := database.DB.Begin()
 	if err != nil {
@@ -208,8 +194,10 @@ func CreateUserRoutineHandler(w http.ResponseWriter, r *http.Request) {
 	// Si se proporcionaron ejercicios, agregarlos
 	if len(req.Exercises) > 0 {
 		exerciseQuery := `
-			INSERT INTO routine_exercises (routine_id, exercise_id, order_index, sets, reps, weight, rest_time_seconds, notes)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			INSERT INTO routine_exercises (routine_id, exercise_id, order_index, sets, reps, weight, rest_time_seconds, notes,
+				block_index, block_type, block_rest_time_seconds,
+				progression_rule, rep_range_min, rep_range_max, weight_increment, deload_after_misses, deload_percent)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 		`
 
 		for _, exercise := range req.Exercises {
@@ -222,6 +210,15 @@ func CreateUserRoutineHandler(w http.ResponseWriter, r *http.Request) {
 				exercise.Weight,
 				exercise.RestTimeSeconds,
 				exercise.Notes,
+				exercise.BlockIndex,
+				exercise.BlockType,
+				exercise.BlockRestTimeSeconds,
+				exercise.ProgressionRule,
+				exercise.RepRangeMin,
+				exercise.RepRangeMax,
+				exercise.WeightIncrement,
+				exercise.DeloadAfterMisses,
+				exercise.DeloadPercent,
 			)
 			if err != nil {
 				fmt.Printf("Error agregando ejercicio a rutina: %v\n", err)
@@ -279,7 +276,7 @@ func UpdateUserRoutineHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Verificar que la rutina pertenece al usuario
 	var exists bool
-	err = database.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM user_routines WHERE id = $1 AND user_id = $2)", routineID, userID).Scan(&exists)
+	err = database.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM user_routines WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL)", routineID, userID).Scan(&exists)
 	if err != nil || !exists {
 		http.Error(w, "Rutina no encontrada", http.StatusNotFound)
 		return
@@ -308,7 +305,7 @@ func UpdateUserRoutineHandler(w http.ResponseWriter, r *http.Request) {
 		argIndex++
 	}
 
-	query += fmt.Sprintf(" WHERE id = $%d AND user_id = $%d", argIndex, argIndex+1)
+	query += fmt.Sprintf(" WHERE id = $%d AND user_id = $%d AND deleted_at IS NULL", argIndex, argIndex+1)
 	args = append(args, routineID, userID)
 
 	_, err = database.DB.Exec(query, args...)
@@ -321,7 +318,7 @@ func UpdateUserRoutineHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"message": "Rutina actualizada exitosamente"})
 }
 
-// DeleteUserRoutineHandler elimina una rutina
+// DeleteUserRoutineHandler manda una rutina a la papelera
 func DeleteUserRoutineHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -340,19 +337,205 @@ func DeleteUserRoutineHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Verificar que la rutina pertenece al usuario
 	var exists bool
-	err = database.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM user_routines WHERE id = $1 AND user_id = $2)", routineID, userID).Scan(&exists)
+	err = database.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM user_routines WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL)", routineID, userID).Scan(&exists)
 	if err != nil || !exists {
 		http.Error(w, "Rutina no encontrada", http.StatusNotFound)
 		return
 	}
 
-	// Eliminar la rutina (los ejercicios se eliminan automáticamente por CASCADE)
-	_, err = database.DB.Exec("DELETE FROM user_routines WHERE id = $1 AND user_id = $2", routineID, userID)
+	// Una rutina que es día de un programa no se puede eliminar: los alumnos la siguen
+	var inProgram bool
+	err = database.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM program_days WHERE routine_id = $1)", routineID).Scan(&inProgram)
+	if err != nil {
+		fmt.Printf("Error verificando programas de la rutina: %v\n", err)
+		http.Error(w, "Error eliminando rutina", http.StatusInternalServerError)
+		return
+	}
+	if inProgram {
+		http.Error(w, "La rutina forma parte de un programa; quitala del programa antes de eliminarla", http.StatusConflict)
+		return
+	}
+
+	// La rutina va a la papelera con sus ejercicios intactos; se eliminan al purgarla
+	result, err := database.DB.Exec("UPDATE user_routines SET deleted_at = NOW() WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL", routineID, userID)
 	if err != nil {
 		fmt.Printf("Error eliminando rutina: %v\n", err)
 		http.Error(w, "Error eliminando rutina", http.StatusInternalServerError)
 		return
 	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		http.Error(w, "Rutina no encontrada", http.StatusNotFound)
+		return
+	}
 
 	json.NewEncoder(w).Encode(map[string]string{"message": "Rutina eliminada exitosamente"})
 }
+
+// UpdateRoutineExerciseHandler actualiza un ejercicio de una rutina, incluidos su bloque y sus parámetros de progresión
+func UpdateRoutineExerciseHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	routineID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de rutina inválido", http.StatusBadRequest)
+		return
+	}
+	routineExerciseID, err := strconv.Atoi(vars["routine_exercise_id"])
+	if err != nil {
+		http.Error(w, "ID de ejercicio de rutina inválido", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateRoutineExerciseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	current, err := loadRoutineExercisePrescription(database.DB, userID, routineExerciseID)
+	if err == nil && current.RoutineID != routineID {
+		err = errRoutineExerciseNotFound
+	}
+	if err != nil {
+		writeRoutineExerciseError(w, err)
+		return
+	}
+
+	// Los validate: del modelo no se aplican solos: la prescripción se valida a mano como al crear
+	if err := validateRoutineExercisePrescription(req.OrderIndex, req.Sets, req.Reps, req.Weight, req.RestTimeSeconds); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// El peso y el incremento se guardan en kg
+	unit, err := requestWeightUnit(req.WeightUnit, getUserWeightUnit(userID))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Weight = weightPtrToKilograms(req.Weight, unit)
+	req.WeightIncrement = weightPtrToKilograms(req.WeightIncrement, unit)
+
+	// La configuración se valida combinada con la actual (por ejemplo el rango de repeticiones)
+	config := current.ProgressionConfig
+	if req.ProgressionRule != nil {
+		config.ProgressionRule = req.ProgressionRule
+	}
+	if req.RepRangeMin != nil {
+		config.RepRangeMin = req.RepRangeMin
+	}
+	if req.RepRangeMax != nil {
+		config.RepRangeMax = req.RepRangeMax
+	}
+	if req.WeightIncrement != nil {
+		config.WeightIncrement = req.WeightIncrement
+	}
+	if req.DeloadAfterMisses != nil {
+		config.DeloadAfterMisses = req.DeloadAfterMisses
+	}
+	if req.DeloadPercent != nil {
+		config.DeloadPercent = req.DeloadPercent
+	}
+	if err := validateProgressionConfig(&config); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Los bloques se validan con el resto de los ejercicios de la rutina
+	routineExercises, err := loadRoutineExercises(database.DB, routineID)
+	if err != nil {
+		fmt.Printf("Error obteniendo ejercicios de rutina: %v\n", err)
+		http.Error(w, "Error actualizando ejercicio de rutina", http.StatusInternalServerError)
+		return
+	}
+	blocks, err := routineBlocksAfterUpdate(routineExercises, routineExerciseID, &req)
+	if err == nil {
+		err = validateRoutineBlocks(blocks)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		fmt.Printf("Error iniciando transacción: %v\n", err)
+		http.Error(w, "Error actualizando ejercicio de rutina", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	for i, exercise := range routineExercises {
+		if sameBlockConfig(exercise, blocks[i]) {
+			continue
+		}
+		_, err := tx.Exec(`
+			UPDATE routine_exercises
+			SET block_index = $1, block_type = $2, block_rest_time_seconds = $3, updated_at = NOW()
+			WHERE id = $4
+		`, blocks[i].BlockIndex, blocks[i].BlockType, blocks[i].BlockRestTimeSeconds, exercise.ID)
+		if err != nil {
+			fmt.Printf("Error actualizando bloque de rutina: %v\n", err)
+			http.Error(w, "Error actualizando ejercicio de rutina", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Construir query de actualización dinámicamente
+	query := "UPDATE routine_exercises SET updated_at = NOW()"
+	args := []interface{}{}
+	fields := []struct {
+		column string
+		set    bool
+		value  interface{}
+	}{
+		{"order_index", req.OrderIndex != nil, req.OrderIndex},
+		{"sets", req.Sets != nil, req.Sets},
+		{"reps", req.Reps != nil, req.Reps},
+		{"weight", req.Weight != nil, req.Weight},
+		{"rest_time_seconds", req.RestTimeSeconds != nil, req.RestTimeSeconds},
+		{"notes", req.Notes != nil, req.Notes},
+		{"progression_rule", req.ProgressionRule != nil, req.ProgressionRule},
+		{"rep_range_min", req.RepRangeMin != nil, req.RepRangeMin},
+		{"rep_range_max", req.RepRangeMax != nil, req.RepRangeMax},
+		{"weight_increment", req.WeightIncrement != nil, req.WeightIncrement},
+		{"deload_after_misses", req.DeloadAfterMisses != nil, req.DeloadAfterMisses},
+		{"deload_percent", req.DeloadPercent != nil, req.DeloadPercent},
+	}
+	for _, field := range fields {
+		if field.set {
+			args = append(args, field.value)
+			query += fmt.Sprintf(", %s = $%d", field.column, len(args))
+		}
+	}
+	args = append(args, routineExerciseID)
+	query += fmt.Sprintf(" WHERE id = $%d", len(args))
+
+	if _, err := tx.Exec(query, args...); err != nil {
+		fmt.Printf("Error actualizando ejercicio de rutina: %v\n", err)
+		http.Error(w, "Error actualizando ejercicio de rutina", http.StatusInternalServerError)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		fmt.Printf("Error confirmando transacción: %v\n", err)
+		http.Error(w, "Error actualizando ejercicio de rutina", http.StatusInternalServerError)
+		return
+	}
+
+	updated, err := loadRoutineExercisePrescription(database.DB, userID, routineExerciseID)
+	if err != nil {
+		writeRoutineExerciseError(w, err)
+		return
+	}
+
+	exercises := []models.RoutineExercise{*updated}
+	convertRoutineExerciseWeights(exercises, getUserWeightUnit(userID))
+	json.NewEncoder(w).Encode(exercises[0])
+}
@@ -0,0 +1,568 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultUpcomingDays = 7
+	maxUpcomingDays     = 28
+)
+
+var (
+	errScheduleNotFound     = errors.New("rutina agendada no encontrada")
+	errScheduleExists       = errors.New("ya hay una rutina agendada ese día")
+	errScheduleRoutine      = errors.New("rutina no encontrada")
+	errInvalidWeekday       = errors.New("weekday debe estar entre 1 (lunes) y 7 (domingo)")
+	errInvalidPreferredTime = errors.New("preferred_time inválido, usar formato HH:MM")
+)
+
+// scheduleEntry es una rutina agendada con la fecha desde la que cuenta: la agenda no guarda
+// historial, así que los días anteriores a su última modificación no figuran como perdidos
+type scheduleEntry struct {
+	models.RoutineSchedule
+	ActiveFrom time.Time
+}
+
+// workoutDayActivity resume el día de entrenamiento registrado en una fecha
+type workoutDayActivity struct {
+	ID        int
+	RoutineID *int
+	Sets      int
+}
+
+// isoWeekday devuelve el día de la semana de una fecha, de 1 (lunes) a 7 (domingo)
+func isoWeekday(date time.Time) int {
+	return (int(date.Weekday())+6)%7 + 1
+}
+
+// parsePreferredTime valida una hora HH:MM; vacía significa sin hora
+func parsePreferredTime(value string) (*string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return nil, errInvalidPreferredTime
+	}
+	formatted := parsed.Format("15:04")
+	return &formatted, nil
+}
+
+// scheduledSessions arma las sesiones agendadas entre from y to (inclusive) con su estado:
+// hecha si ese día se registraron series con la rutina, perdida si el día ya pasó, pendiente hoy
+// y próxima en el futuro
+func scheduledSessions(entries []scheduleEntry, activity map[string]workoutDayActivity, from, to, today time.Time) []models.ScheduledSession {
+	sessions := []models.ScheduledSession{}
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		key := date.Format("2006-01-02")
+		weekday := isoWeekday(date)
+		day, trained := activity[key]
+
+		for _, entry := range entries {
+			if entry.Weekday != weekday || date.Before(entry.ActiveFrom) {
+				continue
+			}
+			session := models.ScheduledSession{
+				ScheduleID:    entry.ID,
+				Date:          key,
+				Weekday:       weekday,
+				RoutineID:     entry.RoutineID,
+				RoutineName:   entry.RoutineName,
+				PreferredTime: entry.PreferredTime,
+			}
+			started := trained && day.RoutineID != nil && *day.RoutineID == entry.RoutineID
+			if started {
+				dayID := day.ID
+				session.WorkoutDayID = &dayID
+			}
+
+			switch {
+			case started && day.Sets > 0:
+				session.Status = models.ScheduledSessionDone
+			case date.Before(today):
+				session.Status = models.ScheduledSessionMissed
+			case date.Equal(today):
+				session.Status = models.ScheduledSessionDue
+			default:
+				session.Status = models.ScheduledSessionUpcoming
+			}
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
+
+// buildDaySchedulePlans agrupa las sesiones por día entre from y to, marcando los días entrenados
+// sin ninguna rutina agendada
+func buildDaySchedulePlans(entries []scheduleEntry, activity map[string]workoutDayActivity, from, to, today time.Time) []models.DaySchedulePlan {
+	sessions := scheduledSessions(entries, activity, from, to, today)
+
+	days := []models.DaySchedulePlan{}
+	index := make(map[string]int)
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		key := date.Format("2006-01-02")
+		index[key] = len(days)
+		days = append(days, models.DaySchedulePlan{Date: key, Weekday: isoWeekday(date), Sessions: []models.ScheduledSession{}})
+	}
+	for _, session := range sessions {
+		day := &days[index[session.Date]]
+		day.Sessions = append(day.Sessions, session)
+	}
+
+	for i := range days {
+		activityDay, ok := activity[days[i].Date]
+		if !ok || activityDay.Sets == 0 {
+			continue
+		}
+		matched := false
+		for _, session := range days[i].Sessions {
+			if session.WorkoutDayID != nil {
+				matched = true
+			}
+		}
+		if !matched {
+			dayID := activityDay.ID
+			days[i].UnscheduledWorkoutDayID = &dayID
+		}
+	}
+	return days
+}
+
+// buildWeeklySchedulePlan compara lo agendado en la semana que empieza en weekStart con lo que se hizo
+func buildWeeklySchedulePlan(entries []scheduleEntry, activity map[string]workoutDayActivity, weekStart, today time.Time) models.WeeklySchedulePlan {
+	weekEnd := weekStart.AddDate(0, 0, 6)
+	plan := models.WeeklySchedulePlan{
+		WeekStart: weekStart.Format("2006-01-02"),
+		WeekEnd:   weekEnd.Format("2006-01-02"),
+		Days:      buildDaySchedulePlans(entries, activity, weekStart, weekEnd, today),
+	}
+
+	for _, day := range plan.Days {
+		for _, session := range day.Sessions {
+			plan.Planned++
+			switch session.Status {
+			case models.ScheduledSessionDone:
+				plan.Done++
+			case models.ScheduledSessionMissed:
+				plan.Missed++
+			}
+		}
+	}
+	if due := plan.Done + plan.Missed; due > 0 {
+		adherence := math.Round(float64(plan.Done)/float64(due)*100) / 100
+		plan.Adherence = &adherence
+	}
+	return plan
+}
+
+// loadRoutineSchedules obtiene la agenda semanal del usuario, sin las rutinas que están en la papelera
+func loadRoutineSchedules(q dbQuerier, userID string, loc *time.Location) ([]scheduleEntry, error) {
+	rows, err := q.Query(`
+		SELECT rs.id, rs.routine_id, ur.name, rs.weekday, to_char(rs.preferred_time, 'HH24:MI'), rs.created_at, rs.updated_at
+		FROM routine_schedules rs
+		JOIN user_routines ur ON ur.id = rs.routine_id
+		WHERE rs.user_id = $1 AND ur.deleted_at IS NULL
+		ORDER BY rs.weekday ASC, rs.preferred_time ASC NULLS LAST, rs.id ASC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []scheduleEntry{}
+	for rows.Next() {
+		var entry scheduleEntry
+		err := rows.Scan(&entry.ID, &entry.RoutineID, &entry.RoutineName, &entry.Weekday, &entry.PreferredTime, &entry.CreatedAt, &entry.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		entry.CreatedAt = convertToUserTime(entry.CreatedAt, loc)
+		entry.UpdatedAt = convertToUserTime(entry.UpdatedAt, loc)
+		entry.ActiveFrom = time.Date(entry.UpdatedAt.Year(), entry.UpdatedAt.Month(), entry.UpdatedAt.Day(), 0, 0, 0, 0, time.UTC)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// loadWorkoutDayActivity obtiene los días de entrenamiento entre from y to con su rutina y la cantidad de series
+func loadWorkoutDayActivity(q dbQuerier, userID, from, to string) (map[string]workoutDayActivity, error) {
+	rows, err := q.Query(`
+		SELECT wd.date::text, wd.id, wd.routine_id, COUNT(w.id)
+		FROM workout_days wd
+		LEFT JOIN workouts w ON w.workout_day_id = wd.id AND w.deleted_at IS NULL
+		WHERE wd.user_id = $1 AND wd.date BETWEEN $2 AND $3 AND wd.deleted_at IS NULL
+		GROUP BY wd.id, wd.date, wd.routine_id
+	`, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activity := make(map[string]workoutDayActivity)
+	for rows.Next() {
+		var date string
+		var day workoutDayActivity
+		if err := rows.Scan(&date, &day.ID, &day.RoutineID, &day.Sets); err != nil {
+			return nil, err
+		}
+		activity[date] = day
+	}
+	return activity, rows.Err()
+}
+
+// loadScheduleWindow carga la agenda y lo entrenado entre from y to
+func loadScheduleWindow(userID string, loc *time.Location, from, to time.Time) ([]scheduleEntry, map[string]workoutDayActivity, error) {
+	entries, err := loadRoutineSchedules(database.DB, userID, loc)
+	if err != nil {
+		return nil, nil, err
+	}
+	activity, err := loadWorkoutDayActivity(database.DB, userID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return entries, activity, nil
+}
+
+// userToday devuelve la fecha de hoy en la zona horaria del usuario
+func userToday(loc *time.Location) time.Time {
+	now := time.Now().In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// checkScheduleRoutine verifica que la rutina sea del usuario y no esté en la papelera
+func checkScheduleRoutine(userID string, routineID int) (string, error) {
+	var name string
+	err := database.DB.QueryRow("SELECT name FROM user_routines WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL",
+		routineID, userID).Scan(&name)
+	if err == sql.ErrNoRows {
+		return "", errScheduleRoutine
+	}
+	return name, err
+}
+
+// writeScheduleError responde con el código HTTP que corresponde a un error de la agenda
+func writeScheduleError(w http.ResponseWriter, err error) {
+	switch err {
+	case errScheduleNotFound, errScheduleRoutine:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errScheduleExists:
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errInvalidWeekday, errInvalidPreferredTime:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		fmt.Printf("Error en agenda de rutinas: %v\n", err)
+		http.Error(w, "Error procesando la agenda", http.StatusInternalServerError)
+	}
+}
+
+// GetRoutineSchedulesHandler obtiene la agenda semanal de rutinas del usuario
+func GetRoutineSchedulesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	entries, err := loadRoutineSchedules(database.DB, userID, getUserLocation(r, userID))
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+
+	schedules := make([]models.RoutineSchedule, len(entries))
+	for i, entry := range entries {
+		schedules[i] = entry.RoutineSchedule
+	}
+	json.NewEncoder(w).Encode(schedules)
+}
+
+// CreateRoutineScheduleHandler agenda una rutina en un día de la semana libre, con hora preferida opcional
+func CreateRoutineScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateRoutineScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if req.Weekday < 1 || req.Weekday > 7 {
+		writeScheduleError(w, errInvalidWeekday)
+		return
+	}
+	var preferredTime *string
+	if req.PreferredTime != nil {
+		var err error
+		if preferredTime, err = parsePreferredTime(*req.PreferredTime); err != nil {
+			writeScheduleError(w, err)
+			return
+		}
+	}
+
+	routineName, err := checkScheduleRoutine(userID, req.RoutineID)
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+
+	schedule := models.RoutineSchedule{
+		RoutineID:     req.RoutineID,
+		RoutineName:   routineName,
+		Weekday:       req.Weekday,
+		PreferredTime: preferredTime,
+	}
+	err = database.DB.QueryRow(`
+		INSERT INTO routine_schedules (user_id, routine_id, weekday, preferred_time)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, weekday) DO NOTHING
+		RETURNING id, created_at, updated_at
+	`, userID, req.RoutineID, req.Weekday, preferredTime).Scan(&schedule.ID, &schedule.CreatedAt, &schedule.UpdatedAt)
+	if err == sql.ErrNoRows {
+		writeScheduleError(w, errScheduleExists)
+		return
+	}
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+
+	loc := getUserLocation(r, userID)
+	schedule.CreatedAt = convertToUserTime(schedule.CreatedAt, loc)
+	schedule.UpdatedAt = convertToUserTime(schedule.UpdatedAt, loc)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(schedule)
+}
+
+// UpdateRoutineScheduleHandler cambia la rutina, el día o la hora preferida de una rutina agendada.
+// preferred_time vacío borra la hora.
+func UpdateRoutineScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	scheduleID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateRoutineScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if req.RoutineID == nil && req.Weekday == nil && req.PreferredTime == nil {
+		http.Error(w, "no hay campos para actualizar", http.StatusBadRequest)
+		return
+	}
+	if req.Weekday != nil && (*req.Weekday < 1 || *req.Weekday > 7) {
+		writeScheduleError(w, errInvalidWeekday)
+		return
+	}
+	// Se distingue "no enviar la hora" (no se toca) de "hora vacía" (se borra)
+	updateTime := req.PreferredTime != nil
+	var preferredTime *string
+	if updateTime {
+		if preferredTime, err = parsePreferredTime(*req.PreferredTime); err != nil {
+			writeScheduleError(w, err)
+			return
+		}
+	}
+	if req.RoutineID != nil {
+		if _, err := checkScheduleRoutine(userID, *req.RoutineID); err != nil {
+			writeScheduleError(w, err)
+			return
+		}
+	}
+
+	var taken bool
+	err = database.DB.QueryRow(`
+		SELECT EXISTS(
+			SELECT 1 FROM routine_schedules current
+			JOIN routine_schedules other ON other.user_id = current.user_id AND other.id <> current.id
+			WHERE current.id = $1 AND current.user_id = $2
+				AND other.weekday = COALESCE($3, current.weekday)
+		)
+	`, scheduleID, userID, req.Weekday).Scan(&taken)
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+	if taken {
+		writeScheduleError(w, errScheduleExists)
+		return
+	}
+
+	_, err = database.DB.Exec(`
+		UPDATE routine_schedules
+		SET routine_id = COALESCE($1, routine_id),
+			weekday = COALESCE($2, weekday),
+			preferred_time = CASE WHEN $3 THEN $4::time ELSE preferred_time END,
+			updated_at = NOW()
+		WHERE id = $5 AND user_id = $6
+	`, req.RoutineID, req.Weekday, updateTime, preferredTime, scheduleID, userID)
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+
+	entries, err := loadRoutineSchedules(database.DB, userID, getUserLocation(r, userID))
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+	for _, entry := range entries {
+		if entry.ID == scheduleID {
+			json.NewEncoder(w).Encode(entry.RoutineSchedule)
+			return
+		}
+	}
+	writeScheduleError(w, errScheduleNotFound)
+}
+
+// DeleteRoutineScheduleHandler quita una rutina de la agenda
+func DeleteRoutineScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	scheduleID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	result, err := database.DB.Exec("DELETE FROM routine_schedules WHERE id = $1 AND user_id = $2", scheduleID, userID)
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		writeScheduleError(w, errScheduleNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetScheduleTodayHandler devuelve las rutinas agendadas para hoy en la zona horaria del usuario,
+// con las que ya se hicieron y las pendientes
+func GetScheduleTodayHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	loc := getUserLocation(r, userID)
+	today := userToday(loc)
+	entries, activity, err := loadScheduleWindow(userID, loc, today, today)
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(buildDaySchedulePlans(entries, activity, today, today, today)[0])
+}
+
+// GetUpcomingSessionsHandler devuelve las próximas sesiones agendadas desde hoy (?days, por defecto 7),
+// sin las que ya se hicieron
+func GetUpcomingSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	days := defaultUpcomingDays
+	if daysParam := r.URL.Query().Get("days"); daysParam != "" {
+		var err error
+		days, err = strconv.Atoi(daysParam)
+		if err != nil || days < 1 || days > maxUpcomingDays {
+			http.Error(w, fmt.Sprintf("days debe estar entre 1 y %d", maxUpcomingDays), http.StatusBadRequest)
+			return
+		}
+	}
+
+	loc := getUserLocation(r, userID)
+	today := userToday(loc)
+	to := today.AddDate(0, 0, days-1)
+	entries, activity, err := loadScheduleWindow(userID, loc, today, to)
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+
+	upcoming := []models.ScheduledSession{}
+	for _, session := range scheduledSessions(entries, activity, today, to, today) {
+		if session.Status != models.ScheduledSessionDone {
+			upcoming = append(upcoming, session)
+		}
+	}
+	json.NewEncoder(w).Encode(upcoming)
+}
+
+// GetWeeklySchedulePlanHandler compara lo agendado con lo hecho en la semana de ?date (por defecto la actual),
+// marcando las sesiones perdidas. Se usa la agenda actual.
+func GetWeeklySchedulePlanHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	loc := getUserLocation(r, userID)
+	today := userToday(loc)
+	date := today
+	if dateParam := r.URL.Query().Get("date"); dateParam != "" {
+		parsed, err := time.Parse("2006-01-02", dateParam)
+		if err != nil {
+			http.Error(w, errInvalidWorkoutDate.Error(), http.StatusBadRequest)
+			return
+		}
+		date = parsed
+	}
+
+	weekStart := periodStart(date, "week")
+	entries, activity, err := loadScheduleWindow(userID, loc, weekStart, weekStart.AddDate(0, 0, 6))
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(buildWeeklySchedulePlan(entries, activity, weekStart, today))
+}
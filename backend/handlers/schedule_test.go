@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+func scheduleDate(value string) time.Time {
+	date, _ := time.Parse("2006-01-02", value)
+	return date
+}
+
+func TestBuildWeeklySchedulePlan(t *testing.T) {
+	morning := "07:30"
+	activeFrom := scheduleDate("2024-05-01")
+	entries := []scheduleEntry{
+		{RoutineSchedule: models.RoutineSchedule{ID: 1, RoutineID: 10, RoutineName: "Full body A", Weekday: 1, PreferredTime: &morning}, ActiveFrom: activeFrom},
+		{RoutineSchedule: models.RoutineSchedule{ID: 2, RoutineID: 20, RoutineName: "Full body B", Weekday: 3}, ActiveFrom: activeFrom},
+		{RoutineSchedule: models.RoutineSchedule{ID: 3, RoutineID: 10, RoutineName: "Full body A", Weekday: 5}, ActiveFrom: activeFrom},
+		// Agendada o cambiada de día después de esta semana: no cuenta como perdida
+		{RoutineSchedule: models.RoutineSchedule{ID: 4, RoutineID: 30, RoutineName: "Movilidad", Weekday: 2}, ActiveFrom: scheduleDate("2024-06-01")},
+	}
+	routineA, routineB := 10, 20
+	activity := map[string]workoutDayActivity{
+		"2024-05-13": {ID: 100, RoutineID: &routineA, Sets: 12}, // Lunes: hecha
+		"2024-05-14": {ID: 101, Sets: 5},                        // Martes: entrenamiento libre
+		"2024-05-15": {ID: 102, RoutineID: &routineB, Sets: 0},  // Miércoles: iniciada sin series
+	}
+
+	// Semana del lunes 13 de mayo de 2024, hoy es jueves 16
+	plan := buildWeeklySchedulePlan(entries, activity, scheduleDate("2024-05-13"), scheduleDate("2024-05-16"))
+
+	if plan.WeekStart != "2024-05-13" || plan.WeekEnd != "2024-05-19" || len(plan.Days) != 7 {
+		t.Fatalf("semana incorrecta: %s a %s con %d días", plan.WeekStart, plan.WeekEnd, len(plan.Days))
+	}
+	if plan.Planned != 3 || plan.Done != 1 || plan.Missed != 1 {
+		t.Errorf("totales incorrectos: %d planificadas, %d hechas, %d perdidas", plan.Planned, plan.Done, plan.Missed)
+	}
+	if plan.Adherence == nil || *plan.Adherence != 0.5 {
+		t.Errorf("adherencia incorrecta: %v", plan.Adherence)
+	}
+
+	monday := plan.Days[0].Sessions[0]
+	if monday.Status != models.ScheduledSessionDone || monday.WorkoutDayID == nil || *monday.WorkoutDayID != 100 || *monday.PreferredTime != "07:30" {
+		t.Errorf("el lunes debería estar hecho: %+v", monday)
+	}
+	if len(plan.Days[1].Sessions) != 0 || plan.Days[1].UnscheduledWorkoutDayID == nil || *plan.Days[1].UnscheduledWorkoutDayID != 101 {
+		t.Errorf("el martes debería ser un entrenamiento fuera de la agenda: %+v", plan.Days[1])
+	}
+	wednesday := plan.Days[2].Sessions[0]
+	if wednesday.Status != models.ScheduledSessionMissed || wednesday.WorkoutDayID == nil {
+		t.Errorf("el miércoles se inició sin series y debería estar perdido: %+v", wednesday)
+	}
+	if friday := plan.Days[4].Sessions[0]; friday.Status != models.ScheduledSessionUpcoming {
+		t.Errorf("el viernes debería estar próximo: %+v", friday)
+	}
+}
+
+func TestBuildWeeklySchedulePlanBeforeEdit(t *testing.T) {
+	// Pasada del miércoles al lunes el 1 de junio: las semanas anteriores no la aplican
+	entries := []scheduleEntry{
+		{RoutineSchedule: models.RoutineSchedule{ID: 1, RoutineID: 10, Weekday: 1}, ActiveFrom: scheduleDate("2024-06-01")},
+	}
+
+	plan := buildWeeklySchedulePlan(entries, nil, scheduleDate("2024-05-13"), scheduleDate("2024-06-05"))
+	if plan.Planned != 0 || plan.Missed != 0 || plan.Adherence != nil {
+		t.Errorf("una semana previa al cambio no debería tener sesiones: %+v", plan)
+	}
+
+	plan = buildWeeklySchedulePlan(entries, nil, scheduleDate("2024-06-03"), scheduleDate("2024-06-05"))
+	if plan.Planned != 1 || plan.Missed != 1 {
+		t.Errorf("el lunes posterior al cambio debería estar perdido: %+v", plan)
+	}
+}
+
+func TestScheduledSessionsDueToday(t *testing.T) {
+	entries := []scheduleEntry{
+		{RoutineSchedule: models.RoutineSchedule{ID: 1, RoutineID: 10, Weekday: 4}, ActiveFrom: scheduleDate("2024-05-16")},
+	}
+	today := scheduleDate("2024-05-16")
+
+	sessions := scheduledSessions(entries, nil, today, today.AddDate(0, 0, 13), today)
+	if len(sessions) != 2 || sessions[0].Status != models.ScheduledSessionDue || sessions[1].Date != "2024-05-23" {
+		t.Errorf("sesiones incorrectas: %+v", sessions)
+	}
+}
+
+func TestParsePreferredTime(t *testing.T) {
+	if value, err := parsePreferredTime("7:05"); err != nil || *value != "07:05" {
+		t.Errorf("hora normalizada incorrecta: %v, %v", value, err)
+	}
+	if value, err := parsePreferredTime(" "); err != nil || value != nil {
+		t.Errorf("una hora vacía debería borrar la hora: %v, %v", value, err)
+	}
+	if _, err := parsePreferredTime("25:00"); err != errInvalidPreferredTime {
+		t.Errorf("se esperaba error para una hora inválida, se obtuvo %v", err)
+	}
+}
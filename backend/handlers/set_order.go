@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+// Las series de un ejercicio en un día se numeran 1..n sin huecos ni repetidos
+
+var errInvalidSetOrder = errors.New("workout_ids debe incluir exactamente una vez cada serie del ejercicio en el día")
+
+// placeSet devuelve el nuevo orden de las series con movedID en la posición indicada
+// (1 es la primera; si excede la cantidad de series queda última). Las demás conservan su orden.
+func placeSet(others []int, movedID, position int) []int {
+	index := position - 1
+	if index < 0 {
+		index = 0
+	}
+	if index > len(others) {
+		index = len(others)
+	}
+	order := make([]int, 0, len(others)+1)
+	order = append(order, others[:index]...)
+	order = append(order, movedID)
+	return append(order, others[index:]...)
+}
+
+// validateSetOrder verifica que requested sea una permutación de las series actuales
+func validateSetOrder(current, requested []int) error {
+	if len(current) != len(requested) {
+		return errInvalidSetOrder
+	}
+	pending := make(map[int]bool, len(current))
+	for _, id := range current {
+		pending[id] = true
+	}
+	for _, id := range requested {
+		if !pending[id] {
+			return errInvalidSetOrder
+		}
+		delete(pending, id)
+	}
+	return nil
+}
+
+// loadSetOrder bloquea y devuelve, en orden, las series del ejercicio en el día, sin excludeID
+func loadSetOrder(q dbQuerier, userID string, workoutDayID, exerciseID, excludeID int) ([]int, error) {
+	rows, err := q.Query(`
+		SELECT id FROM workouts
+		WHERE user_id = $1 AND workout_day_id = $2 AND exercise_id = $3 AND deleted_at IS NULL AND id <> $4
+		ORDER BY set ASC, created_at ASC, id ASC
+		FOR UPDATE
+	`, userID, workoutDayID, exerciseID, excludeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// writeSetOrder numera las series 1..n en el orden de ids
+func writeSetOrder(q dbQuerier, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	int64IDs := make([]int64, len(ids))
+	for i, id := range ids {
+		int64IDs[i] = int64(id)
+	}
+	_, err := q.Exec(`
+		UPDATE workouts w SET set = o.position
+		FROM unnest($1::bigint[]) WITH ORDINALITY AS o(id, position)
+		WHERE w.id = o.id AND w.set <> o.position
+	`, pq.Array(int64IDs))
+	return err
+}
+
+// compactSetNumbers renumera las series del ejercicio en el día para cerrar los huecos
+func compactSetNumbers(q dbQuerier, userID string, workoutDayID, exerciseID int) error {
+	ids, err := loadSetOrder(q, userID, workoutDayID, exerciseID, 0)
+	if err != nil {
+		return err
+	}
+	return writeSetOrder(q, ids)
+}
+
+// placeWorkoutSet ubica la serie en la posición indicada dentro de su ejercicio y día,
+// corriendo las demás. Devuelve el número de serie con el que queda.
+func placeWorkoutSet(q dbQuerier, userID string, workoutID, workoutDayID, exerciseID, position int) (int, error) {
+	others, err := loadSetOrder(q, userID, workoutDayID, exerciseID, workoutID)
+	if err != nil {
+		return 0, err
+	}
+	order := placeSet(others, workoutID, position)
+	if err := writeSetOrder(q, order); err != nil {
+		return 0, err
+	}
+	for i, id := range order {
+		if id == workoutID {
+			return i + 1, nil
+		}
+	}
+	return len(order), nil
+}
+
+// newSetPlacement decide el número de una serie nueva pedida como requested cuando el ejercicio
+// ya tiene existing series en el día: ocupa esa posición corriendo las siguientes, o queda última
+// si dejaría un hueco.
+func newSetPlacement(existing, requested int) int {
+	if requested > existing+1 {
+		return existing + 1
+	}
+	return requested
+}
+
+// ReorderWorkoutSetsHandler reescribe en una transacción los números de serie de un ejercicio
+// en un día según el orden de workout_ids
+func ReorderWorkoutSetsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	dayID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+	exerciseID, err := strconv.Atoi(vars["exercise_id"])
+	if err != nil {
+		http.Error(w, "ID de ejercicio inválido", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.ReorderSetsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM workout_days WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL)", dayID, userID).Scan(&exists)
+	if err != nil {
+		http.Error(w, "Error verificando día de entrenamiento", http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		http.Error(w, "Día de entrenamiento no encontrado", http.StatusNotFound)
+		return
+	}
+
+	current, err := loadSetOrder(tx, userID, dayID, exerciseID, 0)
+	if err != nil {
+		fmt.Printf("Error consultando series del ejercicio: %v\n", err)
+		http.Error(w, "Error reordenando series", http.StatusInternalServerError)
+		return
+	}
+	if len(current) == 0 {
+		http.Error(w, "El ejercicio no tiene series en este día", http.StatusNotFound)
+		return
+	}
+	if err := validateSetOrder(current, req.WorkoutIDs); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := writeSetOrder(tx, req.WorkoutIDs); err != nil {
+		fmt.Printf("Error reordenando series: %v\n", err)
+		http.Error(w, "Error reordenando series", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, "Error confirmando transacción", http.StatusInternalServerError)
+		return
+	}
+
+	day, err := loadWorkoutDayWithExercises(database.DB, userID, dayID, getUserLocation(r, userID))
+	if err != nil {
+		writeWorkoutDayError(w, err)
+		return
+	}
+	convertWorkoutDayWeights(day, getUserWeightUnit(userID))
+	json.NewEncoder(w).Encode(day)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlaceSet(t *testing.T) {
+	others := []int{10, 11, 12}
+
+	tests := []struct {
+		name     string
+		position int
+		want     []int
+	}{
+		{"al principio", 1, []int{7, 10, 11, 12}},
+		{"en el medio corre las siguientes", 2, []int{10, 7, 11, 12}},
+		{"al final", 4, []int{10, 11, 12, 7}},
+		{"más allá del final queda última", 20, []int{10, 11, 12, 7}},
+		{"posición inválida queda primera", 0, []int{7, 10, 11, 12}},
+	}
+
+	for _, tt := range tests {
+		if got := placeSet(others, 7, tt.position); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: %v, se esperaba %v", tt.name, got, tt.want)
+		}
+	}
+	if !reflect.DeepEqual(others, []int{10, 11, 12}) {
+		t.Errorf("placeSet no debería modificar others: %v", others)
+	}
+}
+
+func TestValidateSetOrder(t *testing.T) {
+	current := []int{1, 2, 3}
+
+	tests := []struct {
+		name      string
+		requested []int
+		valid     bool
+	}{
+		{"permutación", []int{3, 1, 2}, true},
+		{"falta una serie", []int{3, 1}, false},
+		{"serie repetida", []int{1, 1, 2}, false},
+		{"serie de otro ejercicio", []int{1, 2, 4}, false},
+	}
+
+	for _, tt := range tests {
+		if err := validateSetOrder(current, tt.requested); (err == nil) != tt.valid {
+			t.Errorf("%s: error inesperado %v", tt.name, err)
+		}
+	}
+}
+
+func TestNewSetPlacement(t *testing.T) {
+	tests := []struct {
+		name       string
+		existing   int
+		requested  int
+		wantNumber int
+	}{
+		{"primera serie", 0, 1, 1},
+		{"primera serie pedida como la 3 queda primera", 0, 3, 1},
+		{"serie existente se inserta corriendo las siguientes", 1, 1, 1},
+		{"posición intermedia", 3, 2, 2},
+		{"siguiente serie", 2, 3, 3},
+		{"serie que dejaría un hueco queda última", 2, 5, 3},
+	}
+
+	for _, tt := range tests {
+		if number := newSetPlacement(tt.existing, tt.requested); number != tt.wantNumber {
+			t.Errorf("%s: número %d, se esperaba %d", tt.name, number, tt.wantNumber)
+		}
+	}
+}
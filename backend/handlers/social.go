@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
 	"github.com/gorilla/mux"
 )
 
@@ -22,17 +23,28 @@ type SocialWorkout struct {
 	TotalExercises int      `json:"total_exercises"`
 	TotalSeries   int       `json:"total_series"`
 	Exercises     []SocialExercise `json:"exercises"`
+	WeightUnit    string    `json:"weight_unit"` // Unidad de quien consulta
+	TotalDistanceMeters  float64 `json:"total_distance_meters"`  // Distancia de deportes y cardio
+	TotalDurationSeconds int     `json:"total_duration_seconds"` // Duración de deportes y cardio
 	KudosCount    int       `json:"kudos_count"`
 	HasKudos      bool      `json:"has_kudos"`
 }
 
 // SocialExercise representa un ejercicio en la vista social
 type SocialExercise struct {
-	ExerciseName string  `json:"exercise_name"`
-	Weight       float64 `json:"weight"`
-	Reps         int     `json:"reps"`
-	Seconds      *int    `json:"seconds"`
-	Set          int     `json:"set"`
+	ExerciseName string   `json:"exercise_name"`
+	Weight       float64  `json:"weight"`
+	Reps         int      `json:"reps"`
+	Seconds      *int     `json:"seconds"`
+	Set          int      `json:"set"`
+	SetType      string   `json:"set_type"`
+	RPE          *float64 `json:"rpe"`
+	RIR          *int     `json:"rir"`
+	BlockID      *int     `json:"block_id"` // Series con el mismo block_id se hicieron como superserie o circuito
+	IsSport      bool     `json:"is_sport"`
+	models.CardioMetrics
+	PaceSecondsPerKm *float64 `json:"pace_seconds_per_km,omitempty"`
+	SpeedKmh         *float64 `json:"speed_kmh,omitempty"`
 }
 
 // GetSocialWorkoutsHandler obtiene entrenamientos sociales de todos los usuarios
@@ -46,6 +58,10 @@ func GetSocialWorkoutsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Las fechas y los pesos se muestran en la zona horaria y la unidad de quien consulta
+	loc := getUserLocation(r, userID)
+	unit := getUserWeightUnit(userID)
+
 	// Por ahora, asumir que la funcionalidad social está habilitada para todos
 	// En el futuro, esto se verificará contra la tabla user_settings
 
@@ -85,8 +101,18 @@ func GetSocialWorkoutsHandler(w http.ResponseWriter, r *http.Request) {
 						'weight', w.weight,
 						'reps', w.reps,
 						'seconds', w.seconds,
-						'set', w.set
-					) ORDER BY w.set
+						'set', w.set,
+						'set_type', w.set_type,
+						'rpe', w.rpe,
+						'rir', w.rir,
+						'block_id', w.block_id,
+						'is_sport', e.is_sport,
+						'distance_meters', w.distance_meters,
+						'elevation_gain_meters', w.elevation_gain_meters,
+						'avg_heart_rate', w.avg_heart_rate,
+						'max_heart_rate', w.max_heart_rate,
+						'calories', w.calories
+					) ORDER BY w.created_at, w.id
 				) FILTER (WHERE w.id IS NOT NULL),
 				'[]'::json
 			) as exercises,
@@ -94,9 +120,9 @@ func GetSocialWorkoutsHandler(w http.ResponseWriter, r *http.Request) {
 			EXISTS(SELECT 1 FROM kudos WHERE user_id = $3 AND workout_day_id = wd.id) as has_kudos
 		FROM workout_days wd
 		LEFT JOIN user_profiles up ON wd.user_id = up.user_id
-		LEFT JOIN workouts w ON wd.id = w.workout_day_id
+		LEFT JOIN workouts w ON wd.id = w.workout_day_id AND w.deleted_at IS NULL
 		LEFT JOIN exercises e ON w.exercise_id = e.id
-		WHERE 1=1
+		WHERE wd.deleted_at IS NULL
 		GROUP BY wd.id, wd.user_id, up.name, up.avatar_url, wd.date, wd.created_at
 		ORDER BY wd.date DESC, wd.created_at DESC
 		LIMIT $1 OFFSET $2
@@ -140,11 +166,7 @@ func GetSocialWorkoutsHandler(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		// Convertir fecha a zona horaria de Argentina
-		loc, err := time.LoadLocation("America/Argentina/Buenos_Aires")
-		if err != nil {
-			loc = time.FixedZone("UTC-3", -3*60*60)
-		}
+		// Convertir fecha a zona horaria del usuario
 		workout.WorkoutDate = workoutDate.In(loc).Format(time.RFC3339)
 		workout.CreatedAt = createdAt.In(loc).Format(time.RFC3339)
 
@@ -153,6 +175,20 @@ func GetSocialWorkoutsHandler(w http.ResponseWriter, r *http.Request) {
 			fmt.Printf("Error parseando ejercicios: %v\n", err)
 			continue
 		}
+		for i := range workout.Exercises {
+			exercise := &workout.Exercises[i]
+			exercise.Weight = fromKilograms(exercise.Weight, unit)
+			exercise.PaceSecondsPerKm, exercise.SpeedKmh = cardioPace(exercise.DistanceMeters, exercise.Seconds)
+			if exercise.IsSport {
+				if exercise.DistanceMeters != nil {
+					workout.TotalDistanceMeters += *exercise.DistanceMeters
+				}
+				if exercise.Seconds != nil {
+					workout.TotalDurationSeconds += *exercise.Seconds
+				}
+			}
+		}
+		workout.WeightUnit = unit
 
 		// Los kudos ahora vienen de la base de datos
 
@@ -193,7 +229,7 @@ func GiveKudosHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Verificar si el workout existe
 	var exists bool
-	err = database.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM workout_days WHERE id = $1)", workoutID).Scan(&exists)
+	err = database.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM workout_days WHERE id = $1 AND deleted_at IS NULL)", workoutID).Scan(&exists)
 	if err != nil {
 		fmt.Printf("Error verificando existencia del workout: %v\n", err)
 		http.Error(w, "Error interno del servidor", http.StatusInternalServerError)
@@ -357,6 +393,7 @@ func DebugHandler(w http.ResponseWriter, r *http.Request) {
 	workoutDaysRows, err := database.DB.Query(`
 		SELECT id, user_id, date, name, effort, mood, created_at 
 		FROM workout_days 
+		WHERE deleted_at IS NULL
 		ORDER BY created_at DESC
 		LIMIT 5
 	`)
@@ -432,7 +469,7 @@ func createKudosNotification(workoutDayID int, fromUserID, toUserID string) {
 
 			// Verificar que existe el workout day
 	var workoutDate time.Time
-	err = database.DB.QueryRow("SELECT created_at FROM workout_days WHERE id = $1 AND user_id = $2", workoutDayID, toUserID).Scan(&workoutDate)
+	err = database.DB.QueryRow("SELECT created_at FROM workout_days WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL", workoutDayID, toUserID).Scan(&workoutDate)
 	if err != nil {
 		fmt.Printf("Error obteniendo fecha del workout: %v\n", err)
 		return
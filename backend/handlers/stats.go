@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+)
+
+// Cantidad de ejercicios más entrenados que se devuelven por defecto y como máximo
+const (
+	defaultTopExercises = 5
+	maxTopExercises     = 50
+)
+
+// maxStatsWeeks es la cantidad máxima de semanas del detalle semanal: en rangos más largos
+// se devuelven las últimas (los totales y promedios cubren todo el rango)
+const maxStatsWeeks = 104
+
+// daySummary resume un día con al menos una serie registrada
+type daySummary struct {
+	ID     int
+	Date   time.Time
+	Effort int
+	Mood   int
+	Sets   int
+	Volume float64
+	// Distancia y duración de deportes y cardio
+	Distance float64
+	Duration int
+}
+
+// weeklyStreaks calcula la racha actual y la más larga de semanas consecutivas con al menos un entrenamiento.
+// La semana de referencia todavía en curso no corta la racha actual si aún no se entrenó.
+func weeklyStreaks(trainedWeeks map[string]bool, reference time.Time) (int, int) {
+	if len(trainedWeeks) == 0 {
+		return 0, 0
+	}
+
+	weeks := make([]time.Time, 0, len(trainedWeeks))
+	for key := range trainedWeeks {
+		week, err := time.Parse("2006-01-02", key)
+		if err != nil {
+			continue
+		}
+		weeks = append(weeks, week)
+	}
+
+	longest := 0
+	for _, week := range weeks {
+		// Solo se cuenta desde el inicio de cada racha
+		if trainedWeeks[week.AddDate(0, 0, -7).Format("2006-01-02")] {
+			continue
+		}
+		length := 0
+		for w := week; trainedWeeks[w.Format("2006-01-02")]; w = w.AddDate(0, 0, 7) {
+			length++
+		}
+		if length > longest {
+			longest = length
+		}
+	}
+
+	current := 0
+	week := periodStart(reference, "week")
+	if !trainedWeeks[week.Format("2006-01-02")] {
+		week = week.AddDate(0, 0, -7)
+	}
+	for ; trainedWeeks[week.Format("2006-01-02")]; week = week.AddDate(0, 0, -7) {
+		current++
+	}
+
+	return current, longest
+}
+
+// summarizeDays calcula totales, promedios, semanas y rachas a partir de los días entrenados
+// del rango [from, to]; from puede ser cero para usar el primer día entrenado. El detalle
+// semanal tiene como máximo las últimas maxStatsWeeks semanas del rango.
+func summarizeDays(days []daySummary, from, to time.Time) models.UserStats {
+	stats := models.UserStats{
+		Weekly:       []models.WeeklyStats{},
+		TopExercises: []models.ExerciseStats{},
+		MuscleGroups: []models.MuscleGroupStats{},
+		Sports:       []models.SportStats{},
+	}
+
+	var effortSum, moodSum, effortDays, moodDays int
+	trainedWeeks := make(map[string]bool)
+	for _, day := range days {
+		stats.TotalSessions++
+		stats.TotalWorkouts += day.Sets
+		stats.TotalVolume += day.Volume
+		stats.TotalDistance += day.Distance
+		stats.TotalDuration += day.Duration
+		// 0 significa que el usuario no cargó el valor
+		if day.Effort > 0 {
+			effortSum += day.Effort
+			effortDays++
+		}
+		if day.Mood > 0 {
+			moodSum += day.Mood
+			moodDays++
+		}
+		trainedWeeks[periodStart(day.Date, "week").Format("2006-01-02")] = true
+	}
+	stats.WorkoutDays = stats.TotalSessions
+	if effortDays > 0 {
+		stats.AvgEffort = float64(effortSum) / float64(effortDays)
+	}
+	if moodDays > 0 {
+		stats.AvgMood = float64(moodSum) / float64(moodDays)
+	}
+
+	if from.IsZero() {
+		if len(days) == 0 {
+			return stats
+		}
+		from = days[0].Date
+	}
+
+	weeks := progressBucketCount("week", from, to)
+	first := periodStart(from, "week")
+	if weeks > maxStatsWeeks {
+		first = periodStart(to, "week").AddDate(0, 0, -7*(maxStatsWeeks-1))
+	}
+	index := make(map[string]int)
+	for start := first; !start.After(to); start = nextPeriod(start, "week") {
+		key := start.Format("2006-01-02")
+		index[key] = len(stats.Weekly)
+		stats.Weekly = append(stats.Weekly, models.WeeklyStats{WeekStart: key})
+	}
+	for _, day := range days {
+		i, ok := index[periodStart(day.Date, "week").Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		stats.Weekly[i].Sessions++
+		stats.Weekly[i].Sets += day.Sets
+		stats.Weekly[i].Volume += day.Volume
+		stats.Weekly[i].DistanceMeters += day.Distance
+		stats.Weekly[i].DurationSeconds += day.Duration
+	}
+	stats.AvgSessionsPerWeek = float64(stats.TotalSessions) / float64(weeks)
+
+	stats.CurrentStreakWeeks, stats.LongestStreakWeeks = weeklyStreaks(trainedWeeks, to)
+	return stats
+}
+
+// loadDaySummaries obtiene los días con al menos una serie del usuario entre from y to (vacíos = sin límite),
+// ordenados por fecha. El volumen no incluye deportes ni series de calentamiento; la distancia
+// y la duración solo cuentan deportes.
+func loadDaySummaries(userID, from, to string) ([]daySummary, error) {
+	query, args := appendDateRangeFilter(`
+		SELECT wd.id, wd.date::text, wd.effort, wd.mood, COUNT(w.id),
+			COALESCE(SUM(`+workoutLoad+` * w.reps) FILTER (WHERE NOT COALESCE(e.is_sport, false) AND `+workingSetFilter+`), 0),
+			COALESCE(SUM(w.distance_meters) FILTER (WHERE e.is_sport), 0),
+			COALESCE(SUM(w.seconds) FILTER (WHERE e.is_sport), 0)
+		FROM workout_days wd
+		JOIN workouts w ON w.workout_day_id = wd.id
+		JOIN exercises e ON w.exercise_id = e.id
+		WHERE wd.user_id = $1 AND w.deleted_at IS NULL`, "wd.date", from, to, []interface{}{userID})
+	query += " GROUP BY wd.id, wd.date, wd.effort, wd.mood ORDER BY wd.date ASC"
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []daySummary
+	for rows.Next() {
+		var day daySummary
+		var date string
+		if err := rows.Scan(&day.ID, &date, &day.Effort, &day.Mood, &day.Sets, &day.Volume, &day.Distance, &day.Duration); err != nil {
+			return nil, err
+		}
+		if day.Date, err = time.Parse("2006-01-02", date); err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
+
+// GetUserStatsHandler obtiene estadísticas de entrenamiento del usuario actual,
+// opcionalmente filtradas por rango de fechas (from, to)
+func GetUserStatsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	top := defaultTopExercises
+	if topStr := r.URL.Query().Get("top"); topStr != "" {
+		top, err = strconv.Atoi(topStr)
+		if err != nil || top < 1 || top > maxTopExercises {
+			http.Error(w, fmt.Sprintf("top debe estar entre 1 y %d", maxTopExercises), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Sin "to" se toma hasta hoy en la zona horaria del usuario
+	if to == "" {
+		to = time.Now().In(getUserLocation(r, userID)).Format("2006-01-02")
+	}
+	toDate, _ := time.Parse("2006-01-02", to)
+	var fromDate time.Time
+	if from != "" {
+		fromDate, _ = time.Parse("2006-01-02", from)
+		if fromDate.After(toDate) {
+			http.Error(w, errInvalidDateRange.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	days, err := loadDaySummaries(userID, from, to)
+	if err != nil {
+		fmt.Printf("Error consultando estadísticas: %v\n", err)
+		http.Error(w, "Error obteniendo estadísticas", http.StatusInternalServerError)
+		return
+	}
+
+	stats := summarizeDays(days, fromDate, toDate)
+	stats.From = from
+	if from == "" && len(days) > 0 {
+		stats.From = days[0].Date.Format("2006-01-02")
+	}
+	stats.To = to
+
+	// Ejercicios más entrenados
+	query, args := appendDateRangeFilter(`
+		SELECT e.id, e.name, COUNT(w.id), COUNT(DISTINCT w.workout_day_id),
+			COALESCE(SUM(`+workoutLoad+` * w.reps) FILTER (WHERE NOT COALESCE(e.is_sport, false) AND `+workingSetFilter+`), 0)
+		FROM workouts w
+		JOIN workout_days wd ON w.workout_day_id = wd.id
+		JOIN exercises e ON w.exercise_id = e.id
+		WHERE w.user_id = $1 AND w.deleted_at IS NULL`, "wd.date", from, to, []interface{}{userID})
+	args = append(args, top)
+	query += fmt.Sprintf(" GROUP BY e.id, e.name ORDER BY COUNT(w.id) DESC, e.name ASC LIMIT $%d", len(args))
+
+	exerciseRows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Error consultando ejercicios más entrenados: %v\n", err)
+		http.Error(w, "Error obteniendo estadísticas", http.StatusInternalServerError)
+		return
+	}
+	defer exerciseRows.Close()
+
+	for exerciseRows.Next() {
+		var exercise models.ExerciseStats
+		if err := exerciseRows.Scan(&exercise.ExerciseID, &exercise.ExerciseName, &exercise.Sets, &exercise.Sessions, &exercise.Volume); err != nil {
+			fmt.Printf("Error escaneando ejercicio: %v\n", err)
+			continue
+		}
+		stats.TopExercises = append(stats.TopExercises, exercise)
+	}
+
+	// Series efectivas (sin calentamiento) por grupo muscular según exercise_muscle_groups
+	query, args = appendDateRangeFilter(`
+		SELECT mg.id, mg.name,
+			COUNT(*) FILTER (WHERE emg.role = 'primary'),
+			COUNT(*) FILTER (WHERE emg.role = 'secondary')
+		FROM workouts w
+		JOIN workout_days wd ON w.workout_day_id = wd.id
+		JOIN exercise_muscle_groups emg ON emg.exercise_id = w.exercise_id
+		JOIN muscle_groups mg ON emg.muscle_group_id = mg.id
+		WHERE w.user_id = $1 AND w.deleted_at IS NULL AND `+workingSetFilter, "wd.date", from, to, []interface{}{userID})
+	query += " GROUP BY mg.id, mg.name ORDER BY COUNT(*) DESC, mg.name ASC"
+
+	muscleRows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Error consultando grupos musculares: %v\n", err)
+		http.Error(w, "Error obteniendo estadísticas", http.StatusInternalServerError)
+		return
+	}
+	defer muscleRows.Close()
+
+	for muscleRows.Next() {
+		var group models.MuscleGroupStats
+		if err := muscleRows.Scan(&group.MuscleGroupID, &group.MuscleGroupName, &group.PrimarySets, &group.SecondarySets); err != nil {
+			fmt.Printf("Error escaneando grupo muscular: %v\n", err)
+			continue
+		}
+		group.TotalSets = group.PrimarySets + group.SecondarySets
+		stats.MuscleGroups = append(stats.MuscleGroups, group)
+	}
+
+	// Deportes y cardio: distancia, duración, calorías y ritmo promedio
+	query, args = appendDateRangeFilter(`
+		SELECT e.id, e.name, COUNT(DISTINCT w.workout_day_id), COUNT(w.id),
+			COALESCE(SUM(w.distance_meters), 0), COALESCE(SUM(w.seconds), 0), COALESCE(SUM(w.calories), 0),
+			COALESCE(SUM(w.distance_meters) FILTER (WHERE w.seconds > 0), 0),
+			COALESCE(SUM(w.seconds) FILTER (WHERE w.distance_meters > 0), 0)
+		FROM workouts w
+		JOIN workout_days wd ON w.workout_day_id = wd.id
+		JOIN exercises e ON w.exercise_id = e.id
+		WHERE w.user_id = $1 AND w.deleted_at IS NULL AND e.is_sport`, "wd.date", from, to, []interface{}{userID})
+	query += " GROUP BY e.id, e.name ORDER BY COUNT(DISTINCT w.workout_day_id) DESC, e.name ASC"
+
+	sportRows, err := database.DB.Query(query, args...)
+	if err != nil {
+		fmt.Printf("Error consultando deportes: %v\n", err)
+		http.Error(w, "Error obteniendo estadísticas", http.StatusInternalServerError)
+		return
+	}
+	defer sportRows.Close()
+
+	for sportRows.Next() {
+		var sport models.SportStats
+		var pacedDistance float64
+		var pacedSeconds int
+		err := sportRows.Scan(&sport.ExerciseID, &sport.ExerciseName, &sport.Sessions, &sport.Entries,
+			&sport.DistanceMeters, &sport.DurationSeconds, &sport.Calories, &pacedDistance, &pacedSeconds)
+		if err != nil {
+			fmt.Printf("Error escaneando deporte: %v\n", err)
+			continue
+		}
+		// El ritmo promedio solo usa las entradas que tienen distancia y duración
+		sport.PaceSecondsPerKm, _ = cardioPace(&pacedDistance, &pacedSeconds)
+		stats.Sports = append(stats.Sports, sport)
+	}
+
+	stats.WeightUnit = getUserWeightUnit(userID)
+	convertStatsVolumes(&stats, stats.WeightUnit)
+
+	json.NewEncoder(w).Encode(stats)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeeklyStreaks(t *testing.T) {
+	weeks := map[string]bool{
+		"2024-01-01": true,
+		"2024-01-08": true,
+		"2024-01-15": true,
+		"2024-02-05": true,
+		"2024-02-12": true,
+	}
+
+	tests := []struct {
+		name        string
+		reference   string
+		wantCurrent int
+		wantLongest int
+	}{
+		{"semana actual entrenada", "2024-02-14", 2, 3},
+		{"semana actual todavía sin entrenar", "2024-02-21", 2, 3},
+		{"racha cortada", "2024-03-01", 0, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reference, _ := time.Parse("2006-01-02", tt.reference)
+			current, longest := weeklyStreaks(weeks, reference)
+			if current != tt.wantCurrent || longest != tt.wantLongest {
+				t.Errorf("rachas incorrectas: got (%d, %d) want (%d, %d)", current, longest, tt.wantCurrent, tt.wantLongest)
+			}
+		})
+	}
+}
+
+func TestSummarizeDays(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	days := []daySummary{
+		{Date: date("2024-03-04"), Effort: 8, Mood: 0, Sets: 10, Volume: 1000},
+		{Date: date("2024-03-06"), Effort: 0, Mood: 6, Sets: 5, Volume: 500},
+		{Date: date("2024-03-20"), Effort: 6, Mood: 8, Sets: 8, Volume: 800},
+	}
+
+	stats := summarizeDays(days, time.Time{}, date("2024-03-24"))
+
+	if stats.TotalSessions != 3 || stats.TotalWorkouts != 23 || stats.TotalVolume != 2300 {
+		t.Errorf("totales incorrectos: %+v", stats)
+	}
+	if stats.AvgEffort != 7 || stats.AvgMood != 7 {
+		t.Errorf("promedios incorrectos: effort %v mood %v", stats.AvgEffort, stats.AvgMood)
+	}
+	if len(stats.Weekly) != 3 || stats.Weekly[1].Sessions != 0 || stats.Weekly[0].Sets != 15 {
+		t.Errorf("semanas incorrectas: %+v", stats.Weekly)
+	}
+	if stats.AvgSessionsPerWeek != 1 {
+		t.Errorf("sesiones por semana incorrectas: %v", stats.AvgSessionsPerWeek)
+	}
+	if stats.CurrentStreakWeeks != 1 || stats.LongestStreakWeeks != 1 {
+		t.Errorf("rachas incorrectas: %d %d", stats.CurrentStreakWeeks, stats.LongestStreakWeeks)
+	}
+}
+
+func TestSummarizeDaysCapsWeekly(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	days := []daySummary{
+		{Date: date("2000-01-03"), Sets: 5},
+		{Date: date("2024-03-20"), Sets: 8},
+	}
+
+	stats := summarizeDays(days, time.Time{}, date("2024-03-24"))
+
+	if len(stats.Weekly) != maxStatsWeeks {
+		t.Fatalf("se esperaban %d semanas, se obtuvieron %d", maxStatsWeeks, len(stats.Weekly))
+	}
+	if last := stats.Weekly[len(stats.Weekly)-1]; last.WeekStart != "2024-03-18" || last.Sets != 8 {
+		t.Errorf("la última semana debería ser la del 2024-03-18: %+v", last)
+	}
+	if stats.TotalSessions != 2 || stats.TotalWorkouts != 13 {
+		t.Errorf("los totales deberían cubrir todo el rango: %+v", stats)
+	}
+	if weeks := progressBucketCount("week", days[0].Date, date("2024-03-24")); stats.AvgSessionsPerWeek != 2/float64(weeks) {
+		t.Errorf("el promedio debería usar todas las semanas del rango: %v", stats.AvgSessionsPerWeek)
+	}
+}
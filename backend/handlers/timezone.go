@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+)
+
+// defaultTimezone es la zona horaria usada cuando el usuario no configuró una
+const defaultTimezone = "America/Argentina/Buenos_Aires"
+
+// timezoneHeader permite al cliente indicar su zona horaria actual (por ejemplo, si está de viaje)
+const timezoneHeader = "X-Timezone"
+
+// loadTimezone carga una zona horaria IANA, con fallback a UTC-3 para la zona por defecto
+func loadTimezone(name string) (*time.Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		if name == defaultTimezone {
+			// Fallback a UTC-3 si no se puede cargar la zona horaria; se conserva el
+			// nombre IANA para poder usarlo también en consultas AT TIME ZONE
+			return time.FixedZone(defaultTimezone, -3*60*60), nil
+		}
+		return nil, fmt.Errorf("zona horaria inválida: %s", name)
+	}
+	return loc, nil
+}
+
+// isValidTimezone indica si el nombre corresponde a una zona horaria IANA conocida
+func isValidTimezone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// getUserTimezone obtiene la zona horaria configurada por el usuario en user_settings
+func getUserTimezone(userID string) string {
+	var timezone *string
+	err := database.DB.QueryRow("SELECT timezone FROM user_settings WHERE user_id = $1", userID).Scan(&timezone)
+	if err != nil || timezone == nil || !isValidTimezone(*timezone) {
+		return defaultTimezone
+	}
+	return *timezone
+}
+
+// getUserLocation resuelve la zona horaria de la request: primero el header X-Timezone,
+// después la configuración del usuario y por último la zona por defecto
+func getUserLocation(r *http.Request, userID string) *time.Location {
+	if headerTZ := r.Header.Get(timezoneHeader); headerTZ != "" {
+		if isValidTimezone(headerTZ) {
+			loc, _ := loadTimezone(headerTZ)
+			return loc
+		}
+		fmt.Printf("Zona horaria inválida en header %s: %s\n", timezoneHeader, headerTZ)
+	}
+
+	loc, err := loadTimezone(getUserTimezone(userID))
+	if err != nil {
+		loc, _ = loadTimezone(defaultTimezone)
+	}
+	return loc
+}
+
+// convertToUserTime convierte una fecha UTC a la zona horaria del usuario
+func convertToUserTime(utcTime time.Time, loc *time.Location) time.Time {
+	return utcTime.In(loc)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestLoadTimezone(t *testing.T) {
+	loc, err := loadTimezone("Europe/Madrid")
+	if err != nil {
+		t.Fatalf("Europe/Madrid debería ser válida: %v", err)
+	}
+	if loc.String() != "Europe/Madrid" {
+		t.Errorf("Zona incorrecta: got %v", loc.String())
+	}
+
+	if _, err := loadTimezone("Marte/Olympus_Mons"); err == nil {
+		t.Error("Una zona horaria inexistente debería devolver error")
+	}
+}
+
+func TestGetUserLocation_HeaderOverride(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/workouts", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(timezoneHeader, "Asia/Tokyo")
+
+	loc := getUserLocation(req, "test_user_id")
+	if loc.String() != "Asia/Tokyo" {
+		t.Errorf("El header %s debería tener prioridad: got %v", timezoneHeader, loc.String())
+	}
+
+	// 23:30 UTC ya es el día siguiente en Tokio
+	utc := time.Date(2024, 3, 10, 23, 30, 0, 0, time.UTC)
+	if got := convertToUserTime(utc, loc).Format("2006-01-02"); got != "2024-03-11" {
+		t.Errorf("Fecha local incorrecta: got %v want 2024-03-11", got)
+	}
+}
@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/gorilla/mux"
+)
+
+// trashRetention es el tiempo que lo eliminado queda en la papelera antes de purgarse
+const trashRetention = 30 * 24 * time.Hour
+
+var (
+	errTrashItemNotFound = errors.New("no está en la papelera")
+	errRestoreConflict   = errors.New("conflicto al restaurar")
+)
+
+// trashCutoff devuelve el momento antes del cual lo eliminado ya venció en now
+func trashCutoff(now time.Time) time.Time {
+	return now.Add(-trashRetention)
+}
+
+// trashPurgeAt devuelve cuándo se purga algo eliminado en deletedAt
+func trashPurgeAt(deletedAt time.Time) time.Time {
+	return deletedAt.Add(trashRetention)
+}
+
+// GetTrashHandler lista los días, las series y las rutinas en la papelera del usuario.
+// Las series de un día eliminado no se listan por separado: vuelven al restaurar el día.
+func GetTrashHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	loc := getUserLocation(r, userID)
+	unit := getUserWeightUnit(userID)
+	cutoff := trashCutoff(time.Now())
+	trash := models.Trash{
+		RetentionDays: int(trashRetention.Hours() / 24),
+		WorkoutDays:   []models.TrashedWorkoutDay{},
+		Workouts:      []models.TrashedWorkout{},
+		Routines:      []models.TrashedRoutine{},
+	}
+
+	dayRows, err := database.DB.Query(`
+		SELECT wd.id, wd.date::text, wd.name, wd.deleted_at,
+			(SELECT COUNT(*) FROM workouts w WHERE w.workout_day_id = wd.id AND w.deleted_at = wd.deleted_at)
+		FROM workout_days wd
+		WHERE wd.user_id = $1 AND wd.deleted_at > $2
+		ORDER BY wd.deleted_at DESC
+	`, userID, cutoff)
+	if err != nil {
+		fmt.Printf("Error consultando días en la papelera: %v\n", err)
+		http.Error(w, "Error obteniendo papelera", http.StatusInternalServerError)
+		return
+	}
+	defer dayRows.Close()
+
+	for dayRows.Next() {
+		var day models.TrashedWorkoutDay
+		if err := dayRows.Scan(&day.ID, &day.Date, &day.Name, &day.DeletedAt, &day.Sets); err != nil {
+			fmt.Printf("Error escaneando día en la papelera: %v\n", err)
+			continue
+		}
+		day.PurgeAt = convertToUserTime(trashPurgeAt(day.DeletedAt), loc)
+		day.DeletedAt = convertToUserTime(day.DeletedAt, loc)
+		trash.WorkoutDays = append(trash.WorkoutDays, day)
+	}
+
+	workoutRows, err := database.DB.Query(`
+		SELECT w.id, w.workout_day_id, wd.date::text, w.exercise_id, e.name, w.set, w.weight, w.reps, w.deleted_at
+		FROM workouts w
+		JOIN workout_days wd ON w.workout_day_id = wd.id
+		JOIN exercises e ON w.exercise_id = e.id
+		WHERE w.user_id = $1 AND w.deleted_at > $2
+			AND (wd.deleted_at IS NULL OR wd.deleted_at <> w.deleted_at)
+		ORDER BY w.deleted_at DESC, w.id DESC
+	`, userID, cutoff)
+	if err != nil {
+		fmt.Printf("Error consultando series en la papelera: %v\n", err)
+		http.Error(w, "Error obteniendo papelera", http.StatusInternalServerError)
+		return
+	}
+	defer workoutRows.Close()
+
+	for workoutRows.Next() {
+		var workout models.TrashedWorkout
+		if err := workoutRows.Scan(
+			&workout.ID, &workout.WorkoutDayID, &workout.Date, &workout.ExerciseID, &workout.ExerciseName,
+			&workout.Set, &workout.Weight, &workout.Reps, &workout.DeletedAt,
+		); err != nil {
+			fmt.Printf("Error escaneando serie en la papelera: %v\n", err)
+			continue
+		}
+		workout.Weight = fromKilograms(workout.Weight, unit)
+		workout.WeightUnit = unit
+		workout.PurgeAt = convertToUserTime(trashPurgeAt(workout.DeletedAt), loc)
+		workout.DeletedAt = convertToUserTime(workout.DeletedAt, loc)
+		trash.Workouts = append(trash.Workouts, workout)
+	}
+
+	routineRows, err := database.DB.Query(`
+		SELECT ur.id, ur.name, ur.deleted_at,
+			(SELECT COUNT(*) FROM routine_exercises re WHERE re.routine_id = ur.id)
+		FROM user_routines ur
+		WHERE ur.user_id = $1 AND ur.deleted_at > $2
+		ORDER BY ur.deleted_at DESC
+	`, userID, cutoff)
+	if err != nil {
+		fmt.Printf("Error consultando rutinas en la papelera: %v\n", err)
+		http.Error(w, "Error obteniendo papelera", http.StatusInternalServerError)
+		return
+	}
+	defer routineRows.Close()
+
+	for routineRows.Next() {
+		var routine models.TrashedRoutine
+		if err := routineRows.Scan(&routine.ID, &routine.Name, &routine.DeletedAt, &routine.Exercises); err != nil {
+			fmt.Printf("Error escaneando rutina en la papelera: %v\n", err)
+			continue
+		}
+		routine.PurgeAt = convertToUserTime(trashPurgeAt(routine.DeletedAt), loc)
+		routine.DeletedAt = convertToUserTime(routine.DeletedAt, loc)
+		trash.Routines = append(trash.Routines, routine)
+	}
+
+	json.NewEncoder(w).Encode(trash)
+}
+
+// restoreWorkout saca una serie de la papelera y la vuelve a ubicar con su número de serie,
+// corriendo las que la siguen. Falla si su día sigue en la papelera.
+func restoreWorkout(tx *sql.Tx, userID string, workoutID int) error {
+	var dayID, exerciseID, set int
+	var dayDeleted bool
+	err := tx.QueryRow(`
+		SELECT w.workout_day_id, w.exercise_id, w.set, wd.deleted_at IS NOT NULL
+		FROM workouts w
+		JOIN workout_days wd ON w.workout_day_id = wd.id
+		WHERE w.id = $1 AND w.user_id = $2 AND w.deleted_at > $3
+		FOR UPDATE OF w
+	`, workoutID, userID, trashCutoff(time.Now())).Scan(&dayID, &exerciseID, &set, &dayDeleted)
+	if err == sql.ErrNoRows {
+		return errTrashItemNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if dayDeleted {
+		return fmt.Errorf("%w: el día de la serie está en la papelera, restaurá el día", errRestoreConflict)
+	}
+
+	// Las series del ejercicio en el día se bloquean para verificar que todavía entra una más
+	others, err := loadSetOrder(tx, userID, dayID, exerciseID, workoutID)
+	if err != nil {
+		return err
+	}
+	if len(others) >= maxSetNumber {
+		return fmt.Errorf("%w: el ejercicio ya tiene %d series ese día", errRestoreConflict, maxSetNumber)
+	}
+
+	if _, err := tx.Exec("UPDATE workouts SET deleted_at = NULL WHERE id = $1", workoutID); err != nil {
+		return err
+	}
+	_, err = placeWorkoutSet(tx, userID, workoutID, dayID, exerciseID, set)
+	return err
+}
+
+// restoreWorkoutDay saca un día de la papelera junto con las series que se eliminaron con él.
+// Falla si mientras tanto se creó otro día en la misma fecha.
+func restoreWorkoutDay(tx *sql.Tx, userID string, dayID int) error {
+	var date string
+	var deletedAt time.Time
+	err := tx.QueryRow(`
+		SELECT date::text, deleted_at FROM workout_days
+		WHERE id = $1 AND user_id = $2 AND deleted_at > $3
+		FOR UPDATE
+	`, dayID, userID, trashCutoff(time.Now())).Scan(&date, &deletedAt)
+	if err == sql.ErrNoRows {
+		return errTrashItemNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	var taken bool
+	err = tx.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM workout_days WHERE user_id = $1 AND date = $2 AND deleted_at IS NULL)",
+		userID, date,
+	).Scan(&taken)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return fmt.Errorf("%w: ya hay un día de entrenamiento el %s", errRestoreConflict, date)
+	}
+
+	if _, err := tx.Exec("UPDATE workouts SET deleted_at = NULL WHERE workout_day_id = $1 AND deleted_at = $2", dayID, deletedAt); err != nil {
+		return err
+	}
+	_, err = tx.Exec("UPDATE workout_days SET deleted_at = NULL WHERE id = $1", dayID)
+	return err
+}
+
+// restoreUserRoutine saca una rutina de la papelera
+func restoreUserRoutine(tx *sql.Tx, userID string, routineID int) error {
+	result, err := tx.Exec(
+		"UPDATE user_routines SET deleted_at = NULL WHERE id = $1 AND user_id = $2 AND deleted_at > $3",
+		routineID, userID, trashCutoff(time.Now()),
+	)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errTrashItemNotFound
+	}
+	return nil
+}
+
+// restoreFromTrash restaura con restore el elemento de la papelera indicado en la ruta
+func restoreFromTrash(w http.ResponseWriter, r *http.Request, restore func(tx *sql.Tx, userID string, id int) error) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	if err := restore(tx, userID, id); err != nil {
+		switch {
+		case errors.Is(err, errTrashItemNotFound):
+			http.Error(w, "No se encontró en la papelera", http.StatusNotFound)
+		case errors.Is(err, errRestoreConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			fmt.Printf("Error restaurando desde la papelera: %v\n", err)
+			http.Error(w, "Error restaurando desde la papelera", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, "Error confirmando transacción", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreWorkoutHandler restaura una serie de la papelera
+func RestoreWorkoutHandler(w http.ResponseWriter, r *http.Request) {
+	restoreFromTrash(w, r, restoreWorkout)
+}
+
+// RestoreWorkoutDayHandler restaura un día de entrenamiento y sus series de la papelera
+func RestoreWorkoutDayHandler(w http.ResponseWriter, r *http.Request) {
+	restoreFromTrash(w, r, restoreWorkoutDay)
+}
+
+// RestoreUserRoutineHandler restaura una rutina de la papelera
+func RestoreUserRoutineHandler(w http.ResponseWriter, r *http.Request) {
+	restoreFromTrash(w, r, restoreUserRoutine)
+}
+
+// trashPurgeQueries borran lo vencido antes del corte $1, en orden: las series y los kudos de
+// un día se borran antes que el día al que referencian
+var trashPurgeQueries = []string{
+	"DELETE FROM workouts WHERE deleted_at < $1",
+	"DELETE FROM kudos WHERE workout_day_id IN (SELECT id FROM workout_days WHERE deleted_at < $1)",
+	"DELETE FROM workouts WHERE workout_day_id IN (SELECT id FROM workout_days WHERE deleted_at < $1)",
+	"DELETE FROM workout_days WHERE deleted_at < $1",
+	// Las rutinas que son días de un programa se conservan aunque estén en la papelera
+	"DELETE FROM user_routines WHERE deleted_at < $1 AND NOT EXISTS (SELECT 1 FROM program_days pd WHERE pd.routine_id = user_routines.id)",
+}
+
+// PurgeTrash borra definitivamente lo que lleva en la papelera más que trashRetention.
+// Los récords, bloques y ejercicios de rutina se borran por CASCADE.
+func PurgeTrash() error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	cutoff := trashCutoff(time.Now())
+	for _, query := range trashPurgeQueries {
+		if _, err := tx.Exec(query, cutoff); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// StartTrashPurge purga la papelera al iniciar y después cada interval, en segundo plano
+func StartTrashPurge(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := PurgeTrash(); err != nil {
+				fmt.Printf("Error purgando la papelera: %v\n", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
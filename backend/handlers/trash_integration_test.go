@@ -0,0 +1,165 @@
+//go:build integration
+
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/testutils"
+)
+
+// setupTrashTest crea un usuario de prueba y devuelve su id y un ejercicio para sus series
+func setupTrashTest(t *testing.T) (string, int) {
+	testutils.SetupTestDatabase(t)
+
+	var exerciseID int
+	if err := database.DB.QueryRow("SELECT id FROM exercises ORDER BY id LIMIT 1").Scan(&exerciseID); err != nil {
+		t.Skipf("No hay ejercicios en la base de prueba: %v", err)
+	}
+
+	userID := testutils.GetTestUserID(t)
+	testutils.CreateTestUserInDB(t, userID)
+	t.Cleanup(func() {
+		testutils.CleanupTestUser(t, userID)
+	})
+	return userID, exerciseID
+}
+
+func createTrashTestDay(t *testing.T, userID, date string, deletedAt *time.Time) int {
+	var dayID int
+	err := database.DB.QueryRow(`
+		INSERT INTO workout_days (user_id, date, name, deleted_at) VALUES ($1, $2, 'Test', $3) RETURNING id
+	`, userID, date, deletedAt).Scan(&dayID)
+	if err != nil {
+		t.Fatalf("Error creando día de entrenamiento: %v", err)
+	}
+	return dayID
+}
+
+func createTrashTestWorkout(t *testing.T, userID string, dayID, exerciseID, set int, deletedAt *time.Time) int {
+	var workoutID int
+	err := database.DB.QueryRow(`
+		INSERT INTO workouts (user_id, workout_day_id, exercise_id, weight, reps, set, deleted_at)
+		VALUES ($1, $2, $3, 50, 10, $4, $5) RETURNING id
+	`, userID, dayID, exerciseID, set, deletedAt).Scan(&workoutID)
+	if err != nil {
+		t.Fatalf("Error creando serie: %v", err)
+	}
+	return workoutID
+}
+
+func restoreInTx(t *testing.T, restore func(tx *sql.Tx, userID string, id int) error, userID string, id int) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		t.Fatalf("Error iniciando transacción: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := restore(tx, userID, id); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Error confirmando transacción: %v", err)
+	}
+	return nil
+}
+
+// TestSupabaseRestoreWorkoutDayConflict prueba que no se restaura un día si ya hay otro en su fecha
+func TestSupabaseRestoreWorkoutDayConflict(t *testing.T) {
+	userID, _ := setupTrashTest(t)
+
+	deletedAt := time.Now().Add(-time.Hour)
+	trashedDayID := createTrashTestDay(t, userID, "2024-05-01", &deletedAt)
+	createTrashTestDay(t, userID, "2024-05-01", nil)
+
+	err := restoreInTx(t, restoreWorkoutDay, userID, trashedDayID)
+	if !errors.Is(err, errRestoreConflict) {
+		t.Fatalf("se esperaba un conflicto, se obtuvo %v", err)
+	}
+
+	var stillTrashed bool
+	database.DB.QueryRow("SELECT deleted_at IS NOT NULL FROM workout_days WHERE id = $1", trashedDayID).Scan(&stillTrashed)
+	if !stillTrashed {
+		t.Errorf("el día debería seguir en la papelera")
+	}
+}
+
+// TestSupabaseRestoreWorkoutDayOnlyItsWorkouts prueba que al restaurar un día vuelven solo las
+// series que se eliminaron con él, no las que ya estaban en la papelera
+func TestSupabaseRestoreWorkoutDayOnlyItsWorkouts(t *testing.T) {
+	userID, exerciseID := setupTrashTest(t)
+
+	earlier := time.Now().Add(-2 * time.Hour).Truncate(time.Microsecond)
+	withDay := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	dayID := createTrashTestDay(t, userID, "2024-05-02", &withDay)
+	trashedBefore := createTrashTestWorkout(t, userID, dayID, exerciseID, 1, &earlier)
+	trashedWithDay := createTrashTestWorkout(t, userID, dayID, exerciseID, 2, &withDay)
+
+	if err := restoreInTx(t, restoreWorkoutDay, userID, dayID); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	for workoutID, wantTrashed := range map[int]bool{trashedBefore: true, trashedWithDay: false} {
+		var trashed bool
+		err := database.DB.QueryRow("SELECT deleted_at IS NOT NULL FROM workouts WHERE id = $1", workoutID).Scan(&trashed)
+		if err != nil {
+			t.Fatalf("Error leyendo serie %d: %v", workoutID, err)
+		}
+		if trashed != wantTrashed {
+			t.Errorf("serie %d: en papelera = %v, se esperaba %v", workoutID, trashed, wantTrashed)
+		}
+	}
+}
+
+// TestSupabaseRestoreWorkoutFullDay prueba que no se restaura una serie si el ejercicio ya tiene
+// el máximo de series en el día
+func TestSupabaseRestoreWorkoutFullDay(t *testing.T) {
+	userID, exerciseID := setupTrashTest(t)
+
+	deletedAt := time.Now().Add(-time.Hour)
+	dayID := createTrashTestDay(t, userID, "2024-05-05", nil)
+	trashedID := createTrashTestWorkout(t, userID, dayID, exerciseID, maxSetNumber+1, &deletedAt)
+	for set := 1; set <= maxSetNumber; set++ {
+		createTrashTestWorkout(t, userID, dayID, exerciseID, set, nil)
+	}
+
+	err := restoreInTx(t, restoreWorkout, userID, trashedID)
+	if !errors.Is(err, errRestoreConflict) {
+		t.Fatalf("se esperaba un conflicto, se obtuvo %v", err)
+	}
+}
+
+// TestSupabasePurgeTrash prueba que la purga borra los días vencidos con sus series (las series
+// antes que el día) y conserva lo que todavía puede restaurarse
+func TestSupabasePurgeTrash(t *testing.T) {
+	userID, exerciseID := setupTrashTest(t)
+
+	expired := time.Now().AddDate(0, 0, -31)
+	recent := time.Now().AddDate(0, 0, -29)
+	expiredDayID := createTrashTestDay(t, userID, "2024-05-03", &expired)
+	expiredWorkoutID := createTrashTestWorkout(t, userID, expiredDayID, exerciseID, 1, &expired)
+	recentDayID := createTrashTestDay(t, userID, "2024-05-04", &recent)
+	recentWorkoutID := createTrashTestWorkout(t, userID, recentDayID, exerciseID, 1, &recent)
+
+	if err := PurgeTrash(); err != nil {
+		t.Fatalf("Error purgando la papelera: %v", err)
+	}
+
+	exists := func(table string, id int) bool {
+		var found bool
+		if err := database.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM "+table+" WHERE id = $1)", id).Scan(&found); err != nil {
+			t.Fatalf("Error consultando %s: %v", table, err)
+		}
+		return found
+	}
+	if exists("workout_days", expiredDayID) || exists("workouts", expiredWorkoutID) {
+		t.Errorf("el día vencido y su serie deberían haberse purgado")
+	}
+	if !exists("workout_days", recentDayID) || !exists("workouts", recentWorkoutID) {
+		t.Errorf("el día reciente y su serie deberían seguir en la papelera")
+	}
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrashRetention(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	cutoff := trashCutoff(now)
+
+	recent := now.AddDate(0, 0, -29)
+	if !recent.After(cutoff) {
+		t.Errorf("algo eliminado hace 29 días todavía debería poder restaurarse")
+	}
+	if !trashPurgeAt(recent).After(now) {
+		t.Errorf("purge_at debería ser posterior a ahora: %v", trashPurgeAt(recent))
+	}
+
+	old := now.AddDate(0, 0, -31)
+	if !old.Before(cutoff) {
+		t.Errorf("algo eliminado hace 31 días debería purgarse")
+	}
+	if got := trashPurgeAt(old); !got.Equal(old.Add(30 * 24 * time.Hour)) {
+		t.Errorf("purge_at incorrecto: %v", got)
+	}
+}
+
+func TestTrashPurgeQueriesOrder(t *testing.T) {
+	position := func(prefix string) int {
+		for i, query := range trashPurgeQueries {
+			if strings.HasPrefix(query, prefix) {
+				return i
+			}
+		}
+		t.Fatalf("no hay una consulta que empiece con %q", prefix)
+		return -1
+	}
+
+	days := position("DELETE FROM workout_days")
+	for _, dependent := range []string{"DELETE FROM workouts WHERE workout_day_id", "DELETE FROM kudos"} {
+		if position(dependent) > days {
+			t.Errorf("%q debería correr antes de borrar los días", dependent)
+		}
+	}
+
+	for _, query := range trashPurgeQueries {
+		if !strings.Contains(query, "deleted_at < $1") {
+			t.Errorf("la consulta debería filtrar por el corte: %s", query)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+)
+
+// poundsToKg es el factor de conversión de libras a kilos
+const poundsToKg = 0.45359237
+
+var errInvalidWeightUnit = errors.New("weight_unit debe ser kg o lb")
+
+// isValidWeightUnit indica si la unidad de peso es kg o lb
+func isValidWeightUnit(unit string) bool {
+	return unit == models.WeightUnitKg || unit == models.WeightUnitLb
+}
+
+// getUserWeightUnit obtiene la unidad de peso configurada por el usuario en user_settings (kg por defecto)
+func getUserWeightUnit(userID string) string {
+	var unit *string
+	err := database.DB.QueryRow("SELECT weight_unit FROM user_settings WHERE user_id = $1", userID).Scan(&unit)
+	if err != nil || unit == nil || !isValidWeightUnit(*unit) {
+		return models.WeightUnitKg
+	}
+	return *unit
+}
+
+// requestWeightUnit devuelve la unidad indicada en la solicitud o, si no se envía, fallback
+// (normalmente la unidad preferida del usuario)
+func requestWeightUnit(unit *string, fallback string) (string, error) {
+	if unit == nil || *unit == "" {
+		return fallback, nil
+	}
+	if !isValidWeightUnit(*unit) {
+		return "", errInvalidWeightUnit
+	}
+	return *unit, nil
+}
+
+// toKilograms convierte un peso expresado en unit a kilos, la unidad en la que se guarda
+func toKilograms(weight float64, unit string) float64 {
+	if unit == models.WeightUnitLb {
+		return weight * poundsToKg
+	}
+	return weight
+}
+
+// fromKilograms convierte un peso guardado en kilos a unit. Las libras se redondean
+// a dos decimales para que un peso cargado en libras vuelva exactamente igual.
+func fromKilograms(weight float64, unit string) float64 {
+	if unit == models.WeightUnitLb {
+		return math.Round(weight/poundsToKg*100) / 100
+	}
+	return weight
+}
+
+// weightPtrFromKilograms convierte un peso opcional guardado en kilos a unit
+func weightPtrFromKilograms(weight *float64, unit string) *float64 {
+	if weight == nil {
+		return nil
+	}
+	converted := fromKilograms(*weight, unit)
+	return &converted
+}
+
+// weightPtrToKilograms convierte un peso opcional expresado en unit a kilos
+func weightPtrToKilograms(weight *float64, unit string) *float64 {
+	if weight == nil {
+		return nil
+	}
+	converted := toKilograms(*weight, unit)
+	return &converted
+}
+
+// convertWorkoutWeight expresa en unit el peso de una serie y de sus récords
+func convertWorkoutWeight(workout *models.Workout, unit string) {
+	workout.Weight = fromKilograms(workout.Weight, unit)
+	workout.Bodyweight = weightPtrFromKilograms(workout.Bodyweight, unit)
+	workout.WeightUnit = unit
+	convertRecordWeights(workout.PersonalRecords, unit)
+}
+
+// convertWorkoutWeights expresa en unit el peso de varias series
+func convertWorkoutWeights(workouts []models.Workout, unit string) {
+	for i := range workouts {
+		convertWorkoutWeight(&workouts[i], unit)
+	}
+}
+
+// convertRecordWeights expresa en unit los valores de los récords. En max_reps_at_weight
+// el valor son repeticiones y solo se convierte el peso de referencia.
+func convertRecordWeights(records []models.PersonalRecord, unit string) {
+	for i := range records {
+		record := &records[i]
+		if record.RecordType != models.RecordTypeMaxRepsAtWeight {
+			record.Value = fromKilograms(record.Value, unit)
+			record.PreviousValue = weightPtrFromKilograms(record.PreviousValue, unit)
+		}
+		record.Weight = weightPtrFromKilograms(record.Weight, unit)
+		record.WeightUnit = unit
+	}
+}
+
+// convertWorkoutDayWeights expresa en unit todos los pesos de un día: series, grupos, bloques y plan
+func convertWorkoutDayWeights(day *models.WorkoutDayWithExercises, unit string) {
+	day.WeightUnit = unit
+	convertWorkoutWeights(day.Sets, unit)
+	for i := range day.ExerciseGroups {
+		convertWorkoutWeights(day.ExerciseGroups[i].Workouts, unit)
+	}
+	for i := range day.Blocks {
+		convertWorkoutBlockWeights(&day.Blocks[i], unit)
+	}
+	if day.Plan != nil {
+		convertWorkoutDayPlanWeights(day.Plan, unit)
+	}
+}
+
+// convertWorkoutBlockWeights expresa en unit el peso de las series de un bloque
+func convertWorkoutBlockWeights(block *models.WorkoutBlock, unit string) {
+	block.WeightUnit = unit
+	convertWorkoutWeights(block.Workouts, unit)
+}
+
+// convertWorkoutDayPlanWeights expresa en unit los pesos planificados y realizados de un plan
+func convertWorkoutDayPlanWeights(plan *models.WorkoutDayPlan, unit string) {
+	plan.WeightUnit = unit
+	for i := range plan.Exercises {
+		exercise := &plan.Exercises[i]
+		for s := range exercise.Sets {
+			exercise.Sets[s].PlannedWeight = weightPtrFromKilograms(exercise.Sets[s].PlannedWeight, unit)
+			exercise.Sets[s].PerformedWeight = weightPtrFromKilograms(exercise.Sets[s].PerformedWeight, unit)
+		}
+		convertWorkoutWeights(exercise.ExtraSets, unit)
+	}
+	convertWorkoutWeights(plan.UnplannedWorkouts, unit)
+}
+
+// convertRoutineExerciseWeights expresa en unit el peso planificado y el incremento de los ejercicios de una rutina
+func convertRoutineExerciseWeights(exercises []models.RoutineExercise, unit string) {
+	for i := range exercises {
+		exercises[i].Weight = weightPtrFromKilograms(exercises[i].Weight, unit)
+		exercises[i].WeightIncrement = weightPtrFromKilograms(exercises[i].WeightIncrement, unit)
+		exercises[i].WeightUnit = unit
+	}
+}
+
+// convertStatsVolumes expresa en unit los volúmenes totales, semanales y por ejercicio
+func convertStatsVolumes(stats *models.UserStats, unit string) {
+	stats.TotalVolume = fromKilograms(stats.TotalVolume, unit)
+	for i := range stats.Weekly {
+		stats.Weekly[i].Volume = fromKilograms(stats.Weekly[i].Volume, unit)
+	}
+	for i := range stats.TopExercises {
+		stats.TopExercises[i].Volume = fromKilograms(stats.TopExercises[i].Volume, unit)
+	}
+}
+
+// convertCalendarVolumes expresa en unit el volumen de cada día del calendario
+func convertCalendarVolumes(calendar *models.TrainingCalendar, unit string) {
+	for i := range calendar.Days {
+		calendar.Days[i].Volume = fromKilograms(calendar.Days[i].Volume, unit)
+	}
+}
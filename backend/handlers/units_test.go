@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+func TestWeightConversionRoundTrip(t *testing.T) {
+	for _, pounds := range []float64{45, 100, 135, 225, 2.5, 317.5} {
+		kg := toKilograms(pounds, models.WeightUnitLb)
+		if got := fromKilograms(kg, models.WeightUnitLb); got != pounds {
+			t.Errorf("%v lb -> %v kg -> %v lb, se esperaba volver al mismo valor", pounds, kg, got)
+		}
+	}
+
+	if got := toKilograms(60, models.WeightUnitKg); got != 60 {
+		t.Errorf("kg no debería convertirse, got %v", got)
+	}
+	if got := fromKilograms(100, models.WeightUnitLb); got != 220.46 {
+		t.Errorf("100 kg = %v lb, se esperaba 220.46", got)
+	}
+}
+
+func TestRequestWeightUnit(t *testing.T) {
+	lb, empty, invalid := "lb", "", "stone"
+
+	if unit, err := requestWeightUnit(nil, models.WeightUnitKg); err != nil || unit != models.WeightUnitKg {
+		t.Errorf("sin unidad debería usar la del usuario, got %q %v", unit, err)
+	}
+	if unit, err := requestWeightUnit(&empty, models.WeightUnitLb); err != nil || unit != models.WeightUnitLb {
+		t.Errorf("unidad vacía debería usar la del usuario, got %q %v", unit, err)
+	}
+	if unit, err := requestWeightUnit(&lb, models.WeightUnitKg); err != nil || unit != models.WeightUnitLb {
+		t.Errorf("la unidad explícita tiene prioridad, got %q %v", unit, err)
+	}
+	if _, err := requestWeightUnit(&invalid, models.WeightUnitKg); err == nil {
+		t.Errorf("se esperaba error para una unidad inválida")
+	}
+}
+
+func TestConvertRecordWeights(t *testing.T) {
+	weight := 100.0
+	previous := 95.0
+	records := []models.PersonalRecord{
+		{RecordType: models.RecordTypeMaxWeight, Value: 100, Weight: &weight, PreviousValue: &previous},
+		{RecordType: models.RecordTypeMaxRepsAtWeight, Value: 8, Weight: &weight},
+	}
+
+	convertRecordWeights(records, models.WeightUnitLb)
+
+	if records[0].Value != 220.46 || *records[0].PreviousValue != 209.44 {
+		t.Errorf("peso máximo mal convertido: %v (anterior %v)", records[0].Value, *records[0].PreviousValue)
+	}
+	if records[1].Value != 8 {
+		t.Errorf("las repeticiones no deben convertirse, got %v", records[1].Value)
+	}
+	if *records[1].Weight != 220.46 || records[1].WeightUnit != models.WeightUnitLb {
+		t.Errorf("peso de referencia mal convertido: %v %s", *records[1].Weight, records[1].WeightUnit)
+	}
+	if weight != 100 {
+		t.Errorf("la conversión no debe modificar el valor original en kg")
+	}
+}
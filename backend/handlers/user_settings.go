@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"net/http"
 	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
 )
 
 type UserSettings struct {
 	HasConfiguredFavorites  bool    `json:"has_configured_favorites"`
 	FavoriteExercises       []int   `json:"favorite_exercises"`
+	Timezone                string  `json:"timezone"`
+	WeightUnit              string  `json:"weight_unit"` // kg o lb; los pesos se guardan en kg
 }
 
 // GetUserSettingsHandler obtiene las configuraciones del usuario
@@ -30,23 +33,29 @@ func GetUserSettingsHandler(w http.ResponseWriter, r *http.Request) {
 	
 	// Primero intentar con la estructura nueva
 	query := `
-		SELECT has_configured_favorites, favorite_exercises
+		SELECT has_configured_favorites, favorite_exercises, COALESCE(timezone, $2), COALESCE(weight_unit, $3)
 		FROM user_settings
 		WHERE user_id = $1
 	`
 	
-	err := database.DB.QueryRow(query, userID).Scan(
+	err := database.DB.QueryRow(query, userID, defaultTimezone, models.WeightUnitKg).Scan(
 		&settings.HasConfiguredFavorites,
 		&settings.FavoriteExercises,
+		&settings.Timezone,
+		&settings.WeightUnit,
 	)
 	
 	// Si hay error de columna inexistente, usar valores por defecto
 	if err != nil && (err.Error() == "pq: column \"has_configured_favorites\" does not exist" || 
-		err.Error() == "pq: column \"favorite_exercises\" does not exist") {
+		err.Error() == "pq: column \"favorite_exercises\" does not exist" ||
+		err.Error() == "pq: column \"timezone\" does not exist" ||
+		err.Error() == "pq: column \"weight_unit\" does not exist") {
 		fmt.Printf("🔍 Columnas no existen, usando valores por defecto para user %s\n", userID)
 		settings = UserSettings{
 			HasConfiguredFavorites:  false,
 			FavoriteExercises:       []int{},
+			Timezone:                defaultTimezone,
+			WeightUnit:              models.WeightUnitKg,
 		}
 		err = nil // Resetear error para continuar
 	}
@@ -58,13 +67,15 @@ func GetUserSettingsHandler(w http.ResponseWriter, r *http.Request) {
 			settings = UserSettings{
 				HasConfiguredFavorites:  false,
 				FavoriteExercises:       []int{},
+				Timezone:                defaultTimezone,
+				WeightUnit:              models.WeightUnitKg,
 			}
 			
 			insertQuery := `
-				INSERT INTO user_settings (user_id, has_configured_favorites, favorite_exercises)
-				VALUES ($1, $2, $3)
+				INSERT INTO user_settings (user_id, has_configured_favorites, favorite_exercises, timezone)
+				VALUES ($1, $2, $3, $4)
 			`
-			_, err = database.DB.Exec(insertQuery, userID, settings.HasConfiguredFavorites, settings.FavoriteExercises)
+			_, err = database.DB.Exec(insertQuery, userID, settings.HasConfiguredFavorites, settings.FavoriteExercises, settings.Timezone)
 			if err != nil {
 				fmt.Printf("Error creando configuraciones por defecto: %v\n", err)
 				http.Error(w, "Error creando configuraciones", http.StatusInternalServerError)
@@ -103,36 +114,62 @@ func UpdateUserSettingsHandler(w http.ResponseWriter, r *http.Request) {
 
 	fmt.Printf("🔍 Updating settings for user %s: %+v\n", userID, settings)
 
+	// La zona horaria es opcional: si no se envía se conserva la actual
+	var timezone *string
+	if settings.Timezone != "" {
+		if !isValidTimezone(settings.Timezone) {
+			http.Error(w, "Zona horaria inválida", http.StatusBadRequest)
+			return
+		}
+		timezone = &settings.Timezone
+	}
+
+	// La unidad de peso también es opcional
+	var weightUnit *string
+	if settings.WeightUnit != "" {
+		if !isValidWeightUnit(settings.WeightUnit) {
+			http.Error(w, errInvalidWeightUnit.Error(), http.StatusBadRequest)
+			return
+		}
+		weightUnit = &settings.WeightUnit
+	}
+
 	// Upsert: insertar si no existe, actualizar si existe
 	query := `
-		INSERT INTO user_settings (user_id, has_configured_favorites, favorite_exercises)
-		VALUES ($1, $2, $3)
+		INSERT INTO user_settings (user_id, has_configured_favorites, favorite_exercises, timezone, weight_unit)
+		VALUES ($1, $2, $3, COALESCE($4::text, $5), COALESCE($6::text, $7))
 		ON CONFLICT (user_id) 
 		DO UPDATE SET 
 			has_configured_favorites = EXCLUDED.has_configured_favorites,
 			favorite_exercises = EXCLUDED.favorite_exercises,
+			timezone = COALESCE($4::text, user_settings.timezone, $5),
+			weight_unit = COALESCE($6::text, user_settings.weight_unit, $7),
 			updated_at = NOW()
 	`
 
-	_, err := database.DB.Exec(query, userID, settings.HasConfiguredFavorites, settings.FavoriteExercises)
+	_, err := database.DB.Exec(query, userID, settings.HasConfiguredFavorites, settings.FavoriteExercises, timezone, defaultTimezone, weightUnit, models.WeightUnitKg)
 	if err != nil {
 		// Si hay error de columna inexistente, intentar crear la tabla/columnas
 		if err.Error() == "pq: column \"has_configured_favorites\" does not exist" || 
-		   err.Error() == "pq: column \"favorite_exercises\" does not exist" {
+		   err.Error() == "pq: column \"favorite_exercises\" does not exist" ||
+		   err.Error() == "pq: column \"timezone\" does not exist" ||
+		   err.Error() == "pq: column \"weight_unit\" does not exist" {
 			fmt.Printf("🔍 Columnas no existen, intentando crear estructura para user %s\n", userID)
 			
 			// Intentar agregar las columnas
 			_, alterErr := database.DB.Exec(`
 				ALTER TABLE user_settings 
 				ADD COLUMN IF NOT EXISTS has_configured_favorites BOOLEAN DEFAULT false,
-				ADD COLUMN IF NOT EXISTS favorite_exercises INTEGER[] DEFAULT '{}'
+				ADD COLUMN IF NOT EXISTS favorite_exercises INTEGER[] DEFAULT '{}',
+				ADD COLUMN IF NOT EXISTS timezone TEXT DEFAULT 'America/Argentina/Buenos_Aires',
+				ADD COLUMN IF NOT EXISTS weight_unit TEXT NOT NULL DEFAULT 'kg'
 			`)
 			if alterErr != nil {
 				fmt.Printf("Error creando columnas: %v\n", alterErr)
 			} else {
 				fmt.Printf("✅ Columnas creadas, reintentando inserción\n")
 				// Reintentar la inserción
-				_, err = database.DB.Exec(query, userID, settings.HasConfiguredFavorites, settings.FavoriteExercises)
+				_, err = database.DB.Exec(query, userID, settings.HasConfiguredFavorites, settings.FavoriteExercises, timezone, defaultTimezone, weightUnit, models.WeightUnitKg)
 			}
 		}
 		
@@ -0,0 +1,543 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+var (
+	errWorkoutBlockNotFound = errors.New("bloque no encontrado en el día de entrenamiento")
+	errInvalidBlockWorkouts = errors.New("todas las series del bloque deben pertenecer al mismo día de entrenamiento")
+	errInvalidBlockType     = errors.New("tipo de bloque inválido, usar superset o circuit")
+	errInvalidBlockRest     = errors.New("el descanso del bloque debe estar entre 0 y 3600 segundos")
+)
+
+// isValidBlockType indica si el tipo de bloque es superset o circuit
+func isValidBlockType(blockType string) bool {
+	return blockType == models.BlockTypeSuperset || blockType == models.BlockTypeCircuit
+}
+
+// resolveWorkoutBlockID determina el bloque de una serie: el indicado (que debe ser del mismo día)
+// o, si no se indica, el de la última serie del mismo ejercicio en ese día
+func resolveWorkoutBlockID(q dbQuerier, userID string, workoutDayID, exerciseID int, blockID *int) (*int, error) {
+	if blockID != nil {
+		var id int
+		err := q.QueryRow(
+			"SELECT id FROM workout_blocks WHERE id = $1 AND user_id = $2 AND workout_day_id = $3",
+			*blockID, userID, workoutDayID,
+		).Scan(&id)
+		if err == sql.ErrNoRows {
+			return nil, errWorkoutBlockNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &id, nil
+	}
+
+	var inherited *int
+	err := q.QueryRow(`
+		SELECT block_id FROM workouts
+		WHERE user_id = $1 AND workout_day_id = $2 AND exercise_id = $3 AND deleted_at IS NULL
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`, userID, workoutDayID, exerciseID).Scan(&inherited)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return inherited, nil
+}
+
+// validateRoutineBlocks verifica que los ejercicios de un mismo bloque de una rutina compartan tipo y descanso,
+// completando el tipo por defecto (superset)
+func validateRoutineBlocks(exercises []models.CreateRoutineExerciseRequest) error {
+	type blockConfig struct {
+		blockType string
+		rest      *int
+	}
+	blocks := make(map[int]blockConfig)
+	for i := range exercises {
+		exercise := &exercises[i]
+		if exercise.BlockIndex == nil {
+			if exercise.BlockType != nil || exercise.BlockRestTimeSeconds != nil {
+				return fmt.Errorf("ejercicio %d: block_type y block_rest_time_seconds requieren block_index", i+1)
+			}
+			continue
+		}
+
+		if *exercise.BlockIndex < 0 {
+			return fmt.Errorf("ejercicio %d: block_index no puede ser negativo", i+1)
+		}
+
+		blockType := models.BlockTypeSuperset
+		if exercise.BlockType != nil {
+			blockType = *exercise.BlockType
+		}
+		if !isValidBlockType(blockType) {
+			return fmt.Errorf("ejercicio %d: %v", i+1, errInvalidBlockType)
+		}
+		if exercise.BlockRestTimeSeconds != nil && (*exercise.BlockRestTimeSeconds < 0 || *exercise.BlockRestTimeSeconds > 3600) {
+			return fmt.Errorf("ejercicio %d: %v", i+1, errInvalidBlockRest)
+		}
+		exercise.BlockType = &blockType
+
+		config, seen := blocks[*exercise.BlockIndex]
+		if !seen {
+			blocks[*exercise.BlockIndex] = blockConfig{blockType: blockType, rest: exercise.BlockRestTimeSeconds}
+			continue
+		}
+		if config.blockType != blockType {
+			return fmt.Errorf("ejercicio %d: los ejercicios del bloque %d deben tener el mismo tipo", i+1, *exercise.BlockIndex)
+		}
+		if exercise.BlockRestTimeSeconds == nil {
+			exercise.BlockRestTimeSeconds = config.rest
+		} else if config.rest != nil && *config.rest != *exercise.BlockRestTimeSeconds {
+			return fmt.Errorf("ejercicio %d: los ejercicios del bloque %d deben compartir el descanso", i+1, *exercise.BlockIndex)
+		}
+	}
+	return nil
+}
+
+// routineBlocksAfterUpdate devuelve los bloques de los ejercicios de una rutina tal como quedan después de
+// aplicar req al ejercicio routineExerciseID, listos para validateRoutineBlocks. Al sumarse a un bloque el
+// ejercicio toma su tipo y descanso; el tipo y el descanso pedidos se aplican a todo el bloque.
+func routineBlocksAfterUpdate(exercises []models.RoutineExercise, routineExerciseID int, req *models.UpdateRoutineExerciseRequest) ([]models.CreateRoutineExerciseRequest, error) {
+	if req.LeaveBlock && (req.BlockIndex != nil || req.BlockType != nil || req.BlockRestTimeSeconds != nil) {
+		return nil, errors.New("leave_block no se puede combinar con block_index, block_type ni block_rest_time_seconds")
+	}
+
+	blocks := make([]models.CreateRoutineExerciseRequest, len(exercises))
+	target := -1
+	for i, exercise := range exercises {
+		blocks[i] = models.CreateRoutineExerciseRequest{
+			ExerciseID:           exercise.ExerciseID,
+			BlockIndex:           exercise.BlockIndex,
+			BlockType:            exercise.BlockType,
+			BlockRestTimeSeconds: exercise.BlockRestTimeSeconds,
+		}
+		if exercise.ID == routineExerciseID {
+			target = i
+		}
+	}
+	if target < 0 {
+		return nil, errRoutineExerciseNotFound
+	}
+
+	updated := &blocks[target]
+	if req.LeaveBlock {
+		updated.BlockIndex, updated.BlockType, updated.BlockRestTimeSeconds = nil, nil, nil
+	}
+	if req.BlockIndex != nil && (updated.BlockIndex == nil || *updated.BlockIndex != *req.BlockIndex) {
+		updated.BlockIndex, updated.BlockType, updated.BlockRestTimeSeconds = req.BlockIndex, nil, nil
+		for i := range blocks {
+			if i != target && blocks[i].BlockIndex != nil && *blocks[i].BlockIndex == *req.BlockIndex {
+				updated.BlockType, updated.BlockRestTimeSeconds = blocks[i].BlockType, blocks[i].BlockRestTimeSeconds
+				break
+			}
+		}
+	}
+
+	if req.BlockType == nil && req.BlockRestTimeSeconds == nil {
+		return blocks, nil
+	}
+	for i := range blocks {
+		sameBlock := updated.BlockIndex != nil && blocks[i].BlockIndex != nil && *blocks[i].BlockIndex == *updated.BlockIndex
+		if i != target && !sameBlock {
+			continue
+		}
+		if req.BlockType != nil {
+			blocks[i].BlockType = req.BlockType
+		}
+		if req.BlockRestTimeSeconds != nil {
+			blocks[i].BlockRestTimeSeconds = req.BlockRestTimeSeconds
+		}
+	}
+	return blocks, nil
+}
+
+// sameBlockConfig indica si el bloque de un ejercicio de rutina no cambió
+func sameBlockConfig(exercise models.RoutineExercise, block models.CreateRoutineExerciseRequest) bool {
+	return equalIntPtr(exercise.BlockIndex, block.BlockIndex) &&
+		equalIntPtr(exercise.BlockRestTimeSeconds, block.BlockRestTimeSeconds) &&
+		((exercise.BlockType == nil && block.BlockType == nil) ||
+			(exercise.BlockType != nil && block.BlockType != nil && *exercise.BlockType == *block.BlockType))
+}
+
+func equalIntPtr(a, b *int) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+// workoutBlockColumns son las columnas que se leen de un bloque.
+// Debe mantenerse en el mismo orden que scanWorkoutBlock.
+const workoutBlockColumns = `id, user_id, workout_day_id, block_type, order_index, rest_time_seconds, created_at, updated_at`
+
+// scanWorkoutBlock lee una fila seleccionada con workoutBlockColumns
+func scanWorkoutBlock(row interface{ Scan(...interface{}) error }, block *models.WorkoutBlock) error {
+	return row.Scan(
+		&block.ID,
+		&block.UserID,
+		&block.WorkoutDayID,
+		&block.BlockType,
+		&block.OrderIndex,
+		&block.RestTimeSeconds,
+		&block.CreatedAt,
+		&block.UpdatedAt,
+	)
+}
+
+// groupSetsIntoBlocks reparte las series (ya en orden de ejecución) entre los bloques del día
+func groupSetsIntoBlocks(blocks []models.WorkoutBlock, sets []models.Workout) []models.WorkoutBlock {
+	index := make(map[int]int)
+	for i := range blocks {
+		index[blocks[i].ID] = i
+		blocks[i].Workouts = []models.Workout{}
+		blocks[i].ExerciseIDs = []int{}
+	}
+	for _, set := range sets {
+		if set.BlockID == nil {
+			continue
+		}
+		i, ok := index[*set.BlockID]
+		if !ok {
+			continue
+		}
+		block := &blocks[i]
+		block.Workouts = append(block.Workouts, set)
+		seen := false
+		for _, id := range block.ExerciseIDs {
+			if id == set.ExerciseID {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			block.ExerciseIDs = append(block.ExerciseIDs, set.ExerciseID)
+		}
+	}
+	return blocks
+}
+
+// loadWorkoutBlocks obtiene los bloques de un día ordenados por order_index
+func loadWorkoutBlocks(q dbQuerier, userID string, workoutDayID int) ([]models.WorkoutBlock, error) {
+	rows, err := q.Query(`
+		SELECT `+workoutBlockColumns+`
+		FROM workout_blocks
+		WHERE user_id = $1 AND workout_day_id = $2
+		ORDER BY order_index ASC, id ASC
+	`, userID, workoutDayID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocks := []models.WorkoutBlock{}
+	for rows.Next() {
+		var block models.WorkoutBlock
+		if err := scanWorkoutBlock(rows, &block); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, rows.Err()
+}
+
+// assignWorkoutsToBlock reemplaza las series de un bloque por las indicadas, que deben ser del mismo día
+func assignWorkoutsToBlock(tx *sql.Tx, userID string, workoutDayID, blockID int, workoutIDs []int) error {
+	if _, err := tx.Exec("UPDATE workouts SET block_id = NULL WHERE block_id = $1 AND user_id = $2", blockID, userID); err != nil {
+		return err
+	}
+	if len(workoutIDs) == 0 {
+		return nil
+	}
+
+	unique := make(map[int]bool)
+	ids := []int64{}
+	for _, id := range workoutIDs {
+		if !unique[id] {
+			unique[id] = true
+			ids = append(ids, int64(id))
+		}
+	}
+
+	result, err := tx.Exec(
+		"UPDATE workouts SET block_id = $1 WHERE id = ANY($2) AND user_id = $3 AND workout_day_id = $4 AND deleted_at IS NULL",
+		blockID, pq.Array(ids), userID, workoutDayID,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if int(affected) != len(ids) {
+		return errInvalidBlockWorkouts
+	}
+	return nil
+}
+
+// writeWorkoutBlockError responde con el status correspondiente a un error de bloques
+func writeWorkoutBlockError(w http.ResponseWriter, err error) {
+	switch err {
+	case errWorkoutBlockNotFound:
+		http.Error(w, "Bloque no encontrado", http.StatusNotFound)
+	case errInvalidBlockWorkouts, errInvalidBlockType, errInvalidBlockRest:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		writeWorkoutDayError(w, err)
+	}
+}
+
+// loadWorkoutBlock obtiene un bloque del usuario con sus series
+func loadWorkoutBlock(q dbQuerier, userID string, blockID int, loc *time.Location) (*models.WorkoutBlock, error) {
+	var block models.WorkoutBlock
+	err := scanWorkoutBlock(q.QueryRow(
+		`SELECT `+workoutBlockColumns+` FROM workout_blocks WHERE id = $1 AND user_id = $2`, blockID, userID,
+	), &block)
+	if err == sql.ErrNoRows {
+		return nil, errWorkoutBlockNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	block.CreatedAt = convertToUserTime(block.CreatedAt, loc)
+	block.UpdatedAt = convertToUserTime(block.UpdatedAt, loc)
+
+	rows, err := q.Query(`
+		SELECT `+workoutColumns+`
+		FROM workouts w
+		JOIN exercises e ON w.exercise_id = e.id
+		WHERE w.block_id = $1 AND w.user_id = $2 AND w.deleted_at IS NULL
+		ORDER BY w.created_at ASC, w.id ASC
+	`, blockID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sets []models.Workout
+	for rows.Next() {
+		var workout models.Workout
+		if err := scanWorkout(rows, &workout); err != nil {
+			return nil, err
+		}
+		workout.CreatedAt = convertToUserTime(workout.CreatedAt, loc)
+		sets = append(sets, workout)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &groupSetsIntoBlocks([]models.WorkoutBlock{block}, sets)[0], nil
+}
+
+// CreateWorkoutBlockHandler crea una superserie o circuito en un día de entrenamiento,
+// opcionalmente agrupando series ya registradas
+func CreateWorkoutBlockHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	dayID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateWorkoutBlockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if req.BlockType == "" {
+		req.BlockType = models.BlockTypeSuperset
+	}
+	if !isValidBlockType(req.BlockType) {
+		http.Error(w, errInvalidBlockType.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.RestTimeSeconds != nil && (*req.RestTimeSeconds < 0 || *req.RestTimeSeconds > 3600) {
+		http.Error(w, errInvalidBlockRest.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.OrderIndex != nil && *req.OrderIndex < 0 {
+		http.Error(w, "order_index no puede ser negativo", http.StatusBadRequest)
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM workout_days WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL)", dayID, userID).Scan(&exists)
+	if err != nil {
+		http.Error(w, "Error verificando día de entrenamiento", http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		http.Error(w, "Día de entrenamiento no encontrado", http.StatusNotFound)
+		return
+	}
+
+	// Por defecto el bloque va después de los existentes
+	var blockID int
+	err = tx.QueryRow(`
+		INSERT INTO workout_blocks (user_id, workout_day_id, block_type, order_index, rest_time_seconds)
+		VALUES ($1, $2, $3, COALESCE($4, (SELECT COALESCE(MAX(order_index) + 1, 0) FROM workout_blocks WHERE workout_day_id = $2)), $5)
+		RETURNING id
+	`, userID, dayID, req.BlockType, req.OrderIndex, req.RestTimeSeconds).Scan(&blockID)
+	if err != nil {
+		fmt.Printf("Error creando bloque: %v\n", err)
+		http.Error(w, "Error creando bloque", http.StatusInternalServerError)
+		return
+	}
+
+	if err := assignWorkoutsToBlock(tx, userID, dayID, blockID, req.WorkoutIDs); err != nil {
+		writeWorkoutBlockError(w, err)
+		return
+	}
+
+	block, err := loadWorkoutBlock(tx, userID, blockID, getUserLocation(r, userID))
+	if err != nil {
+		writeWorkoutBlockError(w, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, "Error confirmando transacción", http.StatusInternalServerError)
+		return
+	}
+
+	convertWorkoutBlockWeights(block, getUserWeightUnit(userID))
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(block)
+}
+
+// UpdateWorkoutBlockHandler actualiza tipo, orden, descanso o series de un bloque
+func UpdateWorkoutBlockHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	blockID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.UpdateWorkoutBlockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if req.BlockType != nil && !isValidBlockType(*req.BlockType) {
+		http.Error(w, errInvalidBlockType.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.RestTimeSeconds != nil && (*req.RestTimeSeconds < 0 || *req.RestTimeSeconds > 3600) {
+		http.Error(w, errInvalidBlockRest.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.OrderIndex != nil && *req.OrderIndex < 0 {
+		http.Error(w, "order_index no puede ser negativo", http.StatusBadRequest)
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	var dayID int
+	err = tx.QueryRow(`
+		UPDATE workout_blocks
+		SET block_type = COALESCE($1, block_type),
+			order_index = COALESCE($2, order_index),
+			rest_time_seconds = COALESCE($3, rest_time_seconds),
+			updated_at = NOW()
+		WHERE id = $4 AND user_id = $5
+		RETURNING workout_day_id
+	`, req.BlockType, req.OrderIndex, req.RestTimeSeconds, blockID, userID).Scan(&dayID)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Bloque no encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		fmt.Printf("Error actualizando bloque: %v\n", err)
+		http.Error(w, "Error actualizando bloque", http.StatusInternalServerError)
+		return
+	}
+
+	if req.WorkoutIDs != nil {
+		if err := assignWorkoutsToBlock(tx, userID, dayID, blockID, *req.WorkoutIDs); err != nil {
+			writeWorkoutBlockError(w, err)
+			return
+		}
+	}
+
+	block, err := loadWorkoutBlock(tx, userID, blockID, getUserLocation(r, userID))
+	if err != nil {
+		writeWorkoutBlockError(w, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, "Error confirmando transacción", http.StatusInternalServerError)
+		return
+	}
+
+	convertWorkoutBlockWeights(block, getUserWeightUnit(userID))
+	json.NewEncoder(w).Encode(block)
+}
+
+// DeleteWorkoutBlockHandler elimina un bloque; sus series quedan en el día como series sueltas
+func DeleteWorkoutBlockHandler(w http.ResponseWriter, r *http.Request) {
+	blockID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	// Las series se desvinculan por ON DELETE SET NULL
+	result, err := database.DB.Exec("DELETE FROM workout_blocks WHERE id = $1 AND user_id = $2", blockID, userID)
+	if err != nil {
+		http.Error(w, "Error eliminando bloque", http.StatusInternalServerError)
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		http.Error(w, "Bloque no encontrado", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+func TestGroupSetsIntoBlocks(t *testing.T) {
+	blockA, blockB, unknown := 1, 2, 99
+	blocks := []models.WorkoutBlock{
+		{ID: blockA, BlockType: models.BlockTypeSuperset},
+		{ID: blockB, BlockType: models.BlockTypeCircuit},
+	}
+	sets := []models.Workout{
+		{ID: 10, ExerciseID: 100, BlockID: &blockA},
+		{ID: 11, ExerciseID: 200, BlockID: &blockA},
+		{ID: 12, ExerciseID: 100, BlockID: &blockA},
+		{ID: 13, ExerciseID: 300},
+		{ID: 14, ExerciseID: 400, BlockID: &unknown},
+	}
+
+	result := groupSetsIntoBlocks(blocks, sets)
+
+	if len(result[0].Workouts) != 3 {
+		t.Fatalf("el bloque A debería tener 3 series, tiene %d", len(result[0].Workouts))
+	}
+	if result[0].Workouts[1].ID != 11 {
+		t.Errorf("las series deberían mantener el orden de ejecución, segunda = %d", result[0].Workouts[1].ID)
+	}
+	if len(result[0].ExerciseIDs) != 2 || result[0].ExerciseIDs[0] != 100 || result[0].ExerciseIDs[1] != 200 {
+		t.Errorf("ejercicios del bloque A incorrectos: %v", result[0].ExerciseIDs)
+	}
+	if result[1].Workouts == nil || len(result[1].Workouts) != 0 {
+		t.Errorf("el bloque B debería tener una lista vacía de series")
+	}
+}
+
+func TestValidateRoutineBlocks(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	strPtr := func(v string) *string { return &v }
+
+	t.Run("completa tipo y descanso del bloque", func(t *testing.T) {
+		exercises := []models.CreateRoutineExerciseRequest{
+			{ExerciseID: 1, BlockIndex: intPtr(1), BlockRestTimeSeconds: intPtr(90)},
+			{ExerciseID: 2, BlockIndex: intPtr(1)},
+			{ExerciseID: 3},
+		}
+		if err := validateRoutineBlocks(exercises); err != nil {
+			t.Fatalf("error inesperado: %v", err)
+		}
+		if exercises[1].BlockType == nil || *exercises[1].BlockType != models.BlockTypeSuperset {
+			t.Errorf("el tipo por defecto debería ser superset")
+		}
+		if exercises[1].BlockRestTimeSeconds == nil || *exercises[1].BlockRestTimeSeconds != 90 {
+			t.Errorf("el descanso debería heredarse del bloque")
+		}
+	})
+
+	cases := map[string][]models.CreateRoutineExerciseRequest{
+		"tipo inválido": {
+			{ExerciseID: 1, BlockIndex: intPtr(1), BlockType: strPtr("giant")},
+		},
+		"tipos distintos en el mismo bloque": {
+			{ExerciseID: 1, BlockIndex: intPtr(1), BlockType: strPtr(models.BlockTypeSuperset)},
+			{ExerciseID: 2, BlockIndex: intPtr(1), BlockType: strPtr(models.BlockTypeCircuit)},
+		},
+		"descansos distintos en el mismo bloque": {
+			{ExerciseID: 1, BlockIndex: intPtr(1), BlockRestTimeSeconds: intPtr(60)},
+			{ExerciseID: 2, BlockIndex: intPtr(1), BlockRestTimeSeconds: intPtr(90)},
+		},
+		"tipo sin bloque": {
+			{ExerciseID: 1, BlockType: strPtr(models.BlockTypeCircuit)},
+		},
+		"descanso fuera de rango": {
+			{ExerciseID: 1, BlockIndex: intPtr(1), BlockRestTimeSeconds: intPtr(-5)},
+		},
+	}
+	for name, exercises := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := validateRoutineBlocks(exercises); err == nil {
+				t.Errorf("se esperaba un error")
+			}
+		})
+	}
+}
+
+func TestRoutineBlocksAfterUpdate(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	strPtr := func(v string) *string { return &v }
+	circuit := strPtr(models.BlockTypeCircuit)
+	exercises := []models.RoutineExercise{
+		{ID: 1, ExerciseID: 10, BlockIndex: intPtr(1), BlockType: circuit, BlockRestTimeSeconds: intPtr(120)},
+		{ID: 2, ExerciseID: 20, BlockIndex: intPtr(1), BlockType: circuit, BlockRestTimeSeconds: intPtr(120)},
+		{ID: 3, ExerciseID: 30},
+	}
+
+	t.Run("sumarse a un bloque hereda su tipo y descanso", func(t *testing.T) {
+		blocks, err := routineBlocksAfterUpdate(exercises, 3, &models.UpdateRoutineExerciseRequest{BlockIndex: intPtr(1)})
+		if err == nil {
+			err = validateRoutineBlocks(blocks)
+		}
+		if err != nil {
+			t.Fatalf("error inesperado: %v", err)
+		}
+		if *blocks[2].BlockType != models.BlockTypeCircuit || *blocks[2].BlockRestTimeSeconds != 120 {
+			t.Errorf("el ejercicio debería tomar la configuración del bloque: %+v", blocks[2])
+		}
+		if !sameBlockConfig(exercises[0], blocks[0]) || sameBlockConfig(exercises[2], blocks[2]) {
+			t.Errorf("solo debería cambiar el ejercicio que se sumó al bloque")
+		}
+	})
+
+	t.Run("el tipo y el descanso se cambian para todo el bloque", func(t *testing.T) {
+		req := &models.UpdateRoutineExerciseRequest{BlockType: strPtr(models.BlockTypeSuperset), BlockRestTimeSeconds: intPtr(60)}
+		blocks, err := routineBlocksAfterUpdate(exercises, 2, req)
+		if err == nil {
+			err = validateRoutineBlocks(blocks)
+		}
+		if err != nil {
+			t.Fatalf("error inesperado: %v", err)
+		}
+		for _, i := range []int{0, 1} {
+			if *blocks[i].BlockType != models.BlockTypeSuperset || *blocks[i].BlockRestTimeSeconds != 60 {
+				t.Errorf("ejercicio %d sin la nueva configuración: %+v", i+1, blocks[i])
+			}
+		}
+		if blocks[2].BlockIndex != nil {
+			t.Errorf("el ejercicio fuera del bloque no debería cambiar")
+		}
+	})
+
+	t.Run("salir del bloque", func(t *testing.T) {
+		blocks, err := routineBlocksAfterUpdate(exercises, 1, &models.UpdateRoutineExerciseRequest{LeaveBlock: true})
+		if err != nil {
+			t.Fatalf("error inesperado: %v", err)
+		}
+		if blocks[0].BlockIndex != nil || blocks[0].BlockType != nil || blocks[0].BlockRestTimeSeconds != nil {
+			t.Errorf("el ejercicio debería quedar fuera del bloque: %+v", blocks[0])
+		}
+		if exercises[0].BlockIndex == nil {
+			t.Errorf("no debería modificar los ejercicios originales")
+		}
+	})
+
+	invalid := map[string]struct {
+		id  int
+		req *models.UpdateRoutineExerciseRequest
+	}{
+		"tipo sin bloque":           {3, &models.UpdateRoutineExerciseRequest{BlockType: circuit}},
+		"tipo inválido":             {1, &models.UpdateRoutineExerciseRequest{BlockType: strPtr("giant")}},
+		"bloque negativo":           {3, &models.UpdateRoutineExerciseRequest{BlockIndex: intPtr(-1)}},
+		"salir y cambiar de bloque": {1, &models.UpdateRoutineExerciseRequest{LeaveBlock: true, BlockIndex: intPtr(2)}},
+		"descanso fuera de rango":   {1, &models.UpdateRoutineExerciseRequest{BlockRestTimeSeconds: intPtr(4000)}},
+	}
+	for name, tc := range invalid {
+		t.Run(name, func(t *testing.T) {
+			blocks, err := routineBlocksAfterUpdate(exercises, tc.id, tc.req)
+			if err == nil {
+				err = validateRoutineBlocks(blocks)
+			}
+			if err == nil {
+				t.Errorf("se esperaba un error")
+			}
+		})
+	}
+}
@@ -0,0 +1,522 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+// Límites para registrar entrenamientos en días distintos de hoy
+const (
+	maxWorkoutDaysInPast   = 365
+	maxWorkoutDaysInFuture = 7
+)
+
+// defaultWorkoutDayName es el nombre con el que se crean los días de entrenamiento
+const defaultWorkoutDayName = "Entrenamiento del día"
+
+// maxDayRating es la cantidad máxima de estrellas de esfuerzo y ánimo de un día
+const maxDayRating = 3
+
+var (
+	errWorkoutDayNotFound    = errors.New("día de entrenamiento no encontrado")
+	errInvalidWorkoutDate    = errors.New("fecha inválida, usar formato YYYY-MM-DD")
+	errWorkoutDateOutOfRange = fmt.Errorf("la fecha debe estar entre %d días atrás y %d días adelante", maxWorkoutDaysInPast, maxWorkoutDaysInFuture)
+)
+
+// dbQuerier permite usar tanto *sql.DB como *sql.Tx en los helpers
+type dbQuerier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// getOrCreateWorkoutDay devuelve el día de entrenamiento del usuario para la fecha, creándolo si no existe
+func getOrCreateWorkoutDay(q dbQuerier, userID, date string) (int, error) {
+	var workoutDayID int
+	err := q.QueryRow(`SELECT id FROM workout_days WHERE user_id = $1 AND date = $2 AND deleted_at IS NULL`, userID, date).Scan(&workoutDayID)
+	if err == nil {
+		return workoutDayID, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	createDayQuery := `
+		INSERT INTO workout_days (user_id, date, name, effort, mood)
+		VALUES ($1, $2, $3, 0, 0)
+		RETURNING id
+	`
+	if err := q.QueryRow(createDayQuery, userID, date, defaultWorkoutDayName).Scan(&workoutDayID); err != nil {
+		return 0, err
+	}
+	fmt.Printf("Día de entrenamiento creado con ID: %d\n", workoutDayID)
+	return workoutDayID, nil
+}
+
+// parseWorkoutDate valida una fecha YYYY-MM-DD contra los límites permitidos respecto de hoy
+func parseWorkoutDate(date string, loc *time.Location) (string, error) {
+	parsed, err := time.ParseInLocation("2006-01-02", date, loc)
+	if err != nil {
+		return "", errInvalidWorkoutDate
+	}
+
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if parsed.Before(today.AddDate(0, 0, -maxWorkoutDaysInPast)) || parsed.After(today.AddDate(0, 0, maxWorkoutDaysInFuture)) {
+		return "", errWorkoutDateOutOfRange
+	}
+
+	return parsed.Format("2006-01-02"), nil
+}
+
+// resolveWorkoutDayID determina a qué día de entrenamiento se asocia una serie:
+// el workout_day_id explícito (que debe pertenecer al usuario), la fecha indicada o el día de hoy
+func resolveWorkoutDayID(q dbQuerier, userID string, loc *time.Location, date *string, workoutDayID *int) (int, error) {
+	if workoutDayID != nil {
+		var id int
+		err := q.QueryRow(`SELECT id FROM workout_days WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`, *workoutDayID, userID).Scan(&id)
+		if err == sql.ErrNoRows {
+			return 0, errWorkoutDayNotFound
+		}
+		return id, err
+	}
+
+	day := time.Now().In(loc).Format("2006-01-02")
+	if date != nil && *date != "" {
+		parsed, err := parseWorkoutDate(*date, loc)
+		if err != nil {
+			return 0, err
+		}
+		day = parsed
+	}
+
+	return getOrCreateWorkoutDay(q, userID, day)
+}
+
+// writeWorkoutDayError responde con el status correspondiente a un error de resolveWorkoutDayID
+func writeWorkoutDayError(w http.ResponseWriter, err error) {
+	switch err {
+	case errWorkoutDayNotFound:
+		http.Error(w, "Día de entrenamiento no encontrado", http.StatusNotFound)
+	case errInvalidWorkoutDate, errWorkoutDateOutOfRange:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		fmt.Printf("Error obteniendo día de entrenamiento: %v\n", err)
+		http.Error(w, "Error obteniendo día de entrenamiento", http.StatusInternalServerError)
+	}
+}
+
+// workoutDayColumns son las columnas que se leen de un día de entrenamiento.
+// Debe mantenerse en el mismo orden que scanWorkoutDay.
+const workoutDayColumns = `id, user_id, date, name, effort, mood, notes, routine_id, created_at, updated_at`
+
+// scanWorkoutDay lee una fila seleccionada con workoutDayColumns
+func scanWorkoutDay(row interface{ Scan(...interface{}) error }, day *models.WorkoutDay) error {
+	return row.Scan(
+		&day.ID,
+		&day.UserID,
+		&day.Date,
+		&day.Name,
+		&day.Effort,
+		&day.Mood,
+		&day.Notes,
+		&day.RoutineID,
+		&day.CreatedAt,
+		&day.UpdatedAt,
+	)
+}
+
+// appendWorkoutToDay agrega la serie al día y al grupo de su ejercicio, creando el grupo
+// si es la primera serie del ejercicio. Las series deben agregarse en el orden en que se hicieron.
+func appendWorkoutToDay(day *models.WorkoutDayWithExercises, workout models.Workout) {
+	i := 0
+	for i < len(day.ExerciseGroups) && day.ExerciseGroups[i].ExerciseID != workout.ExerciseID {
+		i++
+	}
+	if i == len(day.ExerciseGroups) {
+		day.ExerciseGroups = append(day.ExerciseGroups, models.ExerciseGroup{
+			ExerciseID:   workout.ExerciseID,
+			ExerciseName: workout.ExerciseName,
+			Workouts:     []models.Workout{},
+		})
+	}
+	day.ExerciseGroups[i].Workouts = append(day.ExerciseGroups[i].Workouts, workout)
+	day.Sets = append(day.Sets, workout)
+	day.TotalWorkouts++
+}
+
+// loadWorkoutDayWithExercises obtiene un día de entrenamiento del usuario con sus series agrupadas por ejercicio.
+// Los grupos aparecen en el orden en que se hizo la primera serie de cada ejercicio; Sets tiene todas las
+// series en el orden en que se hicieron y Blocks las superseries y circuitos. Si el día se inició
+// desde una rutina incluye el plan con lo planificado vs. lo realizado.
+func loadWorkoutDayWithExercises(q dbQuerier, userID string, dayID int, loc *time.Location) (*models.WorkoutDayWithExercises, error) {
+	var day models.WorkoutDayWithExercises
+	query := `SELECT ` + workoutDayColumns + ` FROM workout_days WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`
+	if err := scanWorkoutDay(q.QueryRow(query, dayID, userID), &day.WorkoutDay); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errWorkoutDayNotFound
+		}
+		return nil, err
+	}
+	day.WorkoutDay.CreatedAt = convertToUserTime(day.WorkoutDay.CreatedAt, loc)
+	day.WorkoutDay.UpdatedAt = convertToUserTime(day.WorkoutDay.UpdatedAt, loc)
+
+	rows, err := q.Query(`
+		SELECT `+workoutColumns+`
+		FROM workouts w
+		JOIN exercises e ON w.exercise_id = e.id
+		WHERE w.workout_day_id = $1 AND w.user_id = $2 AND w.deleted_at IS NULL
+		ORDER BY w.created_at ASC, w.id ASC
+	`, dayID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	day.ExerciseGroups = []models.ExerciseGroup{}
+	day.Sets = []models.Workout{}
+	for rows.Next() {
+		var workout models.Workout
+		if err := scanWorkout(rows, &workout); err != nil {
+			return nil, err
+		}
+		workout.CreatedAt = convertToUserTime(workout.CreatedAt, loc)
+		appendWorkoutToDay(&day, workout)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	blocks, err := loadWorkoutBlocks(q, userID, dayID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range blocks {
+		blocks[i].CreatedAt = convertToUserTime(blocks[i].CreatedAt, loc)
+		blocks[i].UpdatedAt = convertToUserTime(blocks[i].UpdatedAt, loc)
+	}
+	day.Blocks = groupSetsIntoBlocks(blocks, day.Sets)
+
+	plan, err := buildWorkoutDayPlan(q, &day)
+	if err != nil {
+		return nil, err
+	}
+	day.Plan = plan
+
+	return &day, nil
+}
+
+// GetWorkoutDayHandler obtiene un día de entrenamiento con sus series agrupadas por ejercicio
+func GetWorkoutDayHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	dayID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	day, err := loadWorkoutDayWithExercises(database.DB, userID, dayID, getUserLocation(r, userID))
+	if err != nil {
+		writeWorkoutDayError(w, err)
+		return
+	}
+
+	convertWorkoutDayWeights(day, getUserWeightUnit(userID))
+	json.NewEncoder(w).Encode(day)
+}
+
+// loadWorkoutDaysPage carga una página de días del usuario (los más recientes primero) con sus series
+// agrupadas por ejercicio. Incluye los días sin series, por ejemplo una rutina recién iniciada.
+func loadWorkoutDaysPage(q dbQuerier, userID string, cursor *historyCursor, limit int, loc *time.Location) (*models.WorkoutDaysPage, error) {
+	query := `SELECT ` + workoutDayColumns + `, date::text FROM workout_days WHERE user_id = $1 AND deleted_at IS NULL`
+	args := []interface{}{userID}
+	if cursor != nil {
+		args = append(args, cursor.Date)
+		query += fmt.Sprintf(" AND date < $%d", len(args))
+	}
+	args = append(args, limit+1)
+	query += fmt.Sprintf(" ORDER BY date DESC LIMIT $%d", len(args))
+
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := &models.WorkoutDaysPage{Days: []models.WorkoutDayWithExercises{}}
+	var dates []string
+	for rows.Next() {
+		var day models.WorkoutDayWithExercises
+		var date string
+		d := &day.WorkoutDay
+		err := rows.Scan(&d.ID, &d.UserID, &d.Date, &d.Name, &d.Effort, &d.Mood, &d.Notes, &d.RoutineID, &d.CreatedAt, &d.UpdatedAt, &date)
+		if err != nil {
+			return nil, err
+		}
+		d.CreatedAt = convertToUserTime(d.CreatedAt, loc)
+		d.UpdatedAt = convertToUserTime(d.UpdatedAt, loc)
+		day.ExerciseGroups = []models.ExerciseGroup{}
+		day.Sets = []models.Workout{}
+		day.Blocks = []models.WorkoutBlock{}
+		page.Days = append(page.Days, day)
+		dates = append(dates, date)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Days) > limit {
+		page.Days = page.Days[:limit]
+		next := encodeHistoryCursor(historyCursor{Date: dates[limit-1]})
+		page.NextCursor = &next
+	}
+	if len(page.Days) == 0 {
+		return page, nil
+	}
+
+	dayIndex := make(map[int]int, len(page.Days))
+	dayIDs := make([]int, len(page.Days))
+	for i, day := range page.Days {
+		dayIndex[day.WorkoutDay.ID] = i
+		dayIDs[i] = day.WorkoutDay.ID
+	}
+
+	setRows, err := q.Query(`
+		SELECT `+workoutColumns+`
+		FROM workouts w
+		JOIN exercises e ON w.exercise_id = e.id
+		WHERE w.user_id = $1 AND w.workout_day_id = ANY($2) AND w.deleted_at IS NULL
+		ORDER BY w.created_at ASC, w.id ASC
+	`, userID, pq.Array(dayIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer setRows.Close()
+
+	for setRows.Next() {
+		var workout models.Workout
+		if err := scanWorkout(setRows, &workout); err != nil {
+			return nil, err
+		}
+		workout.CreatedAt = convertToUserTime(workout.CreatedAt, loc)
+		appendWorkoutToDay(&page.Days[dayIndex[workout.WorkoutDayID]], workout)
+	}
+	if err := setRows.Err(); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// GetWorkoutDaysPageHandler lista los días de entrenamiento del usuario con sus series, paginados
+// con limit y cursor (el next_cursor de la página anterior)
+func GetWorkoutDaysPageHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var cursor *historyCursor
+	if value := r.URL.Query().Get("cursor"); value != "" {
+		decoded, err := decodeHistoryCursor(value)
+		if err != nil || decoded.WorkoutID > 0 {
+			http.Error(w, errInvalidHistoryCursor.Error(), http.StatusBadRequest)
+			return
+		}
+		cursor = &decoded
+	}
+	limit, err := parseLimit(r, defaultHistoryDays, maxHistoryDays)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := loadWorkoutDaysPage(database.DB, userID, cursor, limit, getUserLocation(r, userID))
+	if err != nil {
+		fmt.Printf("Error consultando días de entrenamiento: %v\n", err)
+		http.Error(w, "Error consultando días de entrenamiento", http.StatusInternalServerError)
+		return
+	}
+
+	page.WeightUnit = getUserWeightUnit(userID)
+	for i := range page.Days {
+		convertWorkoutDayWeights(&page.Days[i], page.WeightUnit)
+	}
+	json.NewEncoder(w).Encode(page)
+}
+
+// validateUpdateWorkoutDayRequest valida los campos enviados para actualizar un día
+func validateUpdateWorkoutDayRequest(req *models.UpdateWorkoutDayRequest) error {
+	if req.Name == nil && req.Effort == nil && req.Mood == nil && req.Notes == nil {
+		return errors.New("no hay campos para actualizar")
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return errors.New("el nombre no puede estar vacío")
+		}
+		if len(name) > 100 {
+			return errors.New("el nombre no puede tener más de 100 caracteres")
+		}
+		req.Name = &name
+	}
+	// Esfuerzo y ánimo van de 0 (sin cargar) a maxDayRating estrellas, como en la tabla
+	if req.Effort != nil && (*req.Effort < 0 || *req.Effort > maxDayRating) {
+		return fmt.Errorf("el esfuerzo debe estar entre 0 y %d", maxDayRating)
+	}
+	if req.Mood != nil && (*req.Mood < 0 || *req.Mood > maxDayRating) {
+		return fmt.Errorf("el ánimo debe estar entre 0 y %d", maxDayRating)
+	}
+	if req.Notes != nil && len(*req.Notes) > 2000 {
+		return errors.New("las notas no pueden tener más de 2000 caracteres")
+	}
+	return nil
+}
+
+// UpdateWorkoutDayHandler actualiza nombre, esfuerzo, ánimo y notas de un día de entrenamiento.
+// Solo se modifican los campos enviados; notas vacías borran las notas existentes.
+func UpdateWorkoutDayHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	dayID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.UpdateWorkoutDayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validateUpdateWorkoutDayRequest(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Se distingue "no enviar notas" (no se tocan) de "notas vacías" (se borran)
+	updateNotes := req.Notes != nil
+	var notes *string
+	if updateNotes && strings.TrimSpace(*req.Notes) != "" {
+		notes = req.Notes
+	}
+
+	query := `
+		UPDATE workout_days
+		SET name = COALESCE($1, name),
+			effort = COALESCE($2, effort),
+			mood = COALESCE($3, mood),
+			notes = CASE WHEN $4 THEN $5 ELSE notes END,
+			updated_at = NOW()
+		WHERE id = $6 AND user_id = $7 AND deleted_at IS NULL
+		RETURNING ` + workoutDayColumns
+
+	var day models.WorkoutDay
+	err = scanWorkoutDay(database.DB.QueryRow(query, req.Name, req.Effort, req.Mood, updateNotes, notes, dayID, userID), &day)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Día de entrenamiento no encontrado", http.StatusNotFound)
+		} else {
+			fmt.Printf("Error actualizando día de entrenamiento: %v\n", err)
+			http.Error(w, "Error actualizando el día de entrenamiento", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	loc := getUserLocation(r, userID)
+	// El esfuerzo y el ánimo alimentan el análisis de recuperación
+	if req.Effort != nil || req.Mood != nil {
+		checkDeloadRecommendation(userID, loc)
+	}
+
+	day.CreatedAt = convertToUserTime(day.CreatedAt, loc)
+	day.UpdatedAt = convertToUserTime(day.UpdatedAt, loc)
+	json.NewEncoder(w).Encode(day)
+}
+
+// DeleteWorkoutDayHandler manda a la papelera un día de entrenamiento junto con sus series.
+// Los kudos se conservan para poder restaurarlo; las notificaciones de kudos se eliminan.
+func DeleteWorkoutDayHandler(w http.ResponseWriter, r *http.Request) {
+	dayID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	// Bloquear el día para que no se agreguen series mientras se elimina
+	var id int
+	err = tx.QueryRow("SELECT id FROM workout_days WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL FOR UPDATE", dayID, userID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Día de entrenamiento no encontrado", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error verificando día de entrenamiento", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// El día y sus series quedan con la misma marca (NOW() es fija en la transacción),
+	// así al restaurar el día vuelven solo las series que se borraron con él
+	deletes := []struct {
+		query string
+		args  []interface{}
+	}{
+		{"DELETE FROM notifications WHERE user_id = $1 AND type = 'kudos' AND data::jsonb->>'workout_day_id' = $2", []interface{}{userID, strconv.Itoa(dayID)}},
+		{"DELETE FROM live_sessions WHERE workout_day_id = $1 AND user_id = $2", []interface{}{dayID, userID}},
+		{"UPDATE workouts SET deleted_at = NOW() WHERE workout_day_id = $1 AND user_id = $2 AND deleted_at IS NULL", []interface{}{dayID, userID}},
+		{"UPDATE workout_days SET deleted_at = NOW() WHERE id = $1 AND user_id = $2", []interface{}{dayID, userID}},
+	}
+	for _, d := range deletes {
+		if _, err := tx.Exec(d.query, d.args...); err != nil {
+			fmt.Printf("Error eliminando día de entrenamiento %d: %v\n", dayID, err)
+			http.Error(w, "Error eliminando día de entrenamiento", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, "Error confirmando transacción", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,75 @@
+//go:build integration
+
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/testutils"
+)
+
+// TestSupabaseLoadWorkoutDaysPage prueba que los días se paginan del más reciente al más antiguo
+// con sus series agrupadas por ejercicio, incluidos los días sin series
+func TestSupabaseLoadWorkoutDaysPage(t *testing.T) {
+	testutils.SetupTestDatabase(t)
+
+	var exerciseID int
+	if err := database.DB.QueryRow("SELECT id FROM exercises ORDER BY id LIMIT 1").Scan(&exerciseID); err != nil {
+		t.Skipf("No hay ejercicios en la base de prueba: %v", err)
+	}
+
+	testUserID := testutils.GetTestUserID(t)
+	testutils.CreateTestUserInDB(t, testUserID)
+	t.Cleanup(func() {
+		testutils.CleanupTestUser(t, testUserID)
+	})
+
+	dayIDs := make(map[string]int)
+	for _, date := range []string{"2024-06-01", "2024-06-02", "2024-06-03"} {
+		var dayID int
+		err := database.DB.QueryRow(`
+			INSERT INTO workout_days (user_id, date, name) VALUES ($1, $2, 'Test') RETURNING id
+		`, testUserID, date).Scan(&dayID)
+		if err != nil {
+			t.Fatalf("Error creando día de entrenamiento: %v", err)
+		}
+		dayIDs[date] = dayID
+	}
+	for set := 1; set <= 2; set++ {
+		_, err := database.DB.Exec(`
+			INSERT INTO workouts (user_id, workout_day_id, exercise_id, weight, reps, set) VALUES ($1, $2, $3, 50, 10, $4)
+		`, testUserID, dayIDs["2024-06-03"], exerciseID, set)
+		if err != nil {
+			t.Fatalf("Error creando serie: %v", err)
+		}
+	}
+
+	page, err := loadWorkoutDaysPage(database.DB, testUserID, nil, 2, time.UTC)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if len(page.Days) != 2 || page.NextCursor == nil {
+		t.Fatalf("la primera página debería tener 2 días y cursor: %+v", page)
+	}
+	first := page.Days[0]
+	if first.WorkoutDay.ID != dayIDs["2024-06-03"] || len(first.ExerciseGroups) != 1 || len(first.ExerciseGroups[0].Workouts) != 2 {
+		t.Errorf("el día más reciente debería traer sus 2 series agrupadas: %+v", first)
+	}
+	if page.Days[1].WorkoutDay.ID != dayIDs["2024-06-02"] || len(page.Days[1].ExerciseGroups) != 0 {
+		t.Errorf("el día sin series debería listarse vacío: %+v", page.Days[1])
+	}
+
+	cursor, err := decodeHistoryCursor(*page.NextCursor)
+	if err != nil {
+		t.Fatalf("cursor inválido: %v", err)
+	}
+	page, err = loadWorkoutDaysPage(database.DB, testUserID, &cursor, 2, time.UTC)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if len(page.Days) != 1 || page.Days[0].WorkoutDay.ID != dayIDs["2024-06-01"] || page.NextCursor != nil {
+		t.Errorf("la segunda página debería tener solo el día más antiguo y ningún cursor: %+v", page)
+	}
+}
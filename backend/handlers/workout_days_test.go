@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+func TestParseWorkoutDate(t *testing.T) {
+	loc := time.UTC
+	today := time.Now().In(loc)
+
+	tests := []struct {
+		name    string
+		date    string
+		wantErr error
+	}{
+		{"hoy", today.Format("2006-01-02"), nil},
+		{"ayer", today.AddDate(0, 0, -1).Format("2006-01-02"), nil},
+		{"límite pasado", today.AddDate(0, 0, -maxWorkoutDaysInPast).Format("2006-01-02"), nil},
+		{"demasiado atrás", today.AddDate(0, 0, -maxWorkoutDaysInPast-1).Format("2006-01-02"), errWorkoutDateOutOfRange},
+		{"demasiado adelante", today.AddDate(0, 0, maxWorkoutDaysInFuture+1).Format("2006-01-02"), errWorkoutDateOutOfRange},
+		{"formato inválido", "10/03/2024", errInvalidWorkoutDate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseWorkoutDate(tt.date, loc)
+			if err != tt.wantErr {
+				t.Fatalf("error incorrecto: got %v want %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.date {
+				t.Errorf("fecha incorrecta: got %v want %v", got, tt.date)
+			}
+		})
+	}
+}
+
+func TestValidateUpdateWorkoutDayRequest(t *testing.T) {
+	str := func(s string) *string { return &s }
+	num := func(n int) *int { return &n }
+
+	tests := []struct {
+		name    string
+		req     models.UpdateWorkoutDayRequest
+		wantErr bool
+	}{
+		{"sin campos", models.UpdateWorkoutDayRequest{}, true},
+		{"solo esfuerzo", models.UpdateWorkoutDayRequest{Effort: num(3)}, false},
+		{"esfuerzo fuera de rango", models.UpdateWorkoutDayRequest{Effort: num(4)}, true},
+		{"ánimo fuera de rango", models.UpdateWorkoutDayRequest{Mood: num(7)}, true},
+		{"ánimo negativo", models.UpdateWorkoutDayRequest{Mood: num(-1)}, true},
+		{"nombre vacío", models.UpdateWorkoutDayRequest{Name: str("   ")}, true},
+		{"notas vacías borran", models.UpdateWorkoutDayRequest{Notes: str("")}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateUpdateWorkoutDayRequest(&tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error incorrecto: got %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+func TestValidateWorkoutRequest(t *testing.T) {
+	empty := ""
+
+	tests := []struct {
+		name    string
+		req     models.CreateWorkoutRequest
+		wantErr bool
+	}{
+		{"sin campos opcionales", models.CreateWorkoutRequest{ExerciseID: 1}, false},
+		{"reps cero", models.CreateWorkoutRequest{ExerciseID: 1, Reps: intPtr(0)}, true},
+		{"peso negativo", models.CreateWorkoutRequest{ExerciseID: 1, Weight: floatPtr(-5)}, true},
+
+		// Crear, editar y el lote comparten la validación: set va de 1 a maxSetNumber
+		{"serie máxima", models.CreateWorkoutRequest{ExerciseID: 1, Set: intPtr(maxSetNumber)}, false},
+		{"serie cero", models.CreateWorkoutRequest{ExerciseID: 1, Set: intPtr(0)}, true},
+		{"serie mayor al máximo", models.CreateWorkoutRequest{ExerciseID: 1, Set: intPtr(maxSetNumber + 1)}, true},
+
+		{"RPE mínimo", models.CreateWorkoutRequest{ExerciseID: 1, RPE: floatPtr(1)}, false},
+		{"RPE máximo", models.CreateWorkoutRequest{ExerciseID: 1, RPE: floatPtr(10)}, false},
+		{"RPE en medio punto", models.CreateWorkoutRequest{ExerciseID: 1, RPE: floatPtr(8.5)}, false},
+		{"RPE menor a 1", models.CreateWorkoutRequest{ExerciseID: 1, RPE: floatPtr(0.5)}, true},
+		{"RPE mayor a 10", models.CreateWorkoutRequest{ExerciseID: 1, RPE: floatPtr(10.5)}, true},
+		{"RPE fuera de paso", models.CreateWorkoutRequest{ExerciseID: 1, RPE: floatPtr(7.3)}, true},
+
+		{"RIR cero", models.CreateWorkoutRequest{ExerciseID: 1, RIR: intPtr(0)}, false},
+		{"RIR máximo", models.CreateWorkoutRequest{ExerciseID: 1, RIR: intPtr(10)}, false},
+		{"RIR negativo", models.CreateWorkoutRequest{ExerciseID: 1, RIR: intPtr(-1)}, true},
+		{"RIR mayor a 10", models.CreateWorkoutRequest{ExerciseID: 1, RIR: intPtr(11)}, true},
+
+		{"tipo de calentamiento", models.CreateWorkoutRequest{ExerciseID: 1, SetType: stringPtr(models.SetTypeWarmup)}, false},
+		{"tipo AMRAP", models.CreateWorkoutRequest{ExerciseID: 1, SetType: stringPtr(models.SetTypeAMRAP)}, false},
+		{"tipo vacío usa working", models.CreateWorkoutRequest{ExerciseID: 1, SetType: &empty}, false},
+		{"tipo inválido", models.CreateWorkoutRequest{ExerciseID: 1, SetType: stringPtr("superset")}, true},
+		{"tipo con mayúsculas", models.CreateWorkoutRequest{ExerciseID: 1, SetType: stringPtr("Warmup")}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWorkoutRequest(&tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error incorrecto: got %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetTypeValue(t *testing.T) {
+	empty := ""
+	if got := setTypeValue(&models.CreateWorkoutRequest{}); got != models.SetTypeWorking {
+		t.Errorf("sin tipo debería ser working, es %q", got)
+	}
+	if got := setTypeValue(&models.CreateWorkoutRequest{SetType: &empty}); got != models.SetTypeWorking {
+		t.Errorf("con tipo vacío debería ser working, es %q", got)
+	}
+	if got := setTypeValue(&models.CreateWorkoutRequest{SetType: stringPtr(models.SetTypeDrop)}); got != models.SetTypeDrop {
+		t.Errorf("debería respetar el tipo enviado, es %q", got)
+	}
+}
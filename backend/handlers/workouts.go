@@ -3,10 +3,13 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -14,14 +17,107 @@ import (
 	"github.com/goalritmo/gym/backend/models"
 )
 
-// convertToArgentinaTime convierte una fecha UTC a la zona horaria de Argentina
-func convertToArgentinaTime(utcTime time.Time) time.Time {
-	loc, err := time.LoadLocation("America/Argentina/Buenos_Aires")
-	if err != nil {
-		// Fallback a UTC-3 si no se puede cargar la zona horaria
-		loc = time.FixedZone("UTC-3", -3*60*60)
+// maxSetNumber es el número de serie más alto permitido por ejercicio y día
+const maxSetNumber = 20
+
+// validateWorkoutRequest valida los campos de una serie antes de guardarla
+func validateWorkoutRequest(req *models.CreateWorkoutRequest) error {
+	if req.Reps != nil && *req.Reps <= 0 {
+		return errors.New("Repeticiones deben ser mayores a 0 si se proporcionan")
+	}
+
+	// Validar peso si se proporciona
+	if req.Weight != nil && *req.Weight <= 0 {
+		return errors.New("Peso debe ser mayor a 0 si se proporciona")
+	}
+
+	if req.Set != nil && (*req.Set <= 0 || *req.Set > maxSetNumber) {
+		return fmt.Errorf("Número de serie debe estar entre 1 y %d", maxSetNumber)
+	}
+
+	if req.SetType != nil && *req.SetType != "" && !isValidSetType(*req.SetType) {
+		return fmt.Errorf("Tipo de serie inválido, usar uno de: %s", strings.Join(models.SetTypes, ", "))
 	}
-	return utcTime.In(loc)
+
+	// RPE de 1 a 10 en pasos de 0.5
+	if req.RPE != nil && (*req.RPE < 1 || *req.RPE > 10 || math.Mod(*req.RPE*2, 1) != 0) {
+		return errors.New("RPE debe estar entre 1 y 10, en pasos de 0.5")
+	}
+
+	if req.RIR != nil && (*req.RIR < 0 || *req.RIR > 10) {
+		return errors.New("RIR debe estar entre 0 y 10")
+	}
+
+	return nil
+}
+
+// isValidSetType indica si el tipo de serie es uno de los permitidos
+func isValidSetType(setType string) bool {
+	for _, valid := range models.SetTypes {
+		if setType == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// setTypeValue devuelve el tipo de serie de la solicitud, working si no se envió
+func setTypeValue(req *models.CreateWorkoutRequest) string {
+	if req.SetType == nil || *req.SetType == "" {
+		return models.SetTypeWorking
+	}
+	return *req.SetType
+}
+
+// workingSetFilter es la condición SQL que deja afuera las series de calentamiento
+// al calcular volumen, récords y progreso (w = workouts)
+const workingSetFilter = "w.set_type <> 'warmup'"
+
+// workoutLoad es la carga de una serie en kg para calcular volumen, récords y progresión: el peso
+// más el peso corporal registrado en ejercicios con peso corporal (w = workouts)
+const workoutLoad = "(w.weight + COALESCE(w.bodyweight_kg, 0))"
+
+// workoutColumns son las columnas que se leen de una serie (w = workouts, e = exercises).
+// Debe mantenerse en el mismo orden que scanWorkout.
+const workoutColumns = `
+	w.id, w.user_id, w.workout_day_id, w.exercise_id, e.name as exercise_name,
+	w.weight, w.reps, w.set, w.seconds, w.observations, w.set_type, w.rpe, w.rir,
+	w.block_id, w.created_at, e.is_sport,
+	w.distance_meters, w.elevation_gain_meters, w.avg_heart_rate, w.max_heart_rate, w.calories, w.bodyweight_kg
+`
+
+// scanWorkout lee una fila seleccionada con workoutColumns y calcula ritmo y velocidad.
+// extra recibe las columnas que se seleccionen después de workoutColumns.
+func scanWorkout(rows *sql.Rows, workout *models.Workout, extra ...interface{}) error {
+	dest := []interface{}{
+		&workout.ID,
+		&workout.UserID,
+		&workout.WorkoutDayID,
+		&workout.ExerciseID,
+		&workout.ExerciseName,
+		&workout.Weight,
+		&workout.Reps,
+		&workout.Set,
+		&workout.Seconds,
+		&workout.Observations,
+		&workout.SetType,
+		&workout.RPE,
+		&workout.RIR,
+		&workout.BlockID,
+		&workout.CreatedAt,
+		&workout.IsSport,
+		&workout.DistanceMeters,
+		&workout.ElevationGainMeters,
+		&workout.AvgHeartRate,
+		&workout.MaxHeartRate,
+		&workout.Calories,
+		&workout.Bodyweight,
+	}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+	fillCardioPace(workout)
+	return nil
 }
 
 // GetWorkoutsHandler obtiene la lista de workouts
@@ -35,29 +131,33 @@ func GetWorkoutsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	loc := getUserLocation(r, userID)
+	unit := getUserWeightUnit(userID)
+
 	// Obtener parámetros de query
 	date := r.URL.Query().Get("date")
 
 
 
 	query := `
-		SELECT w.id, w.user_id, w.workout_day_id, w.exercise_id, e.name as exercise_name, 
-			   w.weight, w.reps, w.set, w.seconds, w.observations, w.created_at, e.is_sport
+		SELECT ` + workoutColumns + `
 		FROM workouts w
 		JOIN exercises e ON w.exercise_id = e.id
-		WHERE w.user_id = $1
+		JOIN workout_days wd ON w.workout_day_id = wd.id
+		WHERE w.user_id = $1 AND w.deleted_at IS NULL
 	`
 	args := []interface{}{userID}
 	argIndex := 2
 
 	if date != "" {
-		// Filtrar por fecha usando la fecha de creación del workout
-		query += fmt.Sprintf(" AND DATE(w.created_at AT TIME ZONE 'UTC' AT TIME ZONE 'America/Argentina/Buenos_Aires') = $%d", argIndex)
+		// Filtrar por la fecha del día de entrenamiento (las series pueden cargarse después)
+		query += fmt.Sprintf(" AND wd.date = $%d", argIndex)
 		args = append(args, date)
 		argIndex++
 	}
 
-	query += " ORDER BY w.created_at DESC, w.set ASC"
+	// Días más recientes primero; dentro de cada día, las series en el orden en que se hicieron
+	query += " ORDER BY wd.date DESC, w.created_at ASC, w.id ASC"
 
 	fmt.Printf("Ejecutando query con %d parámetros\n", len(args))
 
@@ -74,37 +174,26 @@ func GetWorkoutsHandler(w http.ResponseWriter, r *http.Request) {
 	var workouts []models.Workout
 	for rows.Next() {
 		var workout models.Workout
-		err := rows.Scan(
-			&workout.ID,
-			&workout.UserID,
-			&workout.WorkoutDayID,
-			&workout.ExerciseID,
-			&workout.ExerciseName,
-			&workout.Weight,
-			&workout.Reps,
-			&workout.Set,
-			&workout.Seconds,
-			&workout.Observations,
-			&workout.CreatedAt,
-			&workout.IsSport,
-		)
+		err := scanWorkout(rows, &workout)
 		if err != nil {
 			fmt.Printf("Error escaneando workout: %v\n", err)
 			continue
 		}
 
-		// Convertir fecha a zona horaria de Argentina
-		workout.CreatedAt = convertToArgentinaTime(workout.CreatedAt)
+		// Convertir fecha a zona horaria del usuario
+		workout.CreatedAt = convertToUserTime(workout.CreatedAt, loc)
 		workouts = append(workouts, workout)
 	}
 
 	fmt.Printf("Encontrados %d workouts\n", len(workouts))
+	convertWorkoutWeights(workouts, unit)
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(workouts)
 }
 
-// GetWorkoutDaysHandler obtiene la lista de días de entrenamiento
+// GetWorkoutDaysHandler obtiene la lista de días de entrenamiento, sin sus series.
+// Los días con sus series, paginados, están en GET /workout-days/detailed.
 func GetWorkoutDaysHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -115,12 +204,13 @@ func GetWorkoutDaysHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	loc := getUserLocation(r, userID)
 
 
 	query := `
-		SELECT id, user_id, date, name, effort, mood, created_at, updated_at
+		SELECT ` + workoutDayColumns + `
 		FROM workout_days 
-		WHERE user_id = $1 
+		WHERE user_id = $1 AND deleted_at IS NULL
 		ORDER BY date DESC
 	`
 
@@ -137,24 +227,15 @@ func GetWorkoutDaysHandler(w http.ResponseWriter, r *http.Request) {
 	var workoutDays []models.WorkoutDay
 	for rows.Next() {
 		var day models.WorkoutDay
-		err := rows.Scan(
-			&day.ID,
-			&day.UserID,
-			&day.Date,
-			&day.Name,
-			&day.Effort,
-			&day.Mood,
-			&day.CreatedAt,
-			&day.UpdatedAt,
-		)
+		err := scanWorkoutDay(rows, &day)
 		if err != nil {
 			fmt.Printf("Error escaneando día de entrenamiento: %v\n", err)
 			continue
 		}
 
-		// Convertir fechas a zona horaria de Argentina
-		day.CreatedAt = convertToArgentinaTime(day.CreatedAt)
-		day.UpdatedAt = convertToArgentinaTime(day.UpdatedAt)
+		// Convertir fechas a zona horaria del usuario
+		day.CreatedAt = convertToUserTime(day.CreatedAt, loc)
+		day.UpdatedAt = convertToUserTime(day.UpdatedAt, loc)
 		workoutDays = append(workoutDays, day)
 	}
 
@@ -191,75 +272,52 @@ func CreateWorkoutHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	// Validaciones
-	if req.Reps != nil && *req.Reps <= 0 {
-		http.Error(w, "Repeticiones deben ser mayores a 0 si se proporcionan", http.StatusBadRequest)
+	if err := validateWorkoutRequest(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	
-	// Validar peso si se proporciona
-	if req.Weight != nil {
-		if *req.Weight <= 0 {
-			http.Error(w, "Peso debe ser mayor a 0 si se proporciona", http.StatusBadRequest)
-			return
-		}
+
+	// El peso se guarda en kg: se convierte desde la unidad enviada (o la del usuario)
+	// y la respuesta se devuelve en la unidad del usuario
+	unit := getUserWeightUnit(userID)
+	inputUnit, err := requestWeightUnit(req.WeightUnit, unit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
+	req.Weight = weightPtrToKilograms(req.Weight, inputUnit)
 
-	// Verificar que el ejercicio existe
-	var exerciseExists bool
-	err = database.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM exercises WHERE id = $1)", req.ExerciseID).Scan(&exerciseExists)
+	// Verificar que el ejercicio existe y que las métricas de cardio corresponden a su tipo
+	kind, err := loadExerciseKind(database.DB, req.ExerciseID)
 	if err != nil {
-		fmt.Printf("Error verificando ejercicio: %v\n", err)
-		http.Error(w, "Error verificando ejercicio", http.StatusInternalServerError)
+		fmt.Printf("Ejercicio no encontrado o error verificando %d: %v\n", req.ExerciseID, err)
+		writeExerciseKindError(w, err)
 		return
 	}
-	if !exerciseExists {
-		fmt.Printf("Ejercicio no encontrado: %d\n", req.ExerciseID)
-		http.Error(w, "Ejercicio no encontrado", http.StatusBadRequest)
+	if err := validateCardioMetrics(req.CardioMetrics, kind); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 	fmt.Printf("Ejercicio verificado correctamente\n")
 
-	// Obtener fecha actual en zona horaria de Argentina
-	var argentinaLocation *time.Location
-	argentinaLocation, err = time.LoadLocation("America/Argentina/Buenos_Aires")
+	// Resolver el día de entrenamiento: el indicado, el de la fecha enviada o el de hoy
+	// en la zona horaria del usuario
+	loc := getUserLocation(r, userID)
+	workoutDayID, err := resolveWorkoutDayID(database.DB, userID, loc, req.Date, req.WorkoutDayID)
 	if err != nil {
-		argentinaLocation = time.FixedZone("UTC-3", -3*60*60)
+		writeWorkoutDayError(w, err)
+		return
 	}
-	now := time.Now().In(argentinaLocation)
-	today := now.Format("2006-01-02")
 
-	var workoutDayID int
-
-
-
-	// Verificar si ya existe un día de entrenamiento para hoy
-	sessionQuery := `SELECT id FROM workout_days WHERE user_id = $1 AND date = $2`
-	var existingID int
-	err = database.DB.QueryRow(sessionQuery, userID, today).Scan(&existingID)
-	
-	if err != nil {
-		// No existe día de entrenamiento para hoy, crear uno nuevo
-		createDayQuery := `
-			INSERT INTO workout_days (user_id, date, name, effort, mood) 
-			VALUES ($1, $2, $3, 0, 0) 
-			RETURNING id
-		`
-		dayName := "Entrenamiento del día"
-		err = database.DB.QueryRow(createDayQuery, userID, today, dayName).Scan(&workoutDayID)
+	// En ejercicios con peso corporal se guarda el último peso corporal registrado hasta ese día
+	var bodyweight *float64
+	if kind.Bodyweight {
+		bodyweight, err = loadBodyweightOn(database.DB, userID, workoutDayID)
 		if err != nil {
-			fmt.Printf("Error creando día de entrenamiento: %v\n", err)
-			http.Error(w, "Error creando día de entrenamiento", http.StatusInternalServerError)
+			fmt.Printf("Error obteniendo peso corporal: %v\n", err)
+			http.Error(w, "Error obteniendo peso corporal", http.StatusInternalServerError)
 			return
 		}
-		fmt.Printf("Día de entrenamiento creado con ID: %d\n", workoutDayID)
-	} else {
-		workoutDayID = existingID
-	}
-
-	// Obtener valores de los punteros de forma segura
-	var setValue int = 1
-	if req.Set != nil {
-		setValue = *req.Set
 	}
 
 	// Obtener valor de peso de forma segura
@@ -274,88 +332,39 @@ func CreateWorkoutHandler(w http.ResponseWriter, r *http.Request) {
 		repsValue = *req.Reps
 	}
 
-	// Verificar si necesitamos crear series automáticamente
-	// Solo si la serie es > 1 y no hay otras series del mismo ejercicio ese día
-	if setValue > 1 {
-		// Validar que setValue no sea excesivamente grande (límite de seguridad)
-		if setValue > 20 {
-			fmt.Printf("Número de serie demasiado alto: %d\n", setValue)
-			http.Error(w, "Número de serie no puede ser mayor a 20", http.StatusBadRequest)
-			return
-		}
-
-		// Verificar si ya existen series del mismo ejercicio en el día de entrenamiento
-		var existingSetsCount int
-		checkQuery := `
-			SELECT COUNT(*) 
-			FROM workouts 
-			WHERE user_id = $1 AND workout_day_id = $2 AND exercise_id = $3
-		`
-		err = database.DB.QueryRow(checkQuery, userID, workoutDayID, req.ExerciseID).Scan(&existingSetsCount)
-		if err != nil {
-			fmt.Printf("Error verificando series existentes: %v\n", err)
-			http.Error(w, "Error verificando series existentes", http.StatusInternalServerError)
-			return
-		}
+	// La serie, las series completadas y la renumeración se guardan juntas
+	tx, err := database.DB.Begin()
+	if err != nil {
+		fmt.Printf("Error iniciando transacción: %v\n", err)
+		http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
 
-		// Si no hay series existentes, crear automáticamente las series faltantes (1 hasta setValue-1)
-		if existingSetsCount == 0 {
-			fmt.Printf("Creando automáticamente %d series faltantes para ejercicio %d\n", setValue-1, req.ExerciseID)
-			
-			// Crear las series faltantes en una transacción
-			tx, err := database.DB.Begin()
-			if err != nil {
-				fmt.Printf("Error iniciando transacción: %v\n", err)
-				http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
-				return
-			}
-			defer tx.Rollback()
-
-			// Insertar las series faltantes (1 hasta setValue-1)
-			for i := 1; i < setValue; i++ {
-				_, err = tx.Exec(`
-					INSERT INTO workouts (user_id, workout_day_id, exercise_id, weight, reps, set, seconds, observations)
-					VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-				`, userID, workoutDayID, req.ExerciseID, weightValue, repsValue, i, req.Seconds, req.Observations)
-				
-				if err != nil {
-					fmt.Printf("Error creando serie automática %d: %v\n", i, err)
-					http.Error(w, "Error creando series automáticas", http.StatusInternalServerError)
-					return
-				}
-			}
+	// Series actuales del ejercicio en el día, bloqueadas para numerar sin huecos ni repetidos
+	existingSets, err := loadSetOrder(tx, userID, workoutDayID, req.ExerciseID, 0)
+	if err != nil {
+		fmt.Printf("Error verificando series existentes: %v\n", err)
+		http.Error(w, "Error verificando series existentes", http.StatusInternalServerError)
+		return
+	}
+	if len(existingSets) >= maxSetNumber {
+		http.Error(w, fmt.Sprintf("El ejercicio ya tiene %d series ese día", maxSetNumber), http.StatusBadRequest)
+		return
+	}
 
-			// Confirmar la transacción
-			if err = tx.Commit(); err != nil {
-				fmt.Printf("Error confirmando transacción: %v\n", err)
-				http.Error(w, "Error confirmando transacción", http.StatusInternalServerError)
-				return
-			}
-			
-			fmt.Printf("Series automáticas creadas exitosamente\n")
-		} else {
-			// Si ya existen series, verificar que no haya conflicto con la serie que se está insertando
-			var existingSetNumber int
-			conflictQuery := `
-				SELECT set 
-				FROM workouts 
-				WHERE user_id = $1 AND workout_day_id = $2 AND exercise_id = $3 AND set = $4
-			`
-			err = database.DB.QueryRow(conflictQuery, userID, workoutDayID, req.ExerciseID, setValue).Scan(&existingSetNumber)
-			if err == nil {
-				// La serie ya existe
-				fmt.Printf("Serie %d ya existe para ejercicio %d\n", setValue, req.ExerciseID)
-				http.Error(w, fmt.Sprintf("La serie %d ya existe para este ejercicio", setValue), http.StatusConflict)
-				return
-			}
-			// Si err != nil, significa que no existe conflicto, continuar normalmente
-		}
+	// Sin número de serie la nueva queda última
+	setValue := len(existingSets) + 1
+	if req.Set != nil {
+		setValue = *req.Set
 	}
+	setValue = newSetPlacement(len(existingSets), setValue)
 
 	// Insertar workout asociado al día de entrenamiento
 	query := `
-		INSERT INTO workouts (user_id, workout_day_id, exercise_id, weight, reps, set, seconds, observations)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO workouts (user_id, workout_day_id, exercise_id, weight, reps, set, seconds, observations, set_type, rpe, rir, block_id,
+			distance_meters, elevation_gain_meters, avg_heart_rate, max_heart_rate, calories, bodyweight_kg)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 		RETURNING id, workout_day_id, created_at
 	`
 
@@ -376,12 +385,31 @@ func CreateWorkoutHandler(w http.ResponseWriter, r *http.Request) {
 		workout.Reps = 0 // Valor por defecto cuando no se proporcionan reps
 	}
 	workout.Observations = req.Observations
-	
+	workout.Set = setValue
+	workout.Seconds = req.Seconds
+	workout.SetType = setTypeValue(&req)
+	workout.RPE = req.RPE
+	workout.RIR = req.RIR
+	workout.IsSport = kind.IsSport
+	workout.Bodyweight = bodyweight
+	workout.CardioMetrics = req.CardioMetrics
+	fillCardioPace(&workout)
+
+	// Superserie o circuito: el indicado o el de la serie anterior del mismo ejercicio
+	workout.BlockID, err = resolveWorkoutBlockID(tx, userID, workoutDayID, req.ExerciseID, req.BlockID)
+	if err != nil {
+		writeWorkoutBlockError(w, err)
+		return
+	}
+
+	// Se inserta al final y, si se pidió una posición intermedia, se corren las siguientes
+	lastSet := len(existingSets) + 1
 	fmt.Printf("Insertando workout con workoutDayID: %d, weight: %f, reps: %d, set: %d\n", workoutDayID, weightValue, repsValue, setValue)
-	err = database.DB.QueryRow(
+	err = tx.QueryRow(
 		query,
 		userID, workoutDayID, req.ExerciseID, weightValue, repsValue,
-		setValue, req.Seconds, req.Observations,
+		lastSet, req.Seconds, req.Observations, workout.SetType, req.RPE, req.RIR, workout.BlockID,
+		req.DistanceMeters, req.ElevationGainMeters, req.AvgHeartRate, req.MaxHeartRate, req.Calories, bodyweight,
 	).Scan(&workout.ID, &workout.WorkoutDayID, &workout.CreatedAt)
 
 	if err != nil {
@@ -389,9 +417,34 @@ func CreateWorkoutHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Error creando workout", http.StatusInternalServerError)
 		return
 	}
+	if setValue < lastSet {
+		workout.Set, err = placeWorkoutSet(tx, userID, workout.ID, workoutDayID, req.ExerciseID, setValue)
+		if err != nil {
+			fmt.Printf("Error ubicando serie: %v\n", err)
+			http.Error(w, "Error creando workout", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Detectar récords personales batidos con esta serie
+	records, beaten := detectPersonalRecordsInTx(tx, userID, workout.ID)
+	workout.PersonalRecords = records[workout.ID]
+
+	if err = tx.Commit(); err != nil {
+		fmt.Printf("Error confirmando transacción: %v\n", err)
+		http.Error(w, "Error confirmando transacción", http.StatusInternalServerError)
+		return
+	}
+	notifyPersonalRecords(userID, beaten)
 	
-	// Convertir fecha a zona horaria de Argentina antes de devolver
-	workout.CreatedAt = convertToArgentinaTime(workout.CreatedAt)
+	// Convertir fecha a zona horaria del usuario antes de devolver
+	workout.CreatedAt = convertToUserTime(workout.CreatedAt, loc)
+	convertWorkoutWeight(&workout, unit)
+
+	// Si hay una sesión en vivo en este día, arranca el descanso del ejercicio
+	advanceLiveSession(userID, workoutDayID, req.ExerciseID)
+	// Si el día es la sesión del programa que sigue, el programa avanza al completarla
+	syncProgramAfterSets(userID, workoutDayID, loc)
 	
 	fmt.Printf("Workout creado exitosamente con ID: %d\n", workout.ID)
 
@@ -422,30 +475,92 @@ func UpdateWorkoutHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validaciones
-	if req.Reps != nil && *req.Reps <= 0 {
-		http.Error(w, "Repeticiones deben ser mayores a 0 si se proporcionan", http.StatusBadRequest)
+	// Validaciones: son las mismas que al crear, así que set tiene que estar entre 1 y maxSetNumber
+	if err := validateWorkoutRequest(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	
-	// Validar peso si se proporciona
-	if req.Weight != nil && *req.Weight <= 0 {
-		http.Error(w, "Peso debe ser mayor a 0 si se proporciona", http.StatusBadRequest)
+
+	unit := getUserWeightUnit(userID)
+	inputUnit, err := requestWeightUnit(req.WeightUnit, unit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	req.Weight = weightPtrToKilograms(req.Weight, inputUnit)
 
+	// Las métricas de cardio dependen del tipo de ejercicio de la serie
+	var kind exerciseKind
+	err = database.DB.QueryRow(`
+		SELECT e.is_sport, e.tracks_distance
+		FROM workouts w
+		JOIN exercises e ON w.exercise_id = e.id
+		WHERE w.id = $1 AND w.user_id = $2 AND w.deleted_at IS NULL
+	`, id, userID).Scan(&kind.IsSport, &kind.TracksDistance)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Workout no encontrado", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error verificando workout", http.StatusInternalServerError)
+		}
+		return
+	}
+	if err := validateCardioMetrics(req.CardioMetrics, kind); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	loc := getUserLocation(r, userID)
+
+	// Si se envía una fecha o un workout_day_id, la serie se mueve a ese día
+	var targetDayID *int
+	if req.WorkoutDayID != nil || (req.Date != nil && *req.Date != "") {
+		dayID, err := resolveWorkoutDayID(database.DB, userID, loc, req.Date, req.WorkoutDayID)
+		if err != nil {
+			writeWorkoutDayError(w, err)
+			return
+		}
+		targetDayID = &dayID
+	}
+
+	// El bloque indicado tiene que ser del día en que queda la serie
+	var blockID *int
+	if req.BlockID != nil {
+		dayID := targetDayID
+		if dayID == nil {
+			var currentDayID int
+			err := database.DB.QueryRow("SELECT workout_day_id FROM workouts WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL", id, userID).Scan(&currentDayID)
+			if err != nil {
+				http.Error(w, "Workout no encontrado", http.StatusNotFound)
+				return
+			}
+			dayID = &currentDayID
+		}
+		blockID, err = resolveWorkoutBlockID(database.DB, userID, *dayID, 0, req.BlockID)
+		if err != nil {
+			writeWorkoutBlockError(w, err)
+			return
+		}
+	}
+
+	// Si la serie cambia de día sin indicar bloque, deja de pertenecer a su bloque anterior.
+	// Sin set se conserva el número actual; la numeración se corrige después de actualizar.
 	query := `
 		UPDATE workouts 
-		SET weight = $1, reps = $2, set = $3, seconds = $4, observations = $5
-		WHERE id = $6 AND user_id = $7
-		RETURNING id, exercise_id, weight, reps, set, seconds, observations, workout_day_id, created_at
+		SET weight = $1, reps = $2, set = COALESCE($3, set), seconds = $4, observations = $5,
+			block_id = CASE
+				WHEN $12::bigint IS NOT NULL THEN $12::bigint
+				WHEN $8::bigint IS NOT NULL AND $8::bigint <> workout_day_id THEN NULL
+				ELSE block_id
+			END,
+			workout_day_id = COALESCE($8, workout_day_id),
+			set_type = $9, rpe = $10, rir = $11,
+			distance_meters = $13, elevation_gain_meters = $14, avg_heart_rate = $15, max_heart_rate = $16, calories = $17
+		WHERE id = $6 AND user_id = $7 AND deleted_at IS NULL
+		RETURNING id, exercise_id, weight, reps, set, seconds, observations, set_type, rpe, rir, block_id, workout_day_id, created_at,
+			distance_meters, elevation_gain_meters, avg_heart_rate, max_heart_rate, calories, bodyweight_kg
 	`
 
-	var setValue int = 1
-	if req.Set != nil {
-		setValue = *req.Set
-	}
-
 	// Obtener valor de peso de forma segura
 	var weightValue float64 = 0
 	if req.Weight != nil {
@@ -458,15 +573,48 @@ func UpdateWorkoutHandler(w http.ResponseWriter, r *http.Request) {
 		repsValue = *req.Reps
 	}
 
+	tx, err := database.DB.Begin()
+	if err != nil {
+		http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	var previousDayID, exerciseID int
+	err = tx.QueryRow("SELECT workout_day_id, exercise_id FROM workouts WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL FOR UPDATE", id, userID).Scan(&previousDayID, &exerciseID)
+	if err != nil {
+		http.Error(w, "Workout no encontrado", http.StatusNotFound)
+		return
+	}
+
+	// Al mover la serie a otro día, las series del ejercicio en ese día se bloquean para
+	// verificar que todavía entra una más
+	if targetDayID != nil && *targetDayID != previousDayID {
+		targetSets, err := loadSetOrder(tx, userID, *targetDayID, exerciseID, id)
+		if err != nil {
+			fmt.Printf("Error verificando series existentes: %v\n", err)
+			http.Error(w, "Error verificando series existentes", http.StatusInternalServerError)
+			return
+		}
+		if len(targetSets) >= maxSetNumber {
+			http.Error(w, fmt.Sprintf("El ejercicio ya tiene %d series ese día", maxSetNumber), http.StatusBadRequest)
+			return
+		}
+	}
+
 	var workout models.Workout
-	err = database.DB.QueryRow(
+	err = tx.QueryRow(
 		query,
-		weightValue, repsValue, setValue, req.Seconds, req.Observations,
-		id, userID,
+		weightValue, repsValue, req.Set, req.Seconds, req.Observations,
+		id, userID, targetDayID, setTypeValue(&req), req.RPE, req.RIR, blockID,
+		req.DistanceMeters, req.ElevationGainMeters, req.AvgHeartRate, req.MaxHeartRate, req.Calories,
 	).Scan(
 		&workout.ID, &workout.ExerciseID, &workout.Weight, &workout.Reps,
 		&workout.Set, &workout.Seconds, &workout.Observations,
+		&workout.SetType, &workout.RPE, &workout.RIR, &workout.BlockID,
 		&workout.WorkoutDayID, &workout.CreatedAt,
+		&workout.DistanceMeters, &workout.ElevationGainMeters, &workout.AvgHeartRate, &workout.MaxHeartRate, &workout.Calories,
+		&workout.Bodyweight,
 	)
 
 	if err != nil {
@@ -474,8 +622,40 @@ func UpdateWorkoutHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Si el número pedido ya está ocupado, las demás series se corren. Al cambiar de día
+	// sin indicar set la serie queda última, y en el día anterior se cierra el hueco.
+	position := workout.Set
+	if workout.WorkoutDayID != previousDayID {
+		if err := compactSetNumbers(tx, userID, previousDayID, workout.ExerciseID); err != nil {
+			fmt.Printf("Error renumerando series: %v\n", err)
+			http.Error(w, "Error actualizando workout", http.StatusInternalServerError)
+			return
+		}
+		if req.Set == nil {
+			position = maxSetNumber
+		}
+	}
+	workout.Set, err = placeWorkoutSet(tx, userID, workout.ID, workout.WorkoutDayID, workout.ExerciseID, position)
+	if err != nil {
+		fmt.Printf("Error renumerando series: %v\n", err)
+		http.Error(w, "Error actualizando workout", http.StatusInternalServerError)
+		return
+	}
+
+	records, beaten := detectPersonalRecordsInTx(tx, userID, workout.ID)
+	workout.PersonalRecords = records[workout.ID]
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, "Error confirmando transacción", http.StatusInternalServerError)
+		return
+	}
+	notifyPersonalRecords(userID, beaten)
+
 	workout.UserID = userID
-	workout.CreatedAt = convertToArgentinaTime(workout.CreatedAt)
+	workout.IsSport = kind.IsSport
+	fillCardioPace(&workout)
+	workout.CreatedAt = convertToUserTime(workout.CreatedAt, loc)
+	convertWorkoutWeight(&workout, unit)
 	json.NewEncoder(w).Encode(workout)
 }
 
@@ -518,7 +698,7 @@ func UpdateWorkoutDayNameHandler(w http.ResponseWriter, r *http.Request) {
 	query := `
 		UPDATE workout_days 
 		SET name = $1
-		WHERE id = $2 AND user_id = $3
+		WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL
 		RETURNING id, user_id, date, name, effort, mood, created_at
 	`
 
@@ -546,11 +726,11 @@ func UpdateWorkoutDayNameHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	workoutDay.CreatedAt = convertToArgentinaTime(workoutDay.CreatedAt)
+	workoutDay.CreatedAt = convertToUserTime(workoutDay.CreatedAt, getUserLocation(r, userID))
 	json.NewEncoder(w).Encode(workoutDay)
 }
 
-// DeleteWorkoutHandler elimina un workout
+// DeleteWorkoutHandler manda un workout a la papelera y renumera las series que quedan del ejercicio
 func DeleteWorkoutHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
@@ -565,25 +745,41 @@ func DeleteWorkoutHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Verificar que el workout existe y pertenece al usuario
-	var workoutExists bool
-	err = database.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM workouts WHERE id = $1 AND user_id = $2)", id, userID).Scan(&workoutExists)
+	tx, err := database.DB.Begin()
 	if err != nil {
-		http.Error(w, "Error verificando workout", http.StatusInternalServerError)
+		http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
 		return
 	}
-	if !workoutExists {
-		http.Error(w, "Workout no encontrado", http.StatusNotFound)
+	defer tx.Rollback()
+
+	// El workout va a la papelera: se puede restaurar hasta que se purgue
+	var workoutDayID, exerciseID int
+	err = tx.QueryRow(`
+		UPDATE workouts SET deleted_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		RETURNING workout_day_id, exercise_id
+	`, id, userID).Scan(&workoutDayID, &exerciseID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Workout no encontrado", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error eliminando workout", http.StatusInternalServerError)
+		}
 		return
 	}
 
-	// Eliminar el workout
-	_, err = database.DB.Exec("DELETE FROM workouts WHERE id = $1 AND user_id = $2", id, userID)
-	if err != nil {
+	// Las series siguientes bajan un número para no dejar huecos
+	if err := compactSetNumbers(tx, userID, workoutDayID, exerciseID); err != nil {
+		fmt.Printf("Error renumerando series: %v\n", err)
 		http.Error(w, "Error eliminando workout", http.StatusInternalServerError)
 		return
 	}
 
+	if err := tx.Commit(); err != nil {
+		http.Error(w, "Error confirmando transacción", http.StatusInternalServerError)
+		return
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
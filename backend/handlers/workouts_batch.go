@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/goalritmo/gym/backend/database"
+	"github.com/goalritmo/gym/backend/models"
+	"github.com/lib/pq"
+)
+
+// maxBatchSets es la cantidad máxima de series que se aceptan en un lote
+const maxBatchSets = 100
+
+var errBatchSetExists = errors.New("la serie ya existe")
+
+// validateWorkoutBatchRequest valida el tamaño del lote y cada una de sus series
+func validateWorkoutBatchRequest(req *models.CreateWorkoutBatchRequest) error {
+	if len(req.Sets) == 0 {
+		return errors.New("El lote debe incluir al menos una serie")
+	}
+	if len(req.Sets) > maxBatchSets {
+		return fmt.Errorf("El lote no puede tener más de %d series", maxBatchSets)
+	}
+	for i := range req.Sets {
+		set := &req.Sets[i]
+		if set.Date != nil || set.WorkoutDayID != nil {
+			return fmt.Errorf("Serie %d: date y workout_day_id se indican a nivel del lote", i+1)
+		}
+		if err := validateWorkoutRequest(set); err != nil {
+			return fmt.Errorf("Serie %d: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+// assignBatchSetNumbers asigna el número de cada serie del lote a continuación de las que el
+// ejercicio ya tiene en el día (existing, cantidad por ejercicio), así la numeración queda 1..n.
+// Un número enviado tiene que ser el siguiente: uno ocupado devuelve un error que envuelve
+// errBatchSetExists y uno que dejaría un hueco se rechaza.
+func assignBatchSetNumbers(existing map[int]int, sets []models.CreateWorkoutRequest) ([]int, error) {
+	counts := make(map[int]int, len(existing))
+	for exerciseID, count := range existing {
+		counts[exerciseID] = count
+	}
+
+	numbers := make([]int, len(sets))
+	for i, set := range sets {
+		next := counts[set.ExerciseID] + 1
+		if set.Set != nil && *set.Set < next {
+			return nil, fmt.Errorf("Serie %d: %w (serie %d del ejercicio %d)", i+1, errBatchSetExists, *set.Set, set.ExerciseID)
+		}
+		if set.Set != nil && *set.Set > next {
+			return nil, fmt.Errorf("Serie %d: la siguiente serie del ejercicio %d es la %d", i+1, set.ExerciseID, next)
+		}
+		if next > maxSetNumber {
+			return nil, fmt.Errorf("Serie %d: número de serie no puede ser mayor a %d", i+1, maxSetNumber)
+		}
+		counts[set.ExerciseID] = next
+		numbers[i] = next
+	}
+	return numbers, nil
+}
+
+// CreateWorkoutBatchHandler registra varias series (de uno o más ejercicios) en una sola transacción
+func CreateWorkoutBatchHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: user_id not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateWorkoutBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	if err := validateWorkoutBatchRequest(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Los pesos se guardan en kg: cada serie usa su unidad, la del lote o la del usuario
+	unit := getUserWeightUnit(userID)
+	batchUnit, err := requestWeightUnit(req.WeightUnit, unit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Pasar los pesos a kg y juntar los ejercicios involucrados
+	exerciseIDs := []int64{}
+	seenExercises := make(map[int]bool)
+	for i := range req.Sets {
+		set := &req.Sets[i]
+		setUnit, err := requestWeightUnit(set.WeightUnit, batchUnit)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Serie %d: %v", i+1, err), http.StatusBadRequest)
+			return
+		}
+		set.Weight = weightPtrToKilograms(set.Weight, setUnit)
+		if !seenExercises[set.ExerciseID] {
+			seenExercises[set.ExerciseID] = true
+			exerciseIDs = append(exerciseIDs, int64(set.ExerciseID))
+		}
+	}
+
+	// Verificar que todos los ejercicios existen y obtener su tipo
+	kinds := make(map[int]exerciseKind)
+	kindRows, err := database.DB.Query("SELECT id, is_sport, tracks_distance, bodyweight FROM exercises WHERE id = ANY($1)", pq.Array(exerciseIDs))
+	if err != nil {
+		fmt.Printf("Error verificando ejercicios: %v\n", err)
+		http.Error(w, "Error verificando ejercicios", http.StatusInternalServerError)
+		return
+	}
+	for kindRows.Next() {
+		var id int
+		var kind exerciseKind
+		if err := kindRows.Scan(&id, &kind.IsSport, &kind.TracksDistance, &kind.Bodyweight); err != nil {
+			kindRows.Close()
+			fmt.Printf("Error escaneando ejercicio: %v\n", err)
+			http.Error(w, "Error verificando ejercicios", http.StatusInternalServerError)
+			return
+		}
+		kinds[id] = kind
+	}
+	kindRows.Close()
+	if len(kinds) != len(exerciseIDs) {
+		http.Error(w, "Uno o más ejercicios no existen", http.StatusBadRequest)
+		return
+	}
+
+	// Las métricas de cardio dependen del tipo de cada ejercicio
+	for i, set := range req.Sets {
+		if err := validateCardioMetrics(set.CardioMetrics, kinds[set.ExerciseID]); err != nil {
+			http.Error(w, fmt.Sprintf("Serie %d: %v", i+1, err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	loc := getUserLocation(r, userID)
+
+	// Todo el lote se guarda o se descarta junto
+	tx, err := database.DB.Begin()
+	if err != nil {
+		fmt.Printf("Error iniciando transacción: %v\n", err)
+		http.Error(w, "Error iniciando transacción", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	workoutDayID, err := resolveWorkoutDayID(tx, userID, loc, req.Date, req.WorkoutDayID)
+	if err != nil {
+		writeWorkoutDayError(w, err)
+		return
+	}
+
+	// Peso corporal para los ejercicios con peso corporal del lote
+	bodyweight, err := loadBodyweightOn(tx, userID, workoutDayID)
+	if err != nil {
+		fmt.Printf("Error obteniendo peso corporal: %v\n", err)
+		http.Error(w, "Error obteniendo peso corporal", http.StatusInternalServerError)
+		return
+	}
+
+	// Series ya registradas por ejercicio en ese día, bloqueadas para numerar sin huecos ni repetidos
+	existingSets := make(map[int]int)
+	for _, exerciseID := range exerciseIDs {
+		ids, err := loadSetOrder(tx, userID, workoutDayID, int(exerciseID), 0)
+		if err != nil {
+			fmt.Printf("Error consultando series existentes: %v\n", err)
+			http.Error(w, "Error verificando series existentes", http.StatusInternalServerError)
+			return
+		}
+		existingSets[int(exerciseID)] = len(ids)
+	}
+
+	setNumbers, err := assignBatchSetNumbers(existingSets, req.Sets)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, errBatchSetExists) {
+			status = http.StatusConflict
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	insertQuery := `
+		INSERT INTO workouts (user_id, workout_day_id, exercise_id, weight, reps, set, seconds, observations, set_type, rpe, rir, block_id,
+			distance_meters, elevation_gain_meters, avg_heart_rate, max_heart_rate, calories, bodyweight_kg)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		RETURNING id, created_at
+	`
+
+	response := models.CreateWorkoutBatchResponse{
+		WorkoutDayID: workoutDayID,
+		Workouts:     make([]models.Workout, 0, len(req.Sets)),
+		AssignedSets: make(map[int][]int),
+	}
+
+	for i, set := range req.Sets {
+		workout := models.Workout{
+			UserID:       userID,
+			WorkoutDayID: workoutDayID,
+			ExerciseID:   set.ExerciseID,
+			Set:          setNumbers[i],
+			Seconds:      set.Seconds,
+			Observations: set.Observations,
+			SetType:      setTypeValue(&set),
+			RPE:          set.RPE,
+			RIR:          set.RIR,
+			IsSport:      kinds[set.ExerciseID].IsSport,
+		}
+		if kinds[set.ExerciseID].Bodyweight {
+			workout.Bodyweight = bodyweight
+		}
+		workout.CardioMetrics = set.CardioMetrics
+		fillCardioPace(&workout)
+		if set.Weight != nil {
+			workout.Weight = *set.Weight
+		}
+		if set.Reps != nil {
+			workout.Reps = *set.Reps
+		}
+		workout.BlockID, err = resolveWorkoutBlockID(tx, userID, workoutDayID, set.ExerciseID, set.BlockID)
+		if err != nil {
+			if err == errWorkoutBlockNotFound {
+				http.Error(w, fmt.Sprintf("Serie %d: bloque no encontrado en el día", i+1), http.StatusBadRequest)
+			} else {
+				writeWorkoutBlockError(w, err)
+			}
+			return
+		}
+
+		err = tx.QueryRow(
+			insertQuery,
+			userID, workoutDayID, workout.ExerciseID, workout.Weight, workout.Reps,
+			workout.Set, workout.Seconds, workout.Observations,
+			workout.SetType, workout.RPE, workout.RIR, workout.BlockID,
+			workout.DistanceMeters, workout.ElevationGainMeters, workout.AvgHeartRate, workout.MaxHeartRate, workout.Calories, workout.Bodyweight,
+		).Scan(&workout.ID, &workout.CreatedAt)
+		if err != nil {
+			fmt.Printf("Error creando serie %d del lote: %v\n", i+1, err)
+			http.Error(w, "Error guardando las series", http.StatusInternalServerError)
+			return
+		}
+
+		workout.CreatedAt = convertToUserTime(workout.CreatedAt, loc)
+		response.Workouts = append(response.Workouts, workout)
+		response.AssignedSets[workout.ExerciseID] = append(response.AssignedSets[workout.ExerciseID], workout.Set)
+	}
+
+	// Detectar récords personales de todo el lote con una única notificación
+	workoutIDs := make([]int, len(response.Workouts))
+	for i, workout := range response.Workouts {
+		workoutIDs[i] = workout.ID
+	}
+	records, beaten := detectPersonalRecordsInTx(tx, userID, workoutIDs...)
+	for i := range response.Workouts {
+		response.Workouts[i].PersonalRecords = records[response.Workouts[i].ID]
+	}
+
+	if err = tx.Commit(); err != nil {
+		fmt.Printf("Error confirmando transacción: %v\n", err)
+		http.Error(w, "Error confirmando transacción", http.StatusInternalServerError)
+		return
+	}
+	notifyPersonalRecords(userID, beaten)
+
+	fmt.Printf("Lote de %d series guardado en el día %d\n", len(response.Workouts), workoutDayID)
+	convertWorkoutWeights(response.Workouts, unit)
+
+	// Si hay una sesión en vivo en este día, arranca el descanso del último ejercicio del lote
+	advanceLiveSession(userID, workoutDayID, response.Workouts[len(response.Workouts)-1].ExerciseID)
+	// Si el día es la sesión del programa que sigue, el programa avanza al completarla
+	syncProgramAfterSets(userID, workoutDayID, loc)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
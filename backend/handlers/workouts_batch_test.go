@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/goalritmo/gym/backend/models"
+)
+
+func TestValidateWorkoutBatchRequest(t *testing.T) {
+	date := "2024-03-10"
+	tooMany := make([]models.CreateWorkoutRequest, maxBatchSets+1)
+	for i := range tooMany {
+		tooMany[i] = models.CreateWorkoutRequest{ExerciseID: 1}
+	}
+
+	tests := []struct {
+		name    string
+		sets    []models.CreateWorkoutRequest
+		wantErr bool
+	}{
+		{"válido", []models.CreateWorkoutRequest{{ExerciseID: 1, Reps: intPtr(10)}, {ExerciseID: 2, Set: intPtr(3)}}, false},
+		{"vacío", nil, true},
+		{"demasiadas series", tooMany, true},
+		{"fecha en una serie", []models.CreateWorkoutRequest{{ExerciseID: 1, Date: &date}}, true},
+		{"día en una serie", []models.CreateWorkoutRequest{{ExerciseID: 1, WorkoutDayID: intPtr(4)}}, true},
+		{"serie inválida", []models.CreateWorkoutRequest{{ExerciseID: 1}, {ExerciseID: 1, Reps: intPtr(0)}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWorkoutBatchRequest(&models.CreateWorkoutBatchRequest{Sets: tt.sets})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error incorrecto: got %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAssignBatchSetNumbers(t *testing.T) {
+	existing := map[int]int{1: 2}
+
+	t.Run("continúa después de las series existentes", func(t *testing.T) {
+		sets := []models.CreateWorkoutRequest{
+			{ExerciseID: 1},
+			{ExerciseID: 2},
+			{ExerciseID: 1},
+			{ExerciseID: 2, Set: intPtr(2)},
+			{ExerciseID: 2},
+		}
+		got, err := assignBatchSetNumbers(existing, sets)
+		if err != nil {
+			t.Fatalf("error inesperado: %v", err)
+		}
+		want := []int{3, 1, 4, 2, 3}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("números asignados %v, se esperaba %v", got, want)
+			}
+		}
+	})
+
+	t.Run("serie existente", func(t *testing.T) {
+		_, err := assignBatchSetNumbers(existing, []models.CreateWorkoutRequest{{ExerciseID: 1, Set: intPtr(2)}})
+		if !errors.Is(err, errBatchSetExists) {
+			t.Errorf("se esperaba errBatchSetExists, se obtuvo %v", err)
+		}
+	})
+
+	t.Run("serie repetida en el lote", func(t *testing.T) {
+		sets := []models.CreateWorkoutRequest{{ExerciseID: 3, Set: intPtr(1)}, {ExerciseID: 3, Set: intPtr(1)}}
+		if _, err := assignBatchSetNumbers(nil, sets); !errors.Is(err, errBatchSetExists) {
+			t.Errorf("se esperaba errBatchSetExists, se obtuvo %v", err)
+		}
+	})
+
+	t.Run("serie que dejaría un hueco", func(t *testing.T) {
+		_, err := assignBatchSetNumbers(existing, []models.CreateWorkoutRequest{{ExerciseID: 1, Set: intPtr(5)}})
+		if err == nil || errors.Is(err, errBatchSetExists) {
+			t.Errorf("se esperaba un error por dejar un hueco, se obtuvo %v", err)
+		}
+	})
+
+	t.Run("supera el máximo de series", func(t *testing.T) {
+		full := map[int]int{1: maxSetNumber}
+		_, err := assignBatchSetNumbers(full, []models.CreateWorkoutRequest{{ExerciseID: 1}})
+		if err == nil || errors.Is(err, errBatchSetExists) {
+			t.Errorf("se esperaba un error por superar %d series, se obtuvo %v", maxSetNumber, err)
+		}
+	})
+}
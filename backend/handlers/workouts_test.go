@@ -1,3 +1,5 @@
+//go:build integration
+
 package handlers
 
 import (
@@ -40,11 +42,11 @@ func TestCreateWorkoutHandler_ValidInput(t *testing.T) {
 	// Datos de prueba válidos
 	workoutData := models.CreateWorkoutRequest{
 		ExerciseID:   1,
-		Weight:       80.5,
-		Reps:         10,
-		Serie:        &[]int{1}[0],
+		Weight:       floatPtr(80.5),
+		Reps:         intPtr(10),
+		Set:          &[]int{1}[0],
 		Seconds:      &[]int{45}[0],
-		Observations: &[]string{"Buena ejecución"}[0],
+		Observations: "Buena ejecución",
 	}
 
 	req, err := mockRequest("POST", "/api/workouts", workoutData)
@@ -72,8 +74,8 @@ func TestCreateWorkoutHandler_InvalidWeight(t *testing.T) {
 	// Datos con peso inválido
 	workoutData := models.CreateWorkoutRequest{
 		ExerciseID: 1,
-		Weight:     -10, // Peso negativo inválido
-		Reps:       10,
+		Weight:     floatPtr(-10), // Peso negativo inválido
+		Reps:       intPtr(10),
 	}
 
 	req, err := mockRequest("POST", "/api/workouts", workoutData)
@@ -99,8 +101,8 @@ func TestCreateWorkoutHandler_InvalidWeight(t *testing.T) {
 func TestCreateWorkoutHandler_InvalidReps(t *testing.T) {
 	workoutData := models.CreateWorkoutRequest{
 		ExerciseID: 1,
-		Weight:     80.5,
-		Reps:       0, // Reps inválidas
+		Weight:     floatPtr(80.5),
+		Reps:       intPtr(0), // Reps inválidas
 	}
 
 	req, err := mockRequest("POST", "/api/workouts", workoutData)
@@ -150,8 +152,8 @@ func TestCreateWorkoutHandler_InvalidJSON(t *testing.T) {
 func TestUpdateWorkoutHandler_ValidInput(t *testing.T) {
 	workoutData := models.CreateWorkoutRequest{
 		ExerciseID: 1,
-		Weight:     85.0,
-		Reps:       12,
+		Weight:     floatPtr(85.0),
+		Reps:       intPtr(12),
 	}
 
 	req, err := mockRequest("PUT", "/api/workouts/1", workoutData)
@@ -179,8 +181,8 @@ func TestUpdateWorkoutHandler_ValidInput(t *testing.T) {
 func TestUpdateWorkoutHandler_InvalidID(t *testing.T) {
 	workoutData := models.CreateWorkoutRequest{
 		ExerciseID: 1,
-		Weight:     85.0,
-		Reps:       12,
+		Weight:     floatPtr(85.0),
+		Reps:       intPtr(12),
 	}
 
 	req, err := mockRequest("PUT", "/api/workouts/invalid_id", workoutData)
@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
+	_ "time/tzdata" // Zonas horarias embebidas: la imagen alpine no incluye tzdata
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
@@ -29,6 +31,9 @@ func main() {
 
 	log.Println("Conexión con base de datos establecida")
 
+	// Purgar la papelera en segundo plano
+	handlers.StartTrashPurge(time.Hour)
+
 	// Crear router
 	r := mux.NewRouter()
 
@@ -45,16 +50,33 @@ func main() {
 	// Workouts endpoints
 	api.HandleFunc("/workouts", handlers.GetWorkoutsHandler).Methods("GET")
 	api.HandleFunc("/workouts", handlers.CreateWorkoutHandler).Methods("POST")
+	api.HandleFunc("/workouts/batch", handlers.CreateWorkoutBatchHandler).Methods("POST")
+	api.HandleFunc("/workouts/history", handlers.GetWorkoutHistoryHandler).Methods("GET")
 	api.HandleFunc("/workouts/{id}", handlers.UpdateWorkoutHandler).Methods("PUT")
 	api.HandleFunc("/workouts/{id}", handlers.DeleteWorkoutHandler).Methods("DELETE")
+	api.HandleFunc("/workouts/{id}/restore", handlers.RestoreWorkoutHandler).Methods("POST")
 	api.HandleFunc("/workout-days/{id}/name", handlers.UpdateWorkoutDayNameHandler).Methods("PUT")
 
 	// Workout days endpoints
 	api.HandleFunc("/workout-days", handlers.GetWorkoutDaysHandler).Methods("GET")
+	api.HandleFunc("/workout-days/detailed", handlers.GetWorkoutDaysPageHandler).Methods("GET")
+	api.HandleFunc("/workout-days/{id}", handlers.GetWorkoutDayHandler).Methods("GET")
+	api.HandleFunc("/workout-days/{id}", handlers.UpdateWorkoutDayHandler).Methods("PUT")
+	api.HandleFunc("/workout-days/{id}", handlers.DeleteWorkoutDayHandler).Methods("DELETE")
+	api.HandleFunc("/workout-days/{id}/restore", handlers.RestoreWorkoutDayHandler).Methods("POST")
+	api.HandleFunc("/workout-days/{id}/exercises/{exercise_id}/sets/order", handlers.ReorderWorkoutSetsHandler).Methods("PUT")
+
+	// Workout blocks endpoints (superseries y circuitos)
+	api.HandleFunc("/workout-days/{id}/blocks", handlers.CreateWorkoutBlockHandler).Methods("POST")
+	api.HandleFunc("/workout-blocks/{id}", handlers.UpdateWorkoutBlockHandler).Methods("PUT")
+	api.HandleFunc("/workout-blocks/{id}", handlers.DeleteWorkoutBlockHandler).Methods("DELETE")
 
 	// Exercises endpoints
 	api.HandleFunc("/exercises", handlers.GetExercisesHandler).Methods("GET")
 	api.HandleFunc("/exercises/{id}", handlers.GetExerciseHandler).Methods("GET")
+	api.HandleFunc("/exercises/{id}/records", handlers.GetExerciseRecordsHandler).Methods("GET")
+	api.HandleFunc("/exercises/{id}/progress", handlers.GetExerciseProgressHandler).Methods("GET")
+	api.HandleFunc("/exercises/{id}/suggestion", handlers.GetProgressionSuggestionHandler).Methods("GET")
 
 	// Equipment endpoints
 	api.HandleFunc("/equipment", handlers.GetEquipmentHandler).Methods("GET")
@@ -63,6 +85,34 @@ func main() {
 	// Users endpoints (usando Supabase Auth)
 	api.HandleFunc("/me", handlers.GetCurrentUserHandler).Methods("GET")
 	api.HandleFunc("/me/stats", handlers.GetUserStatsHandler).Methods("GET")
+	api.HandleFunc("/me/calendar", handlers.GetCalendarHandler).Methods("GET")
+	api.HandleFunc("/me/muscle-volume", handlers.GetMuscleVolumeHandler).Methods("GET")
+	api.HandleFunc("/me/export", handlers.ExportWorkoutsHandler).Methods("GET")
+	api.HandleFunc("/me/import", handlers.ImportWorkoutsHandler).Methods("POST")
+	api.HandleFunc("/me/records", handlers.GetPersonalRecordsHandler).Methods("GET")
+	api.HandleFunc("/me/records/history", handlers.GetPersonalRecordsHistoryHandler).Methods("GET")
+	api.HandleFunc("/me/live-session", handlers.GetLiveSessionHandler).Methods("GET")
+	api.HandleFunc("/me/live-session", handlers.StartLiveSessionHandler).Methods("POST")
+	api.HandleFunc("/me/live-session", handlers.UpdateLiveSessionHandler).Methods("PUT")
+	api.HandleFunc("/me/live-session", handlers.EndLiveSessionHandler).Methods("DELETE")
+	api.HandleFunc("/me/live-session/rest", handlers.StartLiveRestHandler).Methods("POST")
+	api.HandleFunc("/me/live-session/rest", handlers.StopLiveRestHandler).Methods("DELETE")
+	api.HandleFunc("/me/live-session/rest/pause", handlers.PauseLiveRestHandler).Methods("POST")
+	api.HandleFunc("/me/live-session/rest/resume", handlers.ResumeLiveRestHandler).Methods("POST")
+	api.HandleFunc("/me/trash", handlers.GetTrashHandler).Methods("GET")
+	api.HandleFunc("/me/measurements", handlers.GetBodyMeasurementsHandler).Methods("GET")
+	api.HandleFunc("/me/measurements", handlers.CreateBodyMeasurementHandler).Methods("POST")
+	api.HandleFunc("/me/measurements/trend", handlers.GetMeasurementTrendHandler).Methods("GET")
+	api.HandleFunc("/me/measurements/{id}", handlers.UpdateBodyMeasurementHandler).Methods("PUT")
+	api.HandleFunc("/me/measurements/{id}", handlers.DeleteBodyMeasurementHandler).Methods("DELETE")
+	api.HandleFunc("/me/readiness", handlers.GetReadinessHandler).Methods("GET")
+	api.HandleFunc("/me/schedule", handlers.GetRoutineSchedulesHandler).Methods("GET")
+	api.HandleFunc("/me/schedule", handlers.CreateRoutineScheduleHandler).Methods("POST")
+	api.HandleFunc("/me/schedule/today", handlers.GetScheduleTodayHandler).Methods("GET")
+	api.HandleFunc("/me/schedule/upcoming", handlers.GetUpcomingSessionsHandler).Methods("GET")
+	api.HandleFunc("/me/schedule/week", handlers.GetWeeklySchedulePlanHandler).Methods("GET")
+	api.HandleFunc("/me/schedule/{id}", handlers.UpdateRoutineScheduleHandler).Methods("PUT")
+	api.HandleFunc("/me/schedule/{id}", handlers.DeleteRoutineScheduleHandler).Methods("DELETE")
 	api.HandleFunc("/me/last-signin", handlers.UpdateLastSignInHandler).Methods("POST")
 	api.HandleFunc("/me/setup", handlers.UserSetupHandler).Methods("POST")
 
@@ -105,6 +155,7 @@ func main() {
 	api.HandleFunc("/admin/notifications/{id}/history", handlers.AdminStaffOrTeacherMiddleware(handlers.GetNotificationHistoryHandler)).Methods("GET")
 	api.HandleFunc("/admin/exercises", handlers.AdminOrTeacherMiddleware(handlers.GetAdminExercisesHandler)).Methods("GET")
 	api.HandleFunc("/admin/exercises", handlers.AdminOrTeacherMiddleware(handlers.CreateExerciseHandler)).Methods("POST")
+	api.HandleFunc("/admin/muscle-groups/{id}/targets", handlers.AdminOrTeacherMiddleware(handlers.UpdateMuscleGroupTargetsHandler)).Methods("PUT")
 	api.HandleFunc("/admin/users", handlers.AdminMiddleware(handlers.GetAdminUsersHandler)).Methods("GET")
 	api.HandleFunc("/admin/users/{id}", handlers.AdminMiddleware(handlers.DeleteAdminUserHandler)).Methods("DELETE")
 	api.HandleFunc("/admin/users/{id}/role", handlers.AdminMiddleware(handlers.UpdateAdminUserRoleHandler)).Methods("PUT")
@@ -116,6 +167,21 @@ func main() {
 	api.HandleFunc("/routines/{id}", handlers.GetUserRoutineHandler).Methods("GET")
 	api.HandleFunc("/routines/{id}", handlers.UpdateUserRoutineHandler).Methods("PUT")
 	api.HandleFunc("/routines/{id}", handlers.DeleteUserRoutineHandler).Methods("DELETE")
+	api.HandleFunc("/routines/{id}/restore", handlers.RestoreUserRoutineHandler).Methods("POST")
+	api.HandleFunc("/routines/{id}/start", handlers.StartRoutineHandler).Methods("POST")
+	api.HandleFunc("/routines/{id}/exercises/{routine_exercise_id}", handlers.UpdateRoutineExerciseHandler).Methods("PUT")
+
+	// Programs endpoints (los crean y modifican profesores; cualquier usuario puede seguirlos)
+	api.HandleFunc("/programs", handlers.GetProgramsHandler).Methods("GET")
+	api.HandleFunc("/programs", handlers.AdminOrTeacherMiddleware(handlers.CreateProgramHandler)).Methods("POST")
+	api.HandleFunc("/programs/{id}", handlers.GetProgramHandler).Methods("GET")
+	api.HandleFunc("/programs/{id}", handlers.AdminOrTeacherMiddleware(handlers.UpdateProgramHandler)).Methods("PUT")
+	api.HandleFunc("/programs/{id}", handlers.AdminOrTeacherMiddleware(handlers.DeleteProgramHandler)).Methods("DELETE")
+	api.HandleFunc("/programs/{id}/enroll", handlers.EnrollProgramHandler).Methods("POST")
+	api.HandleFunc("/me/program", handlers.GetProgramEnrollmentHandler).Methods("GET")
+	api.HandleFunc("/me/program", handlers.AbandonProgramHandler).Methods("DELETE")
+	api.HandleFunc("/me/program/start", handlers.StartProgramSessionHandler).Methods("POST")
+	api.HandleFunc("/me/program/advance", handlers.AdvanceProgramHandler).Methods("POST")
 
 	// Configurar CORS
 	corsOrigins := os.Getenv("CORS_ALLOWED_ORIGINS")
@@ -137,6 +203,7 @@ func main() {
 			"X-Requested-With",
 			"Accept",
 			"Origin",
+			"X-Timezone",
 		},
 		AllowCredentials: true,
 	})
@@ -0,0 +1,45 @@
+package models
+
+// Estados posibles de un día del calendario
+const (
+	CalendarDayTrained = "trained" // Tiene al menos una serie registrada
+	CalendarDayRest    = "rest"    // Día pasado (o de hoy) sin entrenamiento
+	CalendarDayFuture  = "future"  // Día posterior a hoy
+)
+
+// CalendarDay representa un día del calendario de entrenamiento
+type CalendarDay struct {
+	Date         string  `json:"date"` // Formato YYYY-MM-DD
+	Status       string  `json:"status"`
+	WorkoutDayID *int    `json:"workout_day_id,omitempty"`
+	Sets         int     `json:"sets"`
+	Volume       float64 `json:"volume"`
+	Effort       int     `json:"effort"`
+	Mood         int     `json:"mood"`
+	Level        int     `json:"level"` // Intensidad 0-4 para el heatmap, relativa al día con más series del período
+}
+
+// CalendarWeek representa el objetivo de sesiones de una semana contra lo realizado
+type CalendarWeek struct {
+	WeekStart string `json:"week_start"` // Formato YYYY-MM-DD (lunes)
+	Sessions  int    `json:"sessions"`
+	Target    int    `json:"target"`
+	Met       bool   `json:"met"`
+	Completed bool   `json:"completed"` // false si la semana todavía no terminó
+}
+
+// TrainingCalendar representa el calendario de un mes o un año
+type TrainingCalendar struct {
+	Period         string         `json:"period"` // "month" o "year"
+	From           string         `json:"from"`
+	To             string         `json:"to"`
+	Timezone       string         `json:"timezone"`
+	WeightUnit     string         `json:"weight_unit"` // Unidad del volumen de cada día
+	WeeklyTarget   int            `json:"weekly_target"`
+	TotalSessions  int            `json:"total_sessions"`
+	WeeksMet       int            `json:"weeks_met"`
+	WeeksCompleted int            `json:"weeks_completed"`
+	AdherenceRate  float64        `json:"adherence_rate"` // Semanas terminadas que cumplieron el objetivo (0-1)
+	Days           []CalendarDay  `json:"days"`
+	Weeks          []CalendarWeek `json:"weeks"`
+}
@@ -0,0 +1,29 @@
+package models
+
+// CardioMetrics son las métricas de una serie de cardio o deporte (ejercicios is_sport).
+// La duración es el campo seconds de la serie.
+type CardioMetrics struct {
+	DistanceMeters      *float64 `json:"distance_meters,omitempty" db:"distance_meters"`
+	ElevationGainMeters *float64 `json:"elevation_gain_meters,omitempty" db:"elevation_gain_meters"`
+	AvgHeartRate        *int     `json:"avg_heart_rate,omitempty" db:"avg_heart_rate"`
+	MaxHeartRate        *int     `json:"max_heart_rate,omitempty" db:"max_heart_rate"`
+	Calories            *int     `json:"calories,omitempty" db:"calories"`
+}
+
+// IsEmpty indica si no se cargó ninguna métrica de cardio
+func (m CardioMetrics) IsEmpty() bool {
+	return m.DistanceMeters == nil && m.ElevationGainMeters == nil &&
+		m.AvgHeartRate == nil && m.MaxHeartRate == nil && m.Calories == nil
+}
+
+// SportStats resume un deporte o ejercicio de cardio en el rango consultado
+type SportStats struct {
+	ExerciseID       int      `json:"exercise_id"`
+	ExerciseName     string   `json:"exercise_name"`
+	Sessions         int      `json:"sessions"`
+	Entries          int      `json:"entries"`
+	DistanceMeters   float64  `json:"distance_meters"`
+	DurationSeconds  int      `json:"duration_seconds"`
+	Calories         int      `json:"calories"`
+	PaceSecondsPerKm *float64 `json:"pace_seconds_per_km"` // Promedio de las entradas con distancia y duración
+}
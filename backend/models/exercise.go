@@ -15,6 +15,7 @@ type Exercise struct {
 	VideoURL         *string  `json:"video_url" db:"video_url"`
 	Bodyweight       bool     `json:"bodyweight" db:"bodyweight"`
 	IsSport          bool     `json:"is_sport" db:"is_sport"`
+	TracksDistance   bool     `json:"tracks_distance" db:"tracks_distance"` // Deporte que se registra con distancia
 	CreatedAt        time.Time `json:"created_at" db:"created_at"`
 }
 
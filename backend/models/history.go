@@ -0,0 +1,11 @@
+package models
+
+// WorkoutHistoryPage representa una página del historial de series. Sin agrupar trae las series
+// en workouts; con group_by=day trae los días en days, cada uno solo con las series que cumplen los filtros.
+type WorkoutHistoryPage struct {
+	GroupBy    string                    `json:"group_by"` // set o day
+	Workouts   []Workout                 `json:"workouts,omitempty"`
+	Days       []WorkoutDayWithExercises `json:"days,omitempty"`
+	WeightUnit string                    `json:"weight_unit"`
+	NextCursor *string                   `json:"next_cursor"` // null si no hay más resultados
+}
@@ -0,0 +1,42 @@
+package models
+
+// Formatos de CSV que se pueden importar
+const (
+	ImportFormatStrong = "strong"
+	ImportFormatHevy   = "hevy"
+)
+
+// ImportExerciseMatch representa cómo se resolvió un nombre de ejercicio del CSV
+type ImportExerciseMatch struct {
+	SourceName   string  `json:"source_name"`
+	ExerciseID   *int    `json:"exercise_id"`
+	ExerciseName *string `json:"exercise_name"`
+	MatchedBy    string  `json:"matched_by"` // "name", "mapping", "skipped" o "" si no se encontró
+	Rows         int     `json:"rows"`
+}
+
+// ImportRowError representa una fila del CSV que no se puede importar
+type ImportRowError struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// ImportSummary representa el resultado de una vista previa o de una importación confirmada
+type ImportSummary struct {
+	Format           string                `json:"format"`
+	Committed        bool                  `json:"committed"`
+	TotalRows        int                   `json:"total_rows"`
+	ImportableRows   int                   `json:"importable_rows"`
+	DuplicateRows    int                   `json:"duplicate_rows"` // Ya importadas anteriormente
+	SkippedRows      int                   `json:"skipped_rows"`   // Ejercicios mapeados como omitidos
+	InvalidRows      int                   `json:"invalid_rows"`
+	UnknownRows      int                   `json:"unknown_rows"` // Filas de ejercicios sin mapear
+	Days             int                   `json:"days"`
+	From             string                `json:"from,omitempty"`
+	To               string                `json:"to,omitempty"`
+	Exercises        []ImportExerciseMatch `json:"exercises"`
+	UnknownExercises []string              `json:"unknown_exercises"`
+	Errors           []ImportRowError      `json:"errors"`
+	CreatedDays      int                   `json:"created_days"`
+	ImportedSets     int                   `json:"imported_sets"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Estados de una sesión en vivo
+const (
+	LiveSessionActive  = "active"  // Entre series, sin descanso en curso
+	LiveSessionResting = "resting" // Descanso corriendo
+	LiveSessionPaused  = "paused"  // Descanso pausado
+)
+
+// LiveSession representa el entrenamiento en curso de un usuario. Se guarda en el servidor
+// para que cualquier dispositivo pueda retomarlo; vence sola tras unas horas sin actividad.
+type LiveSession struct {
+	UserID              string     `json:"user_id" db:"user_id"`
+	WorkoutDayID        int        `json:"workout_day_id" db:"workout_day_id"`
+	RoutineID           *int       `json:"routine_id" db:"routine_id"` // Rutina iniciada en el día, si la hay
+	CurrentExerciseID   *int       `json:"current_exercise_id" db:"current_exercise_id"`
+	CurrentExerciseName *string    `json:"current_exercise_name"`
+	RestStartedAt       *time.Time `json:"rest_started_at" db:"rest_started_at"`
+	RestDurationSeconds *int       `json:"rest_duration_seconds" db:"rest_duration_seconds"`
+	PausedAt            *time.Time `json:"paused_at" db:"paused_at"`
+	StartedAt           time.Time  `json:"started_at" db:"started_at"`
+	UpdatedAt           time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Calculados al leer
+	Status               string              `json:"status"`
+	RestRemainingSeconds int                 `json:"rest_remaining_seconds"`
+	RestEndsAt           *time.Time          `json:"rest_ends_at"` // Solo con el descanso corriendo
+	ExpiresAt            time.Time           `json:"expires_at"`
+	ServerTime           time.Time           `json:"server_time"` // Para sincronizar el reloj del cliente
+	NextSet              *LiveSessionNextSet `json:"next_set"`
+}
+
+// LiveSessionNextSet es la próxima serie a hacer: la primera pendiente del plan de la rutina
+// o, sin rutina, la siguiente del ejercicio actual
+type LiveSessionNextSet struct {
+	ExerciseID      int      `json:"exercise_id"`
+	ExerciseName    string   `json:"exercise_name"`
+	Set             int      `json:"set"`
+	PlannedReps     *int     `json:"planned_reps"`
+	PlannedWeight   *float64 `json:"planned_weight"`
+	RestTimeSeconds *int     `json:"rest_time_seconds"`
+	FromRoutine     bool     `json:"from_routine"`
+	WeightUnit      string   `json:"weight_unit,omitempty"`
+}
+
+// StartLiveSessionRequest representa la solicitud para iniciar una sesión en vivo.
+// Sin date ni workout_day_id se usa el día de hoy.
+type StartLiveSessionRequest struct {
+	Date              *string `json:"date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	WorkoutDayID      *int    `json:"workout_day_id,omitempty" validate:"omitempty,gt=0"`
+	CurrentExerciseID *int    `json:"current_exercise_id,omitempty" validate:"omitempty,gt=0"`
+}
+
+// UpdateLiveSessionRequest representa la solicitud para cambiar el ejercicio actual
+type UpdateLiveSessionRequest struct {
+	CurrentExerciseID *int `json:"current_exercise_id" validate:"required,gt=0"`
+}
+
+// StartRestRequest representa la solicitud para iniciar el descanso.
+// Sin seconds se usa el descanso planificado del ejercicio actual.
+type StartRestRequest struct {
+	Seconds *int `json:"seconds,omitempty" validate:"omitempty,gte=0,lte=3600"`
+}
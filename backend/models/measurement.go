@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// Tipos de medida corporal. El peso corporal se guarda en kilos, la grasa corporal
+// en porcentaje y los contornos en centímetros.
+const (
+	MeasurementBodyweight = "bodyweight"
+	MeasurementBodyFat    = "body_fat"
+	MeasurementArm        = "arm"
+	MeasurementWaist      = "waist"
+	MeasurementThigh      = "thigh"
+	MeasurementChest      = "chest"
+	MeasurementHips       = "hips"
+)
+
+// MeasurementTypes son los tipos de medida permitidos
+var MeasurementTypes = []string{
+	MeasurementBodyweight, MeasurementBodyFat, MeasurementArm, MeasurementWaist,
+	MeasurementThigh, MeasurementChest, MeasurementHips,
+}
+
+// BodyMeasurement representa una medida corporal de un día
+type BodyMeasurement struct {
+	ID         int       `json:"id" db:"id"`
+	UserID     string    `json:"user_id" db:"user_id"`
+	Type       string    `json:"type" db:"measurement_type"`
+	Value      float64   `json:"value" db:"value"`             // Peso corporal guardado en kg, se devuelve en unit
+	Unit       string    `json:"unit" db:"-"`                  // kg o lb, % o cm según el tipo
+	MeasuredOn string    `json:"measured_on" db:"measured_on"` // Formato YYYY-MM-DD
+	Notes      *string   `json:"notes" db:"notes"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateBodyMeasurementRequest representa la solicitud para registrar una medida
+type CreateBodyMeasurementRequest struct {
+	Type       string  `json:"type" validate:"required,oneof=bodyweight body_fat arm waist thigh chest hips"`
+	Value      float64 `json:"value" validate:"required,gt=0"`
+	WeightUnit *string `json:"weight_unit,omitempty" validate:"omitempty,oneof=kg lb"`         // Solo peso corporal; por defecto la unidad del usuario
+	MeasuredOn *string `json:"measured_on,omitempty" validate:"omitempty,datetime=2006-01-02"` // Por defecto hoy
+	Notes      *string `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// UpdateBodyMeasurementRequest representa la solicitud para corregir una medida
+type UpdateBodyMeasurementRequest struct {
+	Value      *float64 `json:"value,omitempty" validate:"omitempty,gt=0"`
+	WeightUnit *string  `json:"weight_unit,omitempty" validate:"omitempty,oneof=kg lb"`
+	MeasuredOn *string  `json:"measured_on,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Notes      *string  `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// MeasurementTrendPoint representa una medida con su promedio móvil
+type MeasurementTrendPoint struct {
+	Date          string  `json:"date"` // Formato YYYY-MM-DD
+	Value         float64 `json:"value"`
+	MovingAverage float64 `json:"moving_average"` // Promedio de las medidas de los últimos window_days días
+}
+
+// MeasurementTrend representa la evolución de un tipo de medida en un rango de fechas
+type MeasurementTrend struct {
+	Type       string                  `json:"type"`
+	Unit       string                  `json:"unit"`
+	From       string                  `json:"from,omitempty"`
+	To         string                  `json:"to,omitempty"`
+	WindowDays int                     `json:"window_days"`
+	Points     []MeasurementTrendPoint `json:"points"`
+	Change     *float64                `json:"change"` // Diferencia entre el último y el primer promedio móvil
+}
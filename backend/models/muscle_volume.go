@@ -0,0 +1,52 @@
+package models
+
+// Estado de un grupo muscular respecto de su rango objetivo de series semanales
+const (
+	MuscleVolumeUnder  = "under"
+	MuscleVolumeWithin = "within"
+	MuscleVolumeOver   = "over"
+)
+
+// MuscleGroupVolume representa las series efectivas y el volumen de un grupo muscular.
+// Las series donde el grupo es secundario cuentan con la fracción indicada en el reporte.
+type MuscleGroupVolume struct {
+	MuscleGroupID   int     `json:"muscle_group_id"`
+	MuscleGroupName string  `json:"muscle_group_name"`
+	PrimarySets     int     `json:"primary_sets"`
+	SecondarySets   int     `json:"secondary_sets"`
+	HardSets        float64 `json:"hard_sets"` // primary_sets + secondary_sets × secondary_fraction
+	Volume          float64 `json:"volume"`    // Peso × repeticiones, con la misma ponderación
+	TargetMinSets   int     `json:"target_min_sets"`
+	TargetMaxSets   int     `json:"target_max_sets"`
+	Status          string  `json:"status"` // under, within u over
+}
+
+// WeeklyMuscleVolume representa el volumen por grupo muscular de una semana (de lunes a domingo)
+type WeeklyMuscleVolume struct {
+	WeekStart    string              `json:"week_start"` // Formato YYYY-MM-DD (lunes)
+	MuscleGroups []MuscleGroupVolume `json:"muscle_groups"`
+}
+
+// MuscleVolumeReport representa el volumen semanal por grupo muscular en un rango de fechas
+type MuscleVolumeReport struct {
+	From              string               `json:"from"`
+	To                string               `json:"to"`
+	SecondaryFraction float64              `json:"secondary_fraction"`
+	WeightUnit        string               `json:"weight_unit"`
+	Weeks             []WeeklyMuscleVolume `json:"weeks"`
+	Average           []MuscleGroupVolume  `json:"average"` // Series efectivas y volumen promedio por semana; primary_sets y secondary_sets son totales
+}
+
+// MuscleGroupTarget representa el rango objetivo de series efectivas por semana de un grupo muscular
+type MuscleGroupTarget struct {
+	MuscleGroupID   int    `json:"muscle_group_id"`
+	MuscleGroupName string `json:"muscle_group_name"`
+	TargetMinSets   int    `json:"target_min_sets"`
+	TargetMaxSets   int    `json:"target_max_sets"`
+}
+
+// UpdateMuscleGroupTargetsRequest representa la solicitud para configurar el rango objetivo de un grupo muscular
+type UpdateMuscleGroupTargetsRequest struct {
+	TargetMinSets int `json:"target_min_sets" validate:"gte=0,lte=100"`
+	TargetMaxSets int `json:"target_max_sets" validate:"gte=0,lte=100"`
+}
@@ -0,0 +1,109 @@
+package models
+
+import "time"
+
+// Estados del seguimiento de un programa
+const (
+	ProgramEnrollmentActive    = "active"
+	ProgramEnrollmentCompleted = "completed"
+	ProgramEnrollmentAbandoned = "abandoned"
+)
+
+// Program representa un programa de entrenamiento (mesociclo): una semana tipo de rutinas
+// que se repite durante Weeks semanas, con ajustes por semana
+type Program struct {
+	ID              int           `json:"id"`
+	AuthorID        string        `json:"author_id"`
+	AuthorName      *string       `json:"author_name"`
+	Name            string        `json:"name"`
+	Description     *string       `json:"description"`
+	Weeks           int           `json:"weeks"`
+	DaysPerWeek     int           `json:"days_per_week"`
+	IsPublished     bool          `json:"is_published"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+	Days            []ProgramDay  `json:"days,omitempty"`
+	WeekAdjustments []ProgramWeek `json:"week_adjustments,omitempty"` // Una entrada por semana, de la 1 a Weeks
+}
+
+// ProgramDay representa un día de la semana tipo del programa
+type ProgramDay struct {
+	DayNumber   int               `json:"day_number"`
+	RoutineID   int               `json:"routine_id"`
+	RoutineName string            `json:"routine_name"`
+	Exercises   []RoutineExercise `json:"exercises,omitempty"`
+}
+
+// ProgramWeek representa los ajustes de una semana sobre lo planificado en las rutinas.
+// En las semanas de descarga las series se reducen a la mitad antes de aplicar SetsAdjustment.
+type ProgramWeek struct {
+	Week             int     `json:"week"`
+	SetsAdjustment   int     `json:"sets_adjustment"`   // Series que se suman a cada ejercicio
+	RepsAdjustment   int     `json:"reps_adjustment"`   // Repeticiones que se suman a cada serie
+	IntensityPercent float64 `json:"intensity_percent"` // Porcentaje del peso planificado
+	IsDeload         bool    `json:"is_deload"`
+	Notes            *string `json:"notes"`
+}
+
+// ProgramWeekRequest representa los ajustes de una semana al crear o actualizar un programa.
+// Sin intensity_percent se usa el 100% o, en semanas de descarga, el 60%.
+type ProgramWeekRequest struct {
+	Week             int      `json:"week" validate:"required,gt=0,lte=52"`
+	SetsAdjustment   int      `json:"sets_adjustment,omitempty" validate:"gte=-10,lte=10"`
+	RepsAdjustment   int      `json:"reps_adjustment,omitempty" validate:"gte=-50,lte=50"`
+	IntensityPercent *float64 `json:"intensity_percent,omitempty" validate:"omitempty,gt=0,lte=200"`
+	IsDeload         bool     `json:"is_deload,omitempty"`
+	Notes            *string  `json:"notes,omitempty"`
+}
+
+// CreateProgramRequest representa la solicitud para crear un programa.
+// routine_ids son las rutinas de la semana tipo, en orden.
+type CreateProgramRequest struct {
+	Name            string               `json:"name" validate:"required,min=1,max=255"`
+	Description     string               `json:"description,omitempty"`
+	Weeks           int                  `json:"weeks" validate:"required,gt=0,lte=52"`
+	IsPublished     bool                 `json:"is_published,omitempty"`
+	RoutineIDs      []int                `json:"routine_ids" validate:"required,min=1,max=14"`
+	WeekAdjustments []ProgramWeekRequest `json:"week_adjustments,omitempty"`
+}
+
+// UpdateProgramRequest representa la solicitud para actualizar un programa.
+// routine_ids y week_adjustments reemplazan a los anteriores cuando se envían.
+type UpdateProgramRequest struct {
+	Name            *string              `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Description     *string              `json:"description,omitempty"`
+	Weeks           *int                 `json:"weeks,omitempty" validate:"omitempty,gt=0,lte=52"`
+	IsPublished     *bool                `json:"is_published,omitempty"`
+	RoutineIDs      []int                `json:"routine_ids,omitempty" validate:"omitempty,min=1,max=14"`
+	WeekAdjustments []ProgramWeekRequest `json:"week_adjustments,omitempty"`
+}
+
+// ProgramSession representa la sesión que toca en un programa, con la rutina ajustada a la semana
+type ProgramSession struct {
+	Week        int               `json:"week"`
+	DayNumber   int               `json:"day_number"`
+	RoutineID   int               `json:"routine_id"`
+	RoutineName string            `json:"routine_name"`
+	WeightUnit  string            `json:"weight_unit"`
+	Adjustments ProgramWeek       `json:"adjustments"`
+	Exercises   []RoutineExercise `json:"exercises"`
+}
+
+// ProgramEnrollment representa el seguimiento de un programa por un usuario
+type ProgramEnrollment struct {
+	ID                  int             `json:"id"`
+	ProgramID           int             `json:"program_id"`
+	ProgramName         string          `json:"program_name"`
+	Status              string          `json:"status"`
+	Weeks               int             `json:"weeks"`
+	DaysPerWeek         int             `json:"days_per_week"`
+	CurrentWeek         int             `json:"current_week"`
+	CurrentDay          int             `json:"current_day"`
+	CompletedSessions   int             `json:"completed_sessions"`
+	TotalSessions       int             `json:"total_sessions"`
+	Progress            float64         `json:"progress"`               // Sesiones completadas (0-1)
+	CurrentWorkoutDayID *int            `json:"current_workout_day_id"` // Día en el que se inició la sesión actual, si ya se inició
+	StartedAt           time.Time       `json:"started_at"`
+	CompletedAt         *time.Time      `json:"completed_at"`
+	NextSession         *ProgramSession `json:"next_session,omitempty"`
+}
@@ -0,0 +1,24 @@
+package models
+
+// ProgressBucket representa las métricas de un ejercicio en una semana o un mes
+type ProgressBucket struct {
+	PeriodStart  string  `json:"period_start"` // Formato YYYY-MM-DD (lunes de la semana o primer día del mes)
+	TopSetWeight float64 `json:"top_set_weight"`
+	TopSetReps   int     `json:"top_set_reps"`
+	Estimated1RM float64 `json:"estimated_1rm"`
+	TotalVolume  float64 `json:"total_volume"` // Suma de peso × repeticiones
+	TotalReps    int     `json:"total_reps"`
+	TotalSets    int     `json:"total_sets"`
+	Sessions     int     `json:"sessions"`
+}
+
+// ExerciseProgress representa la serie temporal de progreso de un ejercicio
+type ExerciseProgress struct {
+	ExerciseID   int              `json:"exercise_id"`
+	ExerciseName string           `json:"exercise_name"`
+	Bucket       string           `json:"bucket"` // "week" o "month"
+	WeightUnit   string           `json:"weight_unit"`
+	From         string           `json:"from"`
+	To           string           `json:"to"`
+	Buckets      []ProgressBucket `json:"buckets"`
+}
@@ -0,0 +1,55 @@
+package models
+
+// Reglas de progresión
+const (
+	ProgressionDouble = "double_progression" // Sumar repeticiones dentro del rango y luego peso
+	ProgressionLinear = "linear"             // Sumar peso cada vez que se cumple lo planificado
+)
+
+// Acciones sugeridas para la próxima sesión
+const (
+	ProgressionActionStart          = "start"           // Sin historial: lo planificado
+	ProgressionActionIncreaseWeight = "increase_weight" // Subir el peso
+	ProgressionActionIncreaseReps   = "increase_reps"   // Mismo peso, más repeticiones
+	ProgressionActionRepeat         = "repeat"          // Repetir hasta cumplir
+	ProgressionActionDeload         = "deload"          // Bajar el peso tras varias sesiones sin cumplir
+)
+
+// ProgressionConfig son los parámetros de progresión de un ejercicio de rutina.
+// Los campos vacíos usan los valores por defecto.
+type ProgressionConfig struct {
+	ProgressionRule   *string  `json:"progression_rule" db:"progression_rule" validate:"omitempty,oneof=double_progression linear"`
+	RepRangeMin       *int     `json:"rep_range_min" db:"rep_range_min" validate:"omitempty,gt=0,lte=100"` // Por defecto las reps planificadas
+	RepRangeMax       *int     `json:"rep_range_max" db:"rep_range_max" validate:"omitempty,gt=0,lte=100"` // Por defecto rep_range_min + 4
+	WeightIncrement   *float64 `json:"weight_increment" db:"weight_increment" validate:"omitempty,gt=0,lte=50"`
+	DeloadAfterMisses *int     `json:"deload_after_misses" db:"deload_after_misses" validate:"omitempty,gte=0,lte=10"` // 0 desactiva la descarga
+	DeloadPercent     *float64 `json:"deload_percent" db:"deload_percent" validate:"omitempty,gt=0,lte=50"`
+}
+
+// ProgressionSession resume las series efectivas de una sesión de un ejercicio
+// con el peso más alto que se usó
+type ProgressionSession struct {
+	Date    string  `json:"date"` // Formato YYYY-MM-DD
+	Weight  float64 `json:"weight"`
+	Sets    int     `json:"sets"`     // Series con ese peso
+	MinReps int     `json:"min_reps"` // Menor cantidad de repeticiones entre esas series
+	Met     bool    `json:"met"`      // Se cumplieron las series y repeticiones objetivo
+}
+
+// ProgressionSuggestion es la sugerencia de peso y repeticiones para la próxima sesión de un ejercicio
+type ProgressionSuggestion struct {
+	ExerciseID        int                 `json:"exercise_id"`
+	ExerciseName      string              `json:"exercise_name"`
+	RoutineExerciseID *int                `json:"routine_exercise_id"`
+	Rule              string              `json:"rule"`
+	Action            string              `json:"action"`
+	Weight            *float64            `json:"weight"` // Vacío si no hay peso de referencia
+	Reps              int                 `json:"reps"`
+	Sets              int                 `json:"sets"`
+	RepRangeMin       int                 `json:"rep_range_min"`
+	RepRangeMax       int                 `json:"rep_range_max"`
+	Reason            string              `json:"reason"`
+	ConsecutiveMisses int                 `json:"consecutive_misses"`
+	LastSession       *ProgressionSession `json:"last_session"`
+	WeightUnit        string              `json:"weight_unit"`
+}
@@ -0,0 +1,56 @@
+package models
+
+// Estado de recuperación según las señales de fatiga detectadas
+const (
+	ReadinessReady    = "ready"    // Sin señales de fatiga
+	ReadinessCaution  = "caution"  // Una señal: conviene vigilar
+	ReadinessFatigued = "fatigued" // Dos o más señales: se recomienda una semana de descarga
+)
+
+// Tipos de señal de fatiga
+const (
+	FatigueSignalEffortRising    = "effort_rising"
+	FatigueSignalMoodFalling     = "mood_falling"
+	FatigueSignalPerformanceDrop = "performance_drop"
+	FatigueSignalMissedReps      = "missed_reps"
+	FatigueSignalVolumeSpike     = "volume_spike"
+)
+
+// FatigueSignal representa una señal de fatiga acumulada: el valor de la última semana
+// comparado con la referencia de las semanas anteriores
+type FatigueSignal struct {
+	Type     string  `json:"type"`
+	Message  string  `json:"message"`
+	Value    float64 `json:"value"`
+	Baseline float64 `json:"baseline"`
+}
+
+// ExercisePerformanceDrop representa una caída del 1RM estimado de un ejercicio con un esfuerzo similar
+type ExercisePerformanceDrop struct {
+	ExerciseID     int     `json:"exercise_id"`
+	ExerciseName   string  `json:"exercise_name"`
+	Date           string  `json:"date"`          // Sesión más reciente, formato YYYY-MM-DD
+	Estimated1RM   float64 `json:"estimated_1rm"` // En la unidad del usuario
+	Previous1RM    float64 `json:"previous_1rm"`  // Mejor 1RM estimado anterior con esfuerzo similar
+	PreviousDate   string  `json:"previous_date"`
+	DropPercentage float64 `json:"drop_percentage"`
+}
+
+// ReadinessReport representa la recuperación del usuario según el esfuerzo y el ánimo de sus
+// entrenamientos, el rendimiento por ejercicio, las repeticiones logradas y el volumen semanal
+type ReadinessReport struct {
+	Date                 string                    `json:"date"` // Formato YYYY-MM-DD
+	Status               string                    `json:"status"`
+	Score                int                       `json:"score"` // 0 a 100
+	DeloadRecommended    bool                      `json:"deload_recommended"`
+	RecentEffort         *float64                  `json:"recent_effort"` // Promedio de los últimos 7 días
+	BaselineEffort       *float64                  `json:"baseline_effort"`
+	RecentMood           *float64                  `json:"recent_mood"`
+	BaselineMood         *float64                  `json:"baseline_mood"`
+	WeeklyVolume         float64                   `json:"weekly_volume"`
+	BaselineWeeklyVolume float64                   `json:"baseline_weekly_volume"` // Promedio semanal de las 3 semanas anteriores
+	WeightUnit           string                    `json:"weight_unit"`
+	MissedRepRate        *float64                  `json:"missed_rep_rate"` // Series de rutina por debajo de las repeticiones planificadas (0 a 1)
+	PerformanceDrops     []ExercisePerformanceDrop `json:"performance_drops"`
+	Signals              []FatigueSignal           `json:"signals"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Tipos de récord personal
+const (
+	RecordTypeMaxWeight         = "max_weight"
+	RecordTypeMaxRepsAtWeight   = "max_reps_at_weight"
+	RecordTypeEstimated1RM      = "estimated_1rm"
+	RecordTypeBestSetVolume     = "best_set_volume"
+	RecordTypeBestSessionVolume = "best_session_volume"
+)
+
+// PersonalRecord representa un récord personal de un usuario en un ejercicio
+type PersonalRecord struct {
+	ID            int       `json:"id" db:"id"`
+	UserID        string    `json:"user_id" db:"user_id"`
+	ExerciseID    int       `json:"exercise_id" db:"exercise_id"`
+	ExerciseName  string    `json:"exercise_name" db:"exercise_name"`
+	RecordType    string    `json:"record_type" db:"record_type"`
+	Value         float64   `json:"value" db:"value"`
+	Weight        *float64  `json:"weight" db:"weight"` // Peso de referencia (max_reps_at_weight) o de la serie
+	Reps          *int      `json:"reps" db:"reps"`
+	PreviousValue *float64  `json:"previous_value" db:"previous_value"`
+	WorkoutID     *int      `json:"workout_id" db:"workout_id"`
+	WorkoutDayID  *int      `json:"workout_day_id" db:"workout_day_id"`
+	AchievedOn    string    `json:"achieved_on" db:"achieved_on"` // Formato YYYY-MM-DD
+	WeightUnit    string    `json:"weight_unit,omitempty" db:"-"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// ExerciseRecords agrupa los récords actuales y el historial de un ejercicio
+type ExerciseRecords struct {
+	ExerciseID int              `json:"exercise_id"`
+	Current    []PersonalRecord `json:"current"`
+	History    []PersonalRecord `json:"history"`
+}
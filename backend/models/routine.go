@@ -24,16 +24,23 @@ type RoutineExercise struct {
 	Sets            int       `json:"sets" db:"sets"`
 	Reps            int       `json:"reps" db:"reps"`
 	Weight          *float64  `json:"weight" db:"weight"`
+	WeightUnit      string    `json:"weight_unit,omitempty" db:"-"`
 	RestTimeSeconds int       `json:"rest_time_seconds" db:"rest_time_seconds"`
 	Notes           *string   `json:"notes" db:"notes"`
-	CreatedAt       time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	// Los ejercicios con el mismo block_index forman una superserie o circuito
+	BlockIndex           *int      `json:"block_index" db:"block_index"`
+	BlockType            *string   `json:"block_type" db:"block_type"`
+	BlockRestTimeSeconds *int      `json:"block_rest_time_seconds" db:"block_rest_time_seconds"`
+	ProgressionConfig
+	CreatedAt            time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // CreateRoutineRequest representa la solicitud para crear una rutina
 type CreateRoutineRequest struct {
 	Name        string `json:"name" validate:"required,min=1,max=255"`
 	Description string `json:"description,omitempty"`
+	WeightUnit  *string `json:"weight_unit,omitempty" validate:"omitempty,oneof=kg lb"` // Unidad de los pesos; por defecto la del usuario
 	Exercises   []CreateRoutineExerciseRequest `json:"exercises,omitempty"`
 }
 
@@ -46,6 +53,10 @@ type CreateRoutineExerciseRequest struct {
 	Weight          *float64 `json:"weight,omitempty" validate:"omitempty,gt=0,lte=1000"`
 	RestTimeSeconds int     `json:"rest_time_seconds" validate:"required,gte=0,lte=3600"`
 	Notes           string  `json:"notes,omitempty"`
+	BlockIndex           *int    `json:"block_index,omitempty" validate:"omitempty,gte=0"`
+	BlockType            *string `json:"block_type,omitempty" validate:"omitempty,oneof=superset circuit"`
+	BlockRestTimeSeconds *int    `json:"block_rest_time_seconds,omitempty" validate:"omitempty,gte=0,lte=3600"`
+	ProgressionConfig
 }
 
 // UpdateRoutineRequest representa la solicitud para actualizar una rutina
@@ -63,6 +74,13 @@ type UpdateRoutineExerciseRequest struct {
 	Weight          *float64 `json:"weight,omitempty" validate:"omitempty,gt=0,lte=1000"`
 	RestTimeSeconds *int     `json:"rest_time_seconds,omitempty" validate:"omitempty,gte=0,lte=3600"`
 	Notes           *string  `json:"notes,omitempty"`
+	WeightUnit      *string  `json:"weight_unit,omitempty" validate:"omitempty,oneof=kg lb"` // Unidad de weight y weight_increment; por defecto la del usuario
+	// Bloque (superserie o circuito): el tipo y el descanso se cambian para todo el bloque
+	BlockIndex           *int    `json:"block_index,omitempty" validate:"omitempty,gte=0"`
+	BlockType            *string `json:"block_type,omitempty" validate:"omitempty,oneof=superset circuit"`
+	BlockRestTimeSeconds *int    `json:"block_rest_time_seconds,omitempty" validate:"omitempty,gte=0,lte=3600"`
+	LeaveBlock           bool    `json:"leave_block,omitempty"` // Saca el ejercicio de su bloque
+	ProgressionConfig
 }
 
 // RoutineWithExercises representa una rutina con sus ejercicios incluidos
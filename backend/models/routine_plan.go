@@ -0,0 +1,52 @@
+package models
+
+// PlannedSet representa una serie planificada de la rutina y, si ya se hizo, la serie registrada
+type PlannedSet struct {
+	Set              int      `json:"set"`
+	PlannedReps      int      `json:"planned_reps"`
+	PlannedWeight    *float64 `json:"planned_weight"`
+	Done             bool     `json:"done"`
+	Met              bool     `json:"met"` // Se alcanzaron las repeticiones y el peso planificados
+	WorkoutID        *int     `json:"workout_id,omitempty"`
+	PerformedReps    *int     `json:"performed_reps,omitempty"`
+	PerformedWeight  *float64 `json:"performed_weight,omitempty"`
+	PerformedSeconds *int     `json:"performed_seconds,omitempty"`
+}
+
+// ExercisePlan representa un ejercicio de la rutina con sus series planificadas y realizadas
+type ExercisePlan struct {
+	RoutineExerciseID int          `json:"routine_exercise_id"`
+	ExerciseID        int          `json:"exercise_id"`
+	ExerciseName      string       `json:"exercise_name"`
+	OrderIndex        int          `json:"order_index"`
+	RestTimeSeconds   int          `json:"rest_time_seconds"`
+	Notes             *string      `json:"notes"`
+	BlockIndex        *int         `json:"block_index"` // Ejercicios con el mismo block_index se hacen alternados
+	BlockType         *string      `json:"block_type"`
+	BlockRestSeconds  *int         `json:"block_rest_time_seconds"`
+	PlannedSets       int          `json:"planned_sets"`
+	PerformedSets     int          `json:"performed_sets"`
+	Sets              []PlannedSet `json:"sets"`
+	ExtraSets         []Workout    `json:"extra_sets"` // Series registradas por encima de lo planificado
+}
+
+// WorkoutDayPlan representa el checklist de una rutina iniciada en un día de entrenamiento
+type WorkoutDayPlan struct {
+	WorkoutDayID      int            `json:"workout_day_id"`
+	RoutineID         int            `json:"routine_id"`
+	RoutineName       string         `json:"routine_name"`
+	PlannedSets       int            `json:"planned_sets"`
+	CompletedSets     int            `json:"completed_sets"`
+	Progress          float64        `json:"progress"` // Series planificadas completadas (0-1)
+	WeightUnit        string         `json:"weight_unit"`
+	ProgramWeek       *ProgramWeek   `json:"program_week,omitempty"` // Ajustes de la semana si la sesión es de un programa
+	Exercises         []ExercisePlan `json:"exercises"`
+	UnplannedWorkouts []Workout      `json:"unplanned_workouts"` // Series de ejercicios que no están en la rutina
+}
+
+// StartRoutineRequest representa la solicitud para iniciar una rutina.
+// Sin date ni workout_day_id se usa el día de hoy.
+type StartRoutineRequest struct {
+	Date         *string `json:"date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	WorkoutDayID *int    `json:"workout_day_id,omitempty" validate:"omitempty,gt=0"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// Estado de una sesión agendada
+const (
+	ScheduledSessionDone     = "done"     // Se registraron series con la rutina ese día
+	ScheduledSessionDue      = "due"      // Toca hoy y todavía no se hizo
+	ScheduledSessionMissed   = "missed"   // Día pasado sin hacerla
+	ScheduledSessionUpcoming = "upcoming" // Día futuro
+)
+
+// RoutineSchedule representa una rutina agendada en un día de la semana
+type RoutineSchedule struct {
+	ID            int       `json:"id"`
+	RoutineID     int       `json:"routine_id"`
+	RoutineName   string    `json:"routine_name"`
+	Weekday       int       `json:"weekday"`        // 1 = lunes ... 7 = domingo
+	PreferredTime *string   `json:"preferred_time"` // Formato HH:MM, en la zona horaria del usuario
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// CreateRoutineScheduleRequest representa la solicitud para agendar una rutina en un día de la semana
+type CreateRoutineScheduleRequest struct {
+	RoutineID     int     `json:"routine_id" validate:"required,gt=0"`
+	Weekday       int     `json:"weekday" validate:"required,gte=1,lte=7"`
+	PreferredTime *string `json:"preferred_time,omitempty" validate:"omitempty,datetime=15:04"`
+}
+
+// UpdateRoutineScheduleRequest representa la solicitud para cambiar una rutina agendada.
+// preferred_time vacío borra la hora.
+type UpdateRoutineScheduleRequest struct {
+	RoutineID     *int    `json:"routine_id,omitempty" validate:"omitempty,gt=0"`
+	Weekday       *int    `json:"weekday,omitempty" validate:"omitempty,gte=1,lte=7"`
+	PreferredTime *string `json:"preferred_time,omitempty"`
+}
+
+// ScheduledSession representa una rutina agendada en una fecha concreta
+type ScheduledSession struct {
+	ScheduleID    int     `json:"schedule_id"`
+	Date          string  `json:"date"` // Formato YYYY-MM-DD
+	Weekday       int     `json:"weekday"`
+	RoutineID     int     `json:"routine_id"`
+	RoutineName   string  `json:"routine_name"`
+	PreferredTime *string `json:"preferred_time"`
+	Status        string  `json:"status"`
+	WorkoutDayID  *int    `json:"workout_day_id,omitempty"` // Día de entrenamiento en el que se inició la rutina
+}
+
+// DaySchedulePlan representa un día de la semana con sus rutinas agendadas
+type DaySchedulePlan struct {
+	Date     string             `json:"date"` // Formato YYYY-MM-DD
+	Weekday  int                `json:"weekday"`
+	Sessions []ScheduledSession `json:"sessions"`
+	// Día de entrenamiento con series que no corresponde a ninguna rutina agendada
+	UnscheduledWorkoutDayID *int `json:"unscheduled_workout_day_id,omitempty"`
+}
+
+// WeeklySchedulePlan compara lo agendado en una semana con lo que se hizo
+type WeeklySchedulePlan struct {
+	WeekStart string            `json:"week_start"` // Lunes, formato YYYY-MM-DD
+	WeekEnd   string            `json:"week_end"`
+	Planned   int               `json:"planned"`
+	Done      int               `json:"done"`
+	Missed    int               `json:"missed"`
+	Adherence *float64          `json:"adherence"` // Hechas sobre las que ya vencieron (0-1); nil si todavía no venció ninguna
+	Days      []DaySchedulePlan `json:"days"`
+}
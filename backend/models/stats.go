@@ -0,0 +1,52 @@
+package models
+
+// WeeklyStats representa la actividad de una semana (de lunes a domingo)
+type WeeklyStats struct {
+	WeekStart string  `json:"week_start"` // Formato YYYY-MM-DD (lunes)
+	Sessions  int     `json:"sessions"`
+	Sets      int     `json:"sets"`
+	Volume    float64 `json:"volume"` // Suma de peso × repeticiones
+	// Deportes y cardio
+	DistanceMeters  float64 `json:"distance_meters"`
+	DurationSeconds int     `json:"duration_seconds"`
+}
+
+// ExerciseStats representa cuánto se entrenó un ejercicio en el rango consultado
+type ExerciseStats struct {
+	ExerciseID   int     `json:"exercise_id"`
+	ExerciseName string  `json:"exercise_name"`
+	Sets         int     `json:"sets"`
+	Sessions     int     `json:"sessions"`
+	Volume       float64 `json:"volume"`
+}
+
+// MuscleGroupStats representa las series que trabajaron un grupo muscular
+type MuscleGroupStats struct {
+	MuscleGroupID   int    `json:"muscle_group_id"`
+	MuscleGroupName string `json:"muscle_group_name"`
+	PrimarySets     int    `json:"primary_sets"`
+	SecondarySets   int    `json:"secondary_sets"`
+	TotalSets       int    `json:"total_sets"`
+}
+
+// UserStats representa las estadísticas de entrenamiento de un usuario en un rango de fechas
+type UserStats struct {
+	From               string             `json:"from"`
+	To                 string             `json:"to"`
+	TotalWorkouts      int                `json:"total_workouts"` // Cantidad de series registradas
+	TotalSessions      int                `json:"total_sessions"` // Días con al menos una serie
+	WorkoutDays        int                `json:"workout_days"`
+	TotalVolume        float64            `json:"total_volume"`
+	TotalDistance      float64            `json:"total_distance_meters"`
+	TotalDuration      int                `json:"total_duration_seconds"` // Duración de deportes y cardio
+	WeightUnit         string             `json:"weight_unit"`            // Unidad de todos los volúmenes
+	AvgEffort          float64            `json:"avg_effort"`             // Solo días con esfuerzo cargado
+	AvgMood            float64            `json:"avg_mood"`               // Solo días con ánimo cargado
+	AvgSessionsPerWeek float64            `json:"avg_sessions_per_week"`
+	CurrentStreakWeeks int                `json:"current_streak_weeks"`
+	LongestStreakWeeks int                `json:"longest_streak_weeks"`
+	Weekly             []WeeklyStats      `json:"weekly"` // Hasta las últimas 104 semanas del rango
+	TopExercises       []ExerciseStats    `json:"top_exercises"`
+	MuscleGroups       []MuscleGroupStats `json:"muscle_groups"`
+	Sports             []SportStats       `json:"sports"`
+}
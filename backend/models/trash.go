@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// TrashedWorkoutDay representa un día de entrenamiento en la papelera, con sus series
+type TrashedWorkoutDay struct {
+	ID        int       `json:"id"`
+	Date      string    `json:"date"` // Formato YYYY-MM-DD
+	Name      string    `json:"name"`
+	Sets      int       `json:"sets"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"` // A partir de este momento ya no se puede restaurar
+}
+
+// TrashedWorkout representa una serie eliminada por separado de su día
+type TrashedWorkout struct {
+	ID           int       `json:"id"`
+	WorkoutDayID int       `json:"workout_day_id"`
+	Date         string    `json:"date"` // Formato YYYY-MM-DD
+	ExerciseID   int       `json:"exercise_id"`
+	ExerciseName string    `json:"exercise_name"`
+	Set          int       `json:"set"`
+	Weight       float64   `json:"weight"`
+	WeightUnit   string    `json:"weight_unit"`
+	Reps         int       `json:"reps"`
+	DeletedAt    time.Time `json:"deleted_at"`
+	PurgeAt      time.Time `json:"purge_at"`
+}
+
+// TrashedRoutine representa una rutina en la papelera
+type TrashedRoutine struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Exercises int       `json:"exercises"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// Trash representa la papelera del usuario. Lo eliminado se puede restaurar durante retention_days.
+type Trash struct {
+	RetentionDays int                 `json:"retention_days"`
+	WorkoutDays   []TrashedWorkoutDay `json:"workout_days"`
+	Workouts      []TrashedWorkout    `json:"workouts"`
+	Routines      []TrashedRoutine    `json:"routines"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// Unidades de peso. Los pesos se guardan siempre en kilos y se convierten
+// a la unidad preferida de cada usuario al responder.
+const (
+	WeightUnitKg = "kg"
+	WeightUnitLb = "lb"
+)
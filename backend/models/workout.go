@@ -2,6 +2,18 @@ package models
 
 import "time"
 
+// Tipos de serie
+const (
+	SetTypeWarmup  = "warmup"
+	SetTypeWorking = "working"
+	SetTypeDrop    = "drop"
+	SetTypeFailure = "failure"
+	SetTypeAMRAP   = "amrap"
+)
+
+// SetTypes son los tipos de serie válidos
+var SetTypes = []string{SetTypeWarmup, SetTypeWorking, SetTypeDrop, SetTypeFailure, SetTypeAMRAP}
+
 // WorkoutDay representa un día de entrenamiento
 type WorkoutDay struct {
 	ID        int       `json:"id" db:"id"`
@@ -10,6 +22,8 @@ type WorkoutDay struct {
 	Name      string    `json:"name" db:"name"`
 	Effort    int       `json:"effort" db:"effort"`
 	Mood      int       `json:"mood" db:"mood"`
+	Notes     *string   `json:"notes" db:"notes"`
+	RoutineID *int      `json:"routine_id" db:"routine_id"` // Rutina con la que se inició el día, si hay
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -21,34 +35,80 @@ type Workout struct {
 	WorkoutDayID int       `json:"workout_day_id" db:"workout_day_id"`
 	ExerciseID   int       `json:"exercise_id" db:"exercise_id"`
 	ExerciseName string    `json:"exercise_name" db:"exercise_name"`
-	Weight       float64   `json:"weight" db:"weight"`
+	Weight       float64   `json:"weight" db:"weight"` // Guardado en kg, se devuelve en weight_unit
+	WeightUnit   string    `json:"weight_unit,omitempty" db:"-"`
 	Reps         int       `json:"reps" db:"reps"`
 	Set          int       `json:"set" db:"set"`
 	Seconds      *int      `json:"seconds" db:"seconds"`
 	Observations string    `json:"observations" db:"observations"`
+	SetType      string    `json:"set_type" db:"set_type"`
+	RPE          *float64  `json:"rpe" db:"rpe"`           // Esfuerzo percibido de la serie (1-10)
+	RIR          *int      `json:"rir" db:"rir"`           // Repeticiones en reserva
+	BlockID      *int      `json:"block_id" db:"block_id"` // Superserie o circuito al que pertenece
 	IsSport      bool      `json:"is_sport" db:"is_sport"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	// Peso corporal al registrar la serie en ejercicios con peso corporal; la carga es bodyweight + weight.
+	// Se toma al crear la serie: un peso corporal cargado después para esa fecha no la modifica.
+	Bodyweight *float64 `json:"bodyweight,omitempty" db:"bodyweight_kg"`
+	CardioMetrics
+	// Calculados a partir de la distancia y la duración (seconds)
+	PaceSecondsPerKm *float64 `json:"pace_seconds_per_km,omitempty" db:"-"`
+	SpeedKmh         *float64 `json:"speed_kmh,omitempty" db:"-"`
+	// Récords personales logrados con esta serie (solo al crear o actualizar)
+	PersonalRecords []PersonalRecord `json:"personal_records,omitempty" db:"-"`
 }
 
 // CreateWorkoutRequest representa la solicitud para crear un workout
 type CreateWorkoutRequest struct {
 	ExerciseID   int      `json:"exercise_id" validate:"required,gt=0"`
 	Weight       *float64 `json:"weight" validate:"omitempty,gt=0"`
+	WeightUnit   *string  `json:"weight_unit,omitempty" validate:"omitempty,oneof=kg lb"` // Por defecto la unidad del usuario
 	Reps         *int     `json:"reps" validate:"omitempty,gt=0"`
-	Set          *int     `json:"set"`
+	Set          *int     `json:"set"` // Al actualizar, si el número está ocupado las demás series se corren
 	Seconds      *int     `json:"seconds" validate:"omitempty,gt=0"`
 	Observations string   `json:"observations"`
+	// Tipo de serie (warmup, working, drop, failure, amrap); por defecto working
+	SetType *string  `json:"set_type,omitempty" validate:"omitempty,oneof=warmup working drop failure amrap"`
+	RPE     *float64 `json:"rpe,omitempty" validate:"omitempty,min=1,max=10"`
+	RIR     *int     `json:"rir,omitempty" validate:"omitempty,min=0,max=10"`
+	// Bloque (superserie o circuito) del día; si no se envía se hereda de la serie anterior del mismo ejercicio
+	BlockID *int `json:"block_id,omitempty" validate:"omitempty,gt=0"`
+	// Distancia, desnivel, pulso y calorías: solo para ejercicios is_sport
+	CardioMetrics
+	// Opcionales: permiten registrar series en un día pasado (o futuro cercano).
+	// Si se envía workout_day_id tiene prioridad sobre date; si no se envía ninguno se usa hoy.
+	Date         *string `json:"date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	WorkoutDayID *int    `json:"workout_day_id,omitempty" validate:"omitempty,gt=0"`
+}
+
+// CreateWorkoutBatchRequest representa la solicitud para registrar varias series en una sola llamada.
+// Todas las series van al mismo día de entrenamiento, indicado a nivel del lote.
+type CreateWorkoutBatchRequest struct {
+	Date         *string                `json:"date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	WorkoutDayID *int                   `json:"workout_day_id,omitempty" validate:"omitempty,gt=0"`
+	WeightUnit   *string                `json:"weight_unit,omitempty" validate:"omitempty,oneof=kg lb"` // Unidad de las series que no indican una
+	Sets         []CreateWorkoutRequest `json:"sets" validate:"required,min=1,max=100,dive"`
+}
+
+// CreateWorkoutBatchResponse representa el resultado de un lote de series
+type CreateWorkoutBatchResponse struct {
+	WorkoutDayID int           `json:"workout_day_id"`
+	Workouts     []Workout     `json:"workouts"`
+	AssignedSets map[int][]int `json:"assigned_sets"` // exercise_id -> números de serie asignados
 }
 
-// UpdateWorkoutDayRequest representa la solicitud para actualizar un día de entrenamiento
+// UpdateWorkoutDayRequest representa la solicitud para actualizar un día de entrenamiento.
+// Los campos que no se envían no se modifican.
 type UpdateWorkoutDayRequest struct {
-	Name   string `json:"name"`
-	Effort int    `json:"effort" validate:"min=0,max=10"`
-	Mood   int    `json:"mood" validate:"min=0,max=10"`
+	Name   *string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Effort *int    `json:"effort,omitempty" validate:"omitempty,min=0,max=3"`
+	Mood   *int    `json:"mood,omitempty" validate:"omitempty,min=0,max=3"`
+	Notes  *string `json:"notes,omitempty" validate:"omitempty,max=2000"`
 }
 
 // ExerciseGroup representa un grupo de ejercicios del mismo tipo
 type ExerciseGroup struct {
+	ExerciseID   int       `json:"exercise_id"`
 	ExerciseName string    `json:"exercise_name"`
 	Workouts     []Workout `json:"workouts"`
 }
@@ -57,5 +117,23 @@ type ExerciseGroup struct {
 type WorkoutDayWithExercises struct {
 	WorkoutDay     WorkoutDay      `json:"workout_day"`
 	ExerciseGroups []ExerciseGroup `json:"exercise_groups"`
+	Sets           []Workout       `json:"sets"`   // Todas las series en el orden en que se hicieron
+	Blocks         []WorkoutBlock  `json:"blocks"` // Superseries y circuitos del día
 	TotalWorkouts  int             `json:"total_workouts"`
+	Plan           *WorkoutDayPlan `json:"plan,omitempty"` // Planificado vs. realizado si el día viene de una rutina
+	WeightUnit     string          `json:"weight_unit"`
+}
+
+// WorkoutDaysPage representa una página de días de entrenamiento con sus series, de los más recientes
+// a los más antiguos. Los bloques y el plan de rutina de cada día están en GET /workout-days/{id}.
+type WorkoutDaysPage struct {
+	Days       []WorkoutDayWithExercises `json:"days"`
+	WeightUnit string                    `json:"weight_unit"`
+	NextCursor *string                   `json:"next_cursor"` // null si no hay más días
+}
+
+// ReorderSetsRequest representa el nuevo orden de las series de un ejercicio en un día.
+// Debe incluir todas sus series; quedan numeradas 1..n en ese orden.
+type ReorderSetsRequest struct {
+	WorkoutIDs []int `json:"workout_ids" validate:"required,min=1"`
 }
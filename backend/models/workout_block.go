@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Tipos de bloque
+const (
+	BlockTypeSuperset = "superset"
+	BlockTypeCircuit  = "circuit"
+)
+
+// WorkoutBlock representa una superserie o circuito dentro de un día de entrenamiento
+type WorkoutBlock struct {
+	ID              int       `json:"id" db:"id"`
+	UserID          string    `json:"user_id" db:"user_id"`
+	WorkoutDayID    int       `json:"workout_day_id" db:"workout_day_id"`
+	BlockType       string    `json:"block_type" db:"block_type"`
+	OrderIndex      int       `json:"order_index" db:"order_index"`
+	RestTimeSeconds *int      `json:"rest_time_seconds" db:"rest_time_seconds"` // Descanso al terminar cada vuelta
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	ExerciseIDs     []int     `json:"exercise_ids"`
+	Workouts        []Workout `json:"workouts"` // En el orden en que se hicieron
+	WeightUnit      string    `json:"weight_unit,omitempty"`
+}
+
+// CreateWorkoutBlockRequest representa la solicitud para crear un bloque en un día.
+// WorkoutIDs permite agrupar series ya registradas.
+type CreateWorkoutBlockRequest struct {
+	BlockType       string `json:"block_type" validate:"omitempty,oneof=superset circuit"`
+	OrderIndex      *int   `json:"order_index,omitempty" validate:"omitempty,gte=0"`
+	RestTimeSeconds *int   `json:"rest_time_seconds,omitempty" validate:"omitempty,gte=0,lte=3600"`
+	WorkoutIDs      []int  `json:"workout_ids,omitempty"`
+}
+
+// UpdateWorkoutBlockRequest representa la solicitud para actualizar un bloque.
+// Si se envía WorkoutIDs reemplaza las series del bloque.
+type UpdateWorkoutBlockRequest struct {
+	BlockType       *string `json:"block_type,omitempty" validate:"omitempty,oneof=superset circuit"`
+	OrderIndex      *int    `json:"order_index,omitempty" validate:"omitempty,gte=0"`
+	RestTimeSeconds *int    `json:"rest_time_seconds,omitempty" validate:"omitempty,gte=0,lte=3600"`
+	WorkoutIDs      *[]int  `json:"workout_ids,omitempty"`
+}